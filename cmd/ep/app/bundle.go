@@ -0,0 +1,151 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	"github.com/intel/edge-conductor/pkg/eputils/bundleutils"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	bundleImportDir = "bundle-import"
+)
+
+var (
+	bundleKey  string
+	bundleFile string
+)
+
+// ep_bundle_export collects the images and files of the cluster and the
+// services into a bundle and signs it with keyFile.
+func ep_bundle_export(keyFile, bundleFile string) error {
+	epParams, err := EpWfPreInit(nil, nil)
+	if err != nil {
+		log.Errorln("Failed to init workflow:", err)
+		return err
+	}
+	if err := EpwfLoadServices(epParams); err != nil {
+		log.Errorln("Failed to load services:", err)
+		return err
+	}
+	defer func() {
+		epparams_runtime_file, err := FileNameofRuntime(fnRuntimeInitParams)
+		if err != nil {
+			log.Errorln("Failed to get runtime file path:", err)
+		}
+		err = EpWfTearDown(epParams, epparams_runtime_file)
+		if err != nil {
+			log.Errorln("Workflow Tear Down Error:", err)
+		}
+	}()
+
+	if err := EpWfStart(epParams, "bundle-export"); err != nil {
+		log.Errorln("Failed to start workflow:", err)
+		return err
+	}
+
+	dir := bundleutils.StagingDir(epParams.Runtimedir)
+	if err := bundleutils.Pack(dir, keyFile, bundleFile); err != nil {
+		log.Errorln("Failed to pack bundle:", err)
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		log.Warnln("Failed to remove", dir, err)
+	}
+	log.Infoln("Bundle saved to", bundleFile)
+	return nil
+}
+
+// ep_bundle_import verifies a bundle with keyFile, and loads its images and
+// files into the day-0 registry and file repo.
+func ep_bundle_import(bundleFile, keyFile string) error {
+	epParams, err := EpWfPreInit(nil, nil)
+	if err != nil {
+		log.Errorln("Failed to init workflow:", err)
+		return err
+	}
+
+	dir := filepath.Join(epParams.Runtimedir, bundleImportDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnln("Failed to remove", dir, err)
+		}
+	}()
+
+	manifest, err := bundleutils.Unpack(bundleFile, keyFile, dir)
+	if err != nil {
+		log.Errorln("Failed to verify bundle:", err)
+		return err
+	}
+	if err := bundleutils.Import(dir, manifest, epParams); err != nil {
+		log.Errorln("Failed to import bundle:", err)
+		return err
+	}
+	log.Infof("Imported %d images and %d files", len(manifest.Images), len(manifest.Files))
+	return nil
+}
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Offline bundle operations.",
+	Long:  `Export the images and files of the cluster and services into a signed bundle, and import it on a day-0 host without internet access.`,
+}
+
+var exportBundleCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export an offline bundle.",
+	Long: `Parse the cluster and the services, download their images and files, and pack them into a tarball with a manifest of their SHA256 digests.
+The manifest is signed with the private key given by --key.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Infoln(PROJECTNAME, "- Export Bundle")
+		log.Infoln("==")
+		if err := ep_bundle_export(bundleKey, bundleFile); err != nil {
+			return err
+		}
+		log.Infoln("==")
+		log.Infoln("Done")
+		return nil
+	},
+}
+
+var importBundleCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Import an offline bundle.",
+	Long: `Verify the signature and the digests of a bundle with the public key or certificate given by --key.
+Then load its images into the day-0 registry and push its files to the day-0 file repo, so that "cluster build" and "service build" do not need internet access.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		log.Infoln(PROJECTNAME, "- Import Bundle")
+		log.Infoln("==")
+		if err := ep_bundle_import(args[0], bundleKey); err != nil {
+			return err
+		}
+		log.Infoln("==")
+		log.Infoln("Done")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.AddCommand(exportBundleCmd)
+	bundleCmd.AddCommand(importBundleCmd)
+	bundleCmd.PersistentFlags().StringVar(&bundleKey, "key", "", "PEM key file to sign (export) or verify (import) the bundle")
+	exportBundleCmd.Flags().StringVarP(&bundleFile, "file", "f", "bundle.tar.gz", "bundle file to create")
+	if err := bundleCmd.MarkPersistentFlagRequired("key"); err != nil {
+		log.Error(err)
+	}
+}
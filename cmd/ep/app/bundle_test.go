@@ -0,0 +1,214 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	epapi "github.com/intel/edge-conductor/pkg/api/ep"
+	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/intel/edge-conductor/pkg/eputils/bundleutils"
+	"path/filepath"
+	"testing"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+func patchBundlePack(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(bundleutils.Pack, func(dir, keyFile, bundleFile string) error {
+		return err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchBundleUnpack(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(bundleutils.Unpack, func(bundleFile, keyFile, dir string) (*epapi.Bundlemanifest, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &epapi.Bundlemanifest{}, nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchBundleImport(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(bundleutils.Import, func(dir string, manifest *epapi.Bundlemanifest, epParams *epapiplugins.EpParams) error {
+		return err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func TestEpBundleExport(t *testing.T) {
+	cases := []struct {
+		name           string
+		wantError      error
+		funcBeforeTest func() []*mpatch.Patch
+	}{
+		{
+			name:      "EpWfPreInit fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, nil, testError)}
+			},
+		},
+		{
+			name:      "EpwfLoadServices fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchEpWfLoadServices(t, testError),
+				}
+			},
+		},
+		{
+			name:      "EpWfStart fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchEpWfLoadServices(t, nil),
+					patchEpWfTearDown(t, nil),
+					patchEpWfStart(t, testError),
+				}
+			},
+		},
+		{
+			name:      "Pack fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchEpWfLoadServices(t, nil),
+					patchEpWfTearDown(t, nil),
+					patchEpWfStart(t, nil),
+					patchBundlePack(t, testError),
+				}
+			},
+		},
+		{
+			name: "success",
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{Runtimedir: t.TempDir()}, nil),
+					patchEpWfLoadServices(t, nil),
+					patchEpWfTearDown(t, nil),
+					patchEpWfStart(t, nil),
+					patchBundlePack(t, nil),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := tc.funcBeforeTest()
+			defer unpatchAll(t, pList)
+
+			err := ep_bundle_export("key.pem", "bundle.tar.gz")
+			if !isWantedError(err, tc.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestExportBundleFlags(t *testing.T) {
+	defer func(format, file, key string) {
+		outputFormat, bundleFile, bundleKey = format, file, key
+	}(outputFormat, bundleFile, bundleKey)
+
+	// The bundle file flag must not shadow the global output format flag.
+	if err := exportBundleCmd.ParseFlags([]string{"-o", OutputJson, "-f", "offline.tar.gz", "--key", "key.pem"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if outputFormat != OutputJson {
+		t.Errorf("Unexpected output format: %s", outputFormat)
+	}
+	if bundleFile != "offline.tar.gz" {
+		t.Errorf("Unexpected bundle file: %s", bundleFile)
+	}
+	if bundleKey != "key.pem" {
+		t.Errorf("Unexpected key: %s", bundleKey)
+	}
+}
+
+func TestEpBundleImport(t *testing.T) {
+	runtimedir := t.TempDir()
+
+	cases := []struct {
+		name           string
+		wantError      error
+		funcBeforeTest func() []*mpatch.Patch
+	}{
+		{
+			name:      "EpWfPreInit fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, nil, testError)}
+			},
+		},
+		{
+			name:      "Unpack fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{Runtimedir: runtimedir}, nil),
+					patchBundleUnpack(t, testError),
+				}
+			},
+		},
+		{
+			name:      "Import fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{Runtimedir: runtimedir}, nil),
+					patchBundleUnpack(t, nil),
+					patchBundleImport(t, testError),
+				}
+			},
+		},
+		{
+			name: "success",
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{Runtimedir: runtimedir}, nil),
+					patchBundleUnpack(t, nil),
+					patchBundleImport(t, nil),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := tc.funcBeforeTest()
+			defer unpatchAll(t, pList)
+
+			err := ep_bundle_import("bundle.tar.gz", "key.pub")
+			if !isWantedError(err, tc.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if eputils.FileExists(filepath.Join(runtimedir, bundleImportDir)) {
+				t.Errorf("Import dir is not removed")
+			}
+		})
+	}
+}
@@ -36,7 +36,10 @@ func copyCaRuntimeDataDir(registry string, workspace string, runtimedir string,
 	}
 
 	rootCertTgt := filepath.Join(runtimeDataCertRegistry, "ca.crt")
-	rootCertSrc := filepath.Join(workspace, certpath)
+	rootCertSrc := certpath
+	if !filepath.IsAbs(certpath) {
+		rootCertSrc = filepath.Join(workspace, certpath)
+	}
 
 	_, err := eputils.CopyFile(rootCertTgt, rootCertSrc)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 
 var (
 	forceDownload           bool
+	resumeWorkflow          bool
 	clusterKubeConfig       string
 	clusterExportKubeConfig string
 )
@@ -191,6 +192,7 @@ func init() {
 	clusterCmd.AddCommand(getClusterInfoCmd)
 	clusterCmd.PersistentFlags().StringVar(&clusterExportKubeConfig, "export-kubeconfig", GetDefaultKubeConfig(), "export kubeconfig file path")
 	clusterCmd.PersistentFlags().StringVar(&clusterKubeConfig, "kubeconfig", GetDefaultKubeConfig(), "kubeconfig file path")
+	clusterCmd.PersistentFlags().BoolVar(&resumeWorkflow, "resume", false, "skip the steps completed in the previous run if their inputs are not changed")
 	clusterCmd.AddCommand(joinClusterCmd)
 
 	buildClusterCmd.PersistentFlags().BoolVarP(&forceDownload, "force-download", "f", false, "download images with always policy")
@@ -33,4 +33,6 @@ const (
 	WfConfig                   = "workflow/workflow.yml"
 	KitConfigPath              = "kit/kind.yml"
 	ROOTCACERTFILE             = "cert/pki/ca.pem"
+	OutputText                 = "text"
+	OutputJson                 = "json"
 )
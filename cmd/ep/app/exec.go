@@ -0,0 +1,57 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	"fmt"
+	"github.com/intel/edge-conductor/pkg/executor"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var execDryRun bool
+
+// ep_exec runs an executor spec on the nodes of the kit config, or prints
+// its plan if dryRun is set.
+func ep_exec(spec string, dryRun bool) error {
+	epParams, err := EpWfPreInit(nil, nil)
+	if err != nil {
+		log.Errorln("Failed to init workflow:", err)
+		return err
+	}
+	if dryRun {
+		plan, err := executor.Plan(spec, epParams, nil)
+		if err != nil {
+			return err
+		}
+		fmt.Print(plan)
+		return nil
+	}
+	return executor.Run(spec, epParams, nil)
+}
+
+var execCmd = &cobra.Command{
+	Use:   "exec <spec>",
+	Short: "Run an executor spec.",
+	Long: `Run an executor spec on the nodes of the kit config.
+With --dry-run, print the nodes selected by each step and each command rendered for each node, without connecting to the nodes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ep_exec(args[0], execDryRun); err != nil {
+			log.Errorln("Failed to run executor spec:", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().BoolVar(&execDryRun, "dry-run", false, "Print the plan of the spec without running it")
+}
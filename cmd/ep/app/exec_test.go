@@ -0,0 +1,99 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/intel/edge-conductor/pkg/executor"
+	"testing"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+func patchExecutorPlan(t *testing.T, plan string, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(executor.Plan, func(specFile string, epparams *epapiplugins.EpParams, value interface{}) (string, error) {
+		return plan, err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchExecutorRun(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(executor.Run, func(specFile string, epparams *epapiplugins.EpParams, value interface{}) error {
+		return err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func TestEpExec(t *testing.T) {
+	cases := []struct {
+		name           string
+		dryRun         bool
+		wantError      error
+		funcBeforeTest func() []*mpatch.Patch
+	}{
+		{
+			name:      "EpWfPreInit fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, nil, testError)}
+			},
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchExecutorPlan(t, "Step test\n", nil),
+					patchExecutorRun(t, testError),
+				}
+			},
+		},
+		{
+			name:      "dry run fail",
+			dryRun:    true,
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchExecutorPlan(t, "", testError),
+				}
+			},
+		},
+		{
+			name:      "run",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchExecutorRun(t, testError),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := tc.funcBeforeTest()
+			defer unpatchAll(t, pList)
+
+			err := ep_exec("spec.yml", tc.dryRun)
+			if !isWantedError(err, tc.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -8,7 +8,6 @@
 package app
 
 import (
-	"fmt"
 	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
 	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	certmgr "github.com/intel/edge-conductor/pkg/certmgr"
@@ -79,6 +78,9 @@ func check_init_cmd() error {
 	}
 	if kitcfg.Parameters != nil {
 		files = append(files, kitcfg.Parameters.DefaultSSHKeyPath)
+		if kitcfg.Parameters.Bastion != nil {
+			files = append(files, kitcfg.Parameters.Bastion.SSHKeyPath)
+		}
 	}
 
 	for _, file := range files {
@@ -213,8 +215,13 @@ func ep_init() error {
 		return err
 	}
 
-	registry := fmt.Sprintf("%s:%s", epparams.Kitconfig.Parameters.GlobalSettings.ProviderIP, epparams.Kitconfig.Parameters.GlobalSettings.RegistryPort)
-	if err = copyCaRuntimeDataDir(registry, epparams.Workspace, epparams.Runtimedata, epparams.Registrycert.Ca.Cert); err != nil {
+	registry := eputils.RegistryAddress(epparams.Kitconfig)
+	cacert := epparams.Registrycert.Ca.Cert
+	if eputils.RegistryType(epparams.Kitconfig) == epapiplugins.CustomconfigRegistryTypeExternal &&
+		epparams.Kitconfig.Parameters.Customconfig.Registry.Capath != "" {
+		cacert = epparams.Kitconfig.Parameters.Customconfig.Registry.Capath
+	}
+	if err = copyCaRuntimeDataDir(registry, epparams.Workspace, epparams.Runtimedata, cacert); err != nil {
 		log.Errorln("Failed to copy CA:", err)
 		return err
 	}
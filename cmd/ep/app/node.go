@@ -0,0 +1,94 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	"fmt"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+// ep_node_trust scans the host keys of the nodes selected by name or IP, or
+// of all the nodes of the kit config, and replaces their keys in the
+// known_hosts file of the conductor.
+func ep_node_trust(names []string) error {
+	epParams, err := EpWfPreInit(nil, nil)
+	if err != nil {
+		log.Errorln("Failed to init workflow:", err)
+		return err
+	}
+	selected := map[string]bool{}
+	for _, name := range names {
+		selected[name] = false
+	}
+	for _, n := range epParams.Kitconfig.Parameters.Nodes {
+		if len(names) > 0 {
+			if _, has := selected[n.Name]; has {
+				selected[n.Name] = true
+			} else if _, has := selected[n.IP]; has {
+				selected[n.IP] = true
+			} else {
+				continue
+			}
+		}
+		port := n.SSHPort
+		if port == 0 {
+			port = 22
+		}
+		addr := fmt.Sprintf("%s:%d", n.IP, port)
+		key, err := eputils.ScanHostKey(addr)
+		if err != nil {
+			log.Errorf("Failed to get the host key of %s: %v", addr, err)
+			return err
+		}
+		fingerprint := ssh.FingerprintSHA256(key)
+		if n.SSHHostKeyFingerprint != "" && n.SSHHostKeyFingerprint != fingerprint {
+			log.Errorf("Host key of %s is %s, update the pinned fingerprint %s in the kit config", addr, fingerprint, n.SSHHostKeyFingerprint)
+			return eputils.GetError("errHostKeyMismatch")
+		}
+		if err := eputils.TrustHostKey(addr, key); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s %s\n", n.Name, addr, fingerprint)
+	}
+	for name, found := range selected {
+		if !found {
+			log.Errorf("Node %s is not found", name)
+			return eputils.GetError("errNodeNotFound")
+		}
+	}
+	return nil
+}
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Node operations.",
+	Long:  `Node operations.`,
+}
+
+var trustNodeCmd = &cobra.Command{
+	Use:   "trust [<node name or IP>...]",
+	Short: "Accept the SSH host keys of nodes.",
+	Long: `Accept the current SSH host keys of the given nodes, or of all the nodes of the kit config.
+The keys replace the previous keys of the nodes in the known_hosts file of the conductor, e.g. after the nodes are reprovisioned.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ep_node_trust(args); err != nil {
+			log.Errorln("Failed to trust node:", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	nodeCmd.AddCommand(trustNodeCmd)
+}
@@ -0,0 +1,143 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"testing"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+	"golang.org/x/crypto/ssh"
+)
+
+func patchScanHostKey(t *testing.T, key ssh.PublicKey, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(eputils.ScanHostKey, func(address string) (ssh.PublicKey, error) {
+		return key, err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchTrustHostKey(t *testing.T, trusted map[string]bool) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(eputils.TrustHostKey, func(address string, key ssh.PublicKey) error {
+		trusted[address] = true
+		return nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func TestEpNodeTrust(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epParams := &epapiplugins.EpParams{
+		Kitconfig: &epapiplugins.Kitconfig{
+			Parameters: &epapiplugins.KitconfigParameters{
+				Nodes: []*epapiplugins.Node{
+					{Name: "node-1", IP: "10.0.0.1"},
+					{Name: "node-2", IP: "10.0.0.2", SSHPort: 2222},
+					{Name: "node-3", IP: "10.0.0.3", SSHHostKeyFingerprint: "SHA256:pinned"},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name           string
+		args           []string
+		wantError      error
+		wantTrusted    []string
+		funcBeforeTest func() []*mpatch.Patch
+	}{
+		{
+			name:      "EpWfPreInit fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, nil, testError)}
+			},
+		},
+		{
+			name:      "unknown node",
+			args:      []string{"node-4"},
+			wantError: eputils.GetError("errNodeNotFound"),
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, epParams, nil)}
+			},
+		},
+		{
+			name:      "scan fail",
+			args:      []string{"node-1"},
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, epParams, nil),
+					patchScanHostKey(t, nil, testError),
+				}
+			},
+		},
+		{
+			name:      "pinned fingerprint mismatch",
+			args:      []string{"node-3"},
+			wantError: eputils.GetError("errHostKeyMismatch"),
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, epParams, nil),
+					patchScanHostKey(t, key, nil),
+				}
+			},
+		},
+		{
+			name:        "trust by name and IP",
+			args:        []string{"node-1", "10.0.0.2"},
+			wantTrusted: []string{"10.0.0.1:22", "10.0.0.2:2222"},
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, epParams, nil),
+					patchScanHostKey(t, key, nil),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := tc.funcBeforeTest()
+			trusted := map[string]bool{}
+			pList = append(pList, patchTrustHostKey(t, trusted))
+			defer unpatchAll(t, pList)
+
+			err := ep_node_trust(tc.args)
+			if !isWantedError(err, tc.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if len(trusted) != len(tc.wantTrusted) {
+				t.Errorf("Unexpected trusted nodes: %v", trusted)
+			}
+			for _, addr := range tc.wantTrusted {
+				if !trusted[addr] {
+					t.Errorf("%s is not trusted", addr)
+				}
+			}
+		})
+	}
+}
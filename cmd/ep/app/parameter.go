@@ -252,28 +252,53 @@ func setupCustomConfig(kitcfgPath string, epp *epapiplugins.EpParams) error {
 	// Load Customconfig from user config file.
 	epp.Kitconfig.Parameters.Customconfig = kitcfg.Parameters.Customconfig
 	ctmcfg := epp.Kitconfig.Parameters.Customconfig
-	needUserPassword := false
-	if ctmcfg == nil {
-		needUserPassword = true
-	} else {
-		if ctmcfg.Registry != nil {
-			if ctmcfg.Registry.Externalurl == "" {
-				// Harbor bootstrap user is admin
-				if ctmcfg.Registry.User != "admin" {
-					needUserPassword = true
-				}
-				if ctmcfg.Registry.Password == "" {
-					needUserPassword = true
-				}
-			}
-		} else {
-			needUserPassword = true
-		}
-	}
-	if needUserPassword {
+	if ctmcfg == nil || ctmcfg.Registry == nil {
 		log.Error("Please provide a registry password with admin user via custom config file, check doc for more detail.")
 		return eputils.GetError("errRegistryPw")
 	}
+	if err := checkRegistryConfig(epp.Kitconfig); err != nil {
+		return err
+	}
+	return checkImageVerificationConfig(ctmcfg.ImageVerification)
+}
+
+// checkImageVerificationConfig checks that keys are given to verify the
+// image signatures.
+func checkImageVerificationConfig(conf *epapiplugins.CustomconfigImageVerification) error {
+	if conf == nil || conf.Signature == "" || conf.Signature == epapiplugins.CustomconfigImageVerificationSignatureNone {
+		return nil
+	}
+	if len(conf.Keys) == 0 {
+		log.Errorf("Please provide the keys to verify %s signatures of images via custom config file, check doc for more detail.", conf.Signature)
+		return eputils.GetError("errImageVerifyKey")
+	}
+	return nil
+}
+
+// checkRegistryConfig checks that the custom config has the settings that
+// the type of the day-0 registry needs.
+func checkRegistryConfig(kitcfg *epapiplugins.Kitconfig) error {
+	registry := kitcfg.Parameters.Customconfig.Registry
+	switch eputils.RegistryType(kitcfg) {
+	case epapiplugins.CustomconfigRegistryTypeHarbor:
+		// Harbor bootstrap user is admin
+		if registry.User != "admin" || registry.Password == "" {
+			log.Error("Please provide a registry password with admin user via custom config file, check doc for more detail.")
+			return eputils.GetError("errRegistryPw")
+		}
+	case epapiplugins.CustomconfigRegistryTypeRegistry:
+		if registry.User == "" || registry.Password == "" {
+			log.Error("Please provide a registry user and password via custom config file, check doc for more detail.")
+			return eputils.GetError("errRegistryPw")
+		}
+	case epapiplugins.CustomconfigRegistryTypeExternal:
+		if eputils.RegistryAddress(kitcfg) == "" {
+			log.Error("Please provide the URL of the external registry via custom config file, check doc for more detail.")
+			return eputils.GetError("errRegistryUrl")
+		}
+	default:
+		return eputils.GetError("errRegistryType")
+	}
 	return nil
 }
 
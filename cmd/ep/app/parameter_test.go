@@ -248,6 +248,66 @@ func Test_setupcustomconfig(t *testing.T) {
 				patchisnotexist(t, false)
 			},
 		},
+		{
+			name:          "registry_with_password",
+			expectError:   nil,
+			in_kitcfgPath: getrealpath("ctmcfg_registry.yml"),
+			in_epp:        getepparams(t, "epparams_withadminpass.json"),
+			beforetest: func() {
+				patchOsStat(t, nil, nil)
+				patchisnotexist(t, false)
+			},
+		},
+		{
+			name:          "registry_without_password",
+			expectError:   eputils.GetError("errRegistryPw"),
+			in_kitcfgPath: getrealpath("ctmcfg_registry_nopass.yml"),
+			in_epp:        getepparams(t, "epparams_withadminpass.json"),
+			beforetest: func() {
+				patchOsStat(t, nil, nil)
+				patchisnotexist(t, false)
+			},
+		},
+		{
+			name:          "external_registry",
+			expectError:   nil,
+			in_kitcfgPath: getrealpath("ctmcfg_external.yml"),
+			in_epp:        getepparams(t, "epparams_withadminpass.json"),
+			beforetest: func() {
+				patchOsStat(t, nil, nil)
+				patchisnotexist(t, false)
+			},
+		},
+		{
+			name:          "image_verification",
+			expectError:   nil,
+			in_kitcfgPath: getrealpath("ctmcfg_verify.yml"),
+			in_epp:        getepparams(t, "epparams_withadminpass.json"),
+			beforetest: func() {
+				patchOsStat(t, nil, nil)
+				patchisnotexist(t, false)
+			},
+		},
+		{
+			name:          "image_verification_without_keys",
+			expectError:   eputils.GetError("errImageVerifyKey"),
+			in_kitcfgPath: getrealpath("ctmcfg_verify_nokeys.yml"),
+			in_epp:        getepparams(t, "epparams_withadminpass.json"),
+			beforetest: func() {
+				patchOsStat(t, nil, nil)
+				patchisnotexist(t, false)
+			},
+		},
+		{
+			name:          "external_registry_without_url",
+			expectError:   eputils.GetError("errRegistryUrl"),
+			in_kitcfgPath: getrealpath("ctmcfg_external_nourl.yml"),
+			in_epp:        getepparams(t, "epparams_withadminpass.json"),
+			beforetest: func() {
+				patchOsStat(t, nil, nil)
+				patchisnotexist(t, false)
+			},
+		},
 		{
 			name:          "testchant1",
 			expectError:   nil,
@@ -8,12 +8,14 @@
 package app
 
 import (
+	wf "github.com/intel/edge-conductor/pkg/workflow"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"os"
 )
 
 var verbose bool
+var outputFormat string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -34,7 +36,14 @@ func InitConfig() {
 	} else {
 		log.SetLevel(log.InfoLevel)
 	}
-	log.SetOutput(os.Stdout)
+	if outputFormat == OutputJson {
+		// Keep stdout for the JSON report of the workflow run.
+		log.SetOutput(os.Stderr)
+		wf.LogOutput = os.Stderr
+	} else {
+		log.SetOutput(os.Stdout)
+		wf.LogOutput = os.Stdout
+	}
 	log.SetFormatter(&log.TextFormatter{
 		DisableColors: false,
 		FullTimestamp: false,
@@ -50,6 +59,7 @@ func init() {
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", OutputText, "Output format of workflow runs: text or json")
 
 	cobra.OnInitialize(InitConfig)
 }
@@ -8,6 +8,8 @@
 package app
 
 import (
+	wf "github.com/intel/edge-conductor/pkg/workflow"
+	"os"
 	"reflect"
 	"testing"
 
@@ -46,4 +48,15 @@ func TestInitConfig(t *testing.T) {
 	if log.GetLevel() != log.InfoLevel {
 		t.Errorf("TestInitConfig failed, expected Log InfoLevel\r\n")
 	}
+
+	outputFormat = OutputJson
+	InitConfig()
+	if wf.LogOutput != os.Stderr {
+		t.Errorf("TestInitConfig failed, expected plugin logs on stderr\r\n")
+	}
+	outputFormat = OutputText
+	InitConfig()
+	if wf.LogOutput != os.Stdout {
+		t.Errorf("TestInitConfig failed, expected plugin logs on stdout\r\n")
+	}
 }
@@ -154,6 +154,7 @@ func init() {
 	serviceCmd.AddCommand(deployServiceCmd)
 	serviceCmd.AddCommand(listServiceCmd)
 	serviceCmd.PersistentFlags().StringVar(&serviceKubeConfig, "kubeconfig", GetDefaultKubeConfig(), "kubeconfig file path")
+	serviceCmd.PersistentFlags().BoolVar(&resumeWorkflow, "resume", false, "skip the steps completed in the previous run if their inputs are not changed")
 
 	buildServiceCmd.PersistentFlags().BoolVarP(&forceDownload, "force-download", "f", false, "download images with always policy")
 }
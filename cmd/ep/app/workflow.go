@@ -7,26 +7,45 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
-	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
 	orasutils "github.com/intel/edge-conductor/pkg/eputils/orasutils"
+	registryutils "github.com/intel/edge-conductor/pkg/eputils/registryutils"
 	plugin "github.com/intel/edge-conductor/pkg/plugin"
 	wf "github.com/intel/edge-conductor/pkg/workflow"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
 	log "github.com/sirupsen/logrus"
 )
 
+func printWfReport(name string) error {
+	report, err := ioutil.ReadFile(wf.ReportFilePath(name))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(report))
+	return nil
+}
+
 func EpWfStart(epParams *epapiplugins.EpParams, name string) error {
+	if outputFormat != OutputText && outputFormat != OutputJson {
+		log.Errorf("Unsupported output format %s", outputFormat)
+		return eputils.GetError("errOutputFormat")
+	}
 	kitcfg := GetRuntimeTopConfig(epParams)
 	if kitcfg == nil {
 		return eputils.GetError("errKitConfig")
 	}
 	address := fmt.Sprintf("%s:%s", kitcfg.Parameters.GlobalSettings.ProviderIP, kitcfg.Parameters.GlobalSettings.WorkflowPort)
 	plugin.Address = address
+	wf.Resume = resumeWorkflow
 
 	addonbin := "addon/bin/conductor-plugin"
 
@@ -37,26 +56,43 @@ func EpWfStart(epParams *epapiplugins.EpParams, name string) error {
 		logLevel = "Info"
 	}
 
+	// Ctrl-C or SIGTERM cancels the workflow, which stops the plugins and
+	// writes a partial report.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Restore the default signal handling once the first signal arrives,
+	// so that a second Ctrl-C kills the ep immediately.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	var err error
 	if eputils.FileExists(addonbin) {
 
 		finished := make(chan bool)
 		go func() {
 
-			cmd := exec.Command(addonbin, address, logLevel)
+			cmd := exec.CommandContext(ctx, addonbin, address, logLevel)
 			_, err := eputils.RunCMDEx(cmd, true)
 			if err != nil {
 				log.Errorf("Failed to run addon %s on %s", addonbin, address)
 			}
 			finished <- true
 		}()
-		err := wf.Start(name, address, WfConfig)
+		err = wf.Start(ctx, name, address, WfConfig)
 		<-finished
-		return err
 
 	} else {
-		return wf.Start(name, address, WfConfig)
+		err = wf.Start(ctx, name, address, WfConfig)
 
 	}
+	if outputFormat == OutputJson {
+		if rerr := printWfReport(name); rerr != nil {
+			log.Warningf("Failed to print the report of workflow %s: %v", name, rerr)
+		}
+	}
+	return err
 }
 
 func setHostIptoNoProxy(input_ep_params *epapiplugins.EpParams) error {
@@ -74,22 +110,23 @@ func setHostIptoNoProxy(input_ep_params *epapiplugins.EpParams) error {
 }
 
 func EpUtilsInit(epParams *epapiplugins.EpParams) error {
-	regcacert := epParams.Registrycert.Ca.Cert
-	auth, err := docker.GetAuthConf(epParams.Kitconfig.Parameters.GlobalSettings.ProviderIP,
-		epParams.Kitconfig.Parameters.GlobalSettings.RegistryPort,
-		epParams.Kitconfig.Parameters.Customconfig.Registry.User,
-		epParams.Kitconfig.Parameters.Customconfig.Registry.Password)
+	reg, err := registryutils.NewRegistry(epParams)
 	if err != nil {
 		return err
 	}
 
-	err = orasutils.OrasNewClient(auth, regcacert)
+	err = orasutils.OrasNewClient(reg.AuthConf(), reg.CACert())
 	if err != nil {
 		log.Errorln("Failed to create an OrasClient", err)
 		return err
 	}
 	eputils.SetTemplateParams(epParams)
 	eputils.SetTemplateFuncs(funcs)
+	if epParams.Runtimedir != "" {
+		eputils.KnownHostsFile = filepath.Join(epParams.Runtimedir, "known_hosts")
+	}
+	eputils.SetHostKeyPolicy(epParams.Kitconfig.Parameters.GlobalSettings.SSHHostKeyPolicy)
+	eputils.SetSSHBastion(epParams.Kitconfig.Parameters.Bastion)
 	return nil
 }
 
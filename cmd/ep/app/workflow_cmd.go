@@ -0,0 +1,86 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	"fmt"
+	wf "github.com/intel/edge-conductor/pkg/workflow"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func ep_workflow_plan(name string) error {
+	if _, err := EpWfPreInit(nil, nil); err != nil {
+		log.Errorln("Failed to init workflow:", err)
+		return err
+	}
+	p, err := wf.Plan(name, WfConfig)
+	if p != nil {
+		fmt.Print(p.Report())
+	}
+	return err
+}
+
+var graphFormat string
+
+func ep_workflow_graph(name string) error {
+	if _, err := EpWfPreInit(nil, nil); err != nil {
+		log.Errorln("Failed to init workflow:", err)
+		return err
+	}
+	g, err := wf.Graph(name, WfConfig, graphFormat)
+	if err != nil {
+		return err
+	}
+	fmt.Print(g)
+	return nil
+}
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "Workflow operations.",
+	Long:  `Workflow operations.`,
+}
+
+var planWorkflowCmd = &cobra.Command{
+	Use:   "plan <name>",
+	Short: "Check the data flow of a workflow.",
+	Long: `Check the data flow of a workflow without executing any plugin.
+Report missing inputs, unknown schemas, type mismatches, unused outputs and unknown plugins.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ep_workflow_plan(args[0]); err != nil {
+			log.Errorln("Failed to plan workflow:", err)
+			return err
+		}
+		return nil
+	},
+}
+
+var graphWorkflowCmd = &cobra.Command{
+	Use:   "graph <name>",
+	Short: "Render the data flow of a workflow.",
+	Long: `Render the steps of a workflow and the data flowing between them in the DOT or Mermaid format.
+Steps running in containers and confidential data are marked.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ep_workflow_graph(args[0]); err != nil {
+			log.Errorln("Failed to graph workflow:", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workflowCmd)
+	workflowCmd.AddCommand(planWorkflowCmd)
+	workflowCmd.AddCommand(graphWorkflowCmd)
+	graphWorkflowCmd.Flags().StringVar(&graphFormat, "format", wf.GRAPH_DOT, "Graph format: dot or mermaid")
+}
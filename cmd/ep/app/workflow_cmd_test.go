@@ -0,0 +1,145 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package app
+
+import (
+	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	wf "github.com/intel/edge-conductor/pkg/workflow"
+	"testing"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+func patchWfPlan(t *testing.T, p *wf.PlanResult, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(wf.Plan, func(name string, configFile string) (*wf.PlanResult, error) {
+		if configFile != WfConfig {
+			t.Errorf("The parameters of the workflow.Plan function are not expected")
+		}
+		return p, err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func TestEpWorkflowPlan(t *testing.T) {
+	cases := []struct {
+		name           string
+		wantError      error
+		funcBeforeTest func() []*mpatch.Patch
+	}{
+		{
+			name:      "EpWfPreInit fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, nil, testError)}
+			},
+		},
+		{
+			name:      "plan has errors",
+			wantError: eputils.GetError("errWorkflowPlan"),
+			funcBeforeTest: func() []*mpatch.Patch {
+				p := &wf.PlanResult{
+					Workflow: "test",
+					Issues:   []wf.PlanIssue{{Level: wf.PLAN_ERROR, Message: "unknown plugin"}},
+				}
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchWfPlan(t, p, eputils.GetError("errWorkflowPlan")),
+				}
+			},
+		},
+		{
+			name:      "plan ok",
+			wantError: nil,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchWfPlan(t, &wf.PlanResult{Workflow: "test"}, nil),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := tc.funcBeforeTest()
+			defer unpatchAll(t, pList)
+
+			err := ep_workflow_plan("test")
+			if !isWantedError(err, tc.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func patchWfGraph(t *testing.T, g string, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(wf.Graph, func(name string, configFile string, format string) (string, error) {
+		if configFile != WfConfig || format != graphFormat {
+			t.Errorf("The parameters of the workflow.Graph function are not expected")
+		}
+		return g, err
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func TestEpWorkflowGraph(t *testing.T) {
+	cases := []struct {
+		name           string
+		wantError      error
+		funcBeforeTest func() []*mpatch.Patch
+	}{
+		{
+			name:      "EpWfPreInit fail",
+			wantError: testError,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{patchEpWfPreInit(t, nil, testError)}
+			},
+		},
+		{
+			name:      "unsupported format",
+			wantError: eputils.GetError("errOutputFormat"),
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchWfGraph(t, "", eputils.GetError("errOutputFormat")),
+				}
+			},
+		},
+		{
+			name:      "graph ok",
+			wantError: nil,
+			funcBeforeTest: func() []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchEpWfPreInit(t, &epapiplugins.EpParams{}, nil),
+					patchWfGraph(t, "digraph \"test\" {\n}\n", nil),
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := tc.funcBeforeTest()
+			defer unpatchAll(t, pList)
+
+			err := ep_workflow_graph("test")
+			if !isWantedError(err, tc.wantError) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/intel/edge-conductor/pkg/api/plugins"
@@ -16,6 +17,7 @@ import (
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
 	"github.com/intel/edge-conductor/pkg/eputils/orasutils"
 	wf "github.com/intel/edge-conductor/pkg/workflow"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -142,7 +144,7 @@ func TestEpWfStart(t *testing.T) {
 			},
 			isFunctionCorrectly: isFunctionCorrectlyFunc,
 			funcBeforeTest: func() []*mpatch.Patch {
-				patch, patchErr := mpatch.PatchMethod(wf.Start, func(name string, address string, configFile string) error {
+				patch, patchErr := mpatch.PatchMethod(wf.Start, func(ctx context.Context, name string, address string, configFile string) error {
 					return testError
 				})
 				if patchErr != nil {
@@ -167,7 +169,7 @@ func TestEpWfStart(t *testing.T) {
 			isFunctionCorrectly: isFunctionCorrectlyFunc,
 			name:                "test_name",
 			funcBeforeTest: func() []*mpatch.Patch {
-				patch, patchErr := mpatch.PatchMethod(wf.Start, func(name string, address string, configFile string) error {
+				patch, patchErr := mpatch.PatchMethod(wf.Start, func(ctx context.Context, name string, address string, configFile string) error {
 					if name != "test_name" || address != "localhost:8228" || configFile != WfConfig {
 						t.Errorf("The parameters of the workflow.Start function are not expected")
 					}
@@ -180,6 +182,56 @@ func TestEpWfStart(t *testing.T) {
 				return []*mpatch.Patch{patch}
 			},
 		},
+		{
+			wantError: eputils.GetError("errOutputFormat"),
+			isFunctionCorrectly: func(err, wantError error) {
+				outputFormat = OutputText
+				isFunctionCorrectlyFunc(err, wantError)
+			},
+			funcBeforeTest: func() []*mpatch.Patch {
+				outputFormat = "yaml"
+				return nil
+			},
+		},
+		{
+			wantError: testError,
+			epParams: &epapiplugins.EpParams{
+				Kitconfig: &epapiplugins.Kitconfig{
+					Parameters: &epapiplugins.KitconfigParameters{
+						GlobalSettings: &epapiplugins.KitconfigParametersGlobalSettings{
+							ProviderIP:   "localhost",
+							WorkflowPort: "8228",
+						},
+					},
+				},
+			},
+			isFunctionCorrectly: func(err, wantError error) {
+				outputFormat = OutputText
+				isFunctionCorrectlyFunc(err, wantError)
+			},
+			name: "test_name",
+			funcBeforeTest: func() []*mpatch.Patch {
+				outputFormat = OutputJson
+				patchStart, patchErr := mpatch.PatchMethod(wf.Start, func(ctx context.Context, name string, address string, configFile string) error {
+					return testError
+				})
+				if patchErr != nil {
+					t.Errorf("patch error: %v", patchErr)
+					return nil
+				}
+				patchRead, patchErr := mpatch.PatchMethod(ioutil.ReadFile, func(filename string) ([]byte, error) {
+					if filename != wf.ReportFilePath("test_name") {
+						t.Errorf("The report file %s is not expected", filename)
+					}
+					return []byte("{}"), nil
+				})
+				if patchErr != nil {
+					t.Errorf("patch error: %v", patchErr)
+					return nil
+				}
+				return []*mpatch.Patch{patchStart, patchRead}
+			},
+		},
 	}
 
 	for n, testCase := range cases {
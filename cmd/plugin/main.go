@@ -7,6 +7,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	epapp "github.com/intel/edge-conductor/cmd/ep/app"
 	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
@@ -14,6 +15,8 @@ import (
 	plugin "github.com/intel/edge-conductor/pkg/plugin"
 	log "github.com/sirupsen/logrus"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
@@ -73,6 +76,9 @@ func main() {
 		os.Exit(1)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	log.Infof("plug list: %v", epplugins.PluginList)
 	for _, p := range epplugins.PluginList {
 		log.Infof("Enable plugin remote Log: %v\n", p)
@@ -80,7 +86,7 @@ func main() {
 			log.Fatal(err)
 		}
 		log.Infof("Start Plugin: %v\n", p)
-		if err := plugin.StartPlugin(p, nil); err != nil {
+		if err := plugin.StartPlugin(ctx, p, nil); err != nil {
 			log.Errorln(err)
 			os.Exit(1)
 		}
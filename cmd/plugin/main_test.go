@@ -7,6 +7,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	epapp "github.com/intel/edge-conductor/cmd/ep/app"
@@ -96,7 +97,7 @@ func patchEnablePluginRemoteLog(t *testing.T, err error) *mpatch.Patch {
 }
 
 func patchStartPlugin(t *testing.T, err error) *mpatch.Patch {
-	patch, patchErr := mpatch.PatchMethod(plugin.StartPlugin, func(name string, errch chan error) error {
+	patch, patchErr := mpatch.PatchMethod(plugin.StartPlugin, func(ctx context.Context, name string, errch chan error) error {
 		return err
 	})
 	if patchErr != nil {
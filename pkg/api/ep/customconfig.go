@@ -15,6 +15,7 @@ package ep
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 
 	"github.com/go-openapi/errors"
@@ -31,6 +32,9 @@ type Customconfig struct {
 	// cluster
 	Cluster *Cluster `json:"cluster,omitempty"`
 
+	// image verification
+	ImageVerification *CustomconfigImageVerification `json:"image_verification,omitempty"`
+
 	// ironic
 	Ironic *CustomconfigIronic `json:"ironic,omitempty"`
 
@@ -49,6 +53,10 @@ func (m *Customconfig) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateImageVerification(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateIronic(formats); err != nil {
 		res = append(res, err)
 	}
@@ -86,6 +94,25 @@ func (m *Customconfig) validateCluster(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *Customconfig) validateImageVerification(formats strfmt.Registry) error {
+	if swag.IsZero(m.ImageVerification) { // not required
+		return nil
+	}
+
+	if m.ImageVerification != nil {
+		if err := m.ImageVerification.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("image_verification")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("image_verification")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Customconfig) validateIronic(formats strfmt.Registry) error {
 	if swag.IsZero(m.Ironic) { // not required
 		return nil
@@ -158,6 +185,10 @@ func (m *Customconfig) ContextValidate(ctx context.Context, formats strfmt.Regis
 		res = append(res, err)
 	}
 
+	if err := m.contextValidateImageVerification(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateIronic(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -192,6 +223,22 @@ func (m *Customconfig) contextValidateCluster(ctx context.Context, formats strfm
 	return nil
 }
 
+func (m *Customconfig) contextValidateImageVerification(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.ImageVerification != nil {
+		if err := m.ImageVerification.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("image_verification")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("image_verification")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *Customconfig) contextValidateIronic(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.Ironic != nil {
@@ -262,6 +309,127 @@ func (m *Customconfig) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// CustomconfigImageVerification customconfig image verification
+//
+// swagger:model CustomconfigImageVerification
+type CustomconfigImageVerification struct {
+
+	// allowed registries
+	AllowedRegistries []string `json:"allowed_registries"`
+
+	// keys
+	Keys []string `json:"keys"`
+
+	// require sbom
+	RequireSbom bool `json:"require_sbom,omitempty"`
+
+	// signature
+	// Enum: [none cosign notation]
+	Signature string `json:"signature,omitempty"`
+}
+
+// Validate validates this customconfig image verification
+func (m *CustomconfigImageVerification) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateKeys(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateSignature(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *CustomconfigImageVerification) validateKeys(formats strfmt.Registry) error {
+	if swag.IsZero(m.Keys) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Keys); i++ {
+
+		if err := validate.Pattern("image_verification"+"."+"keys"+"."+strconv.Itoa(i), "body", m.Keys[i], `^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$`); err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+var customconfigImageVerificationTypeSignaturePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["none","cosign","notation"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		customconfigImageVerificationTypeSignaturePropEnum = append(customconfigImageVerificationTypeSignaturePropEnum, v)
+	}
+}
+
+const (
+
+	// CustomconfigImageVerificationSignatureNone captures enum value "none"
+	CustomconfigImageVerificationSignatureNone string = "none"
+
+	// CustomconfigImageVerificationSignatureCosign captures enum value "cosign"
+	CustomconfigImageVerificationSignatureCosign string = "cosign"
+
+	// CustomconfigImageVerificationSignatureNotation captures enum value "notation"
+	CustomconfigImageVerificationSignatureNotation string = "notation"
+)
+
+// prop value enum
+func (m *CustomconfigImageVerification) validateSignatureEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, customconfigImageVerificationTypeSignaturePropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *CustomconfigImageVerification) validateSignature(formats strfmt.Registry) error {
+	if swag.IsZero(m.Signature) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateSignatureEnum("image_verification"+"."+"signature", "body", m.Signature); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this customconfig image verification based on context it is used
+func (m *CustomconfigImageVerification) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *CustomconfigImageVerification) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *CustomconfigImageVerification) UnmarshalBinary(b []byte) error {
+	var res CustomconfigImageVerification
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
+
 // CustomconfigIronic customconfig ironic
 //
 // swagger:model CustomconfigIronic
@@ -398,6 +566,10 @@ type CustomconfigRegistry struct {
 	// password
 	Password string `json:"password,omitempty"`
 
+	// type
+	// Enum: [harbor registry external]
+	Type string `json:"type,omitempty"`
+
 	// user
 	User string `json:"user,omitempty"`
 }
@@ -414,6 +586,10 @@ func (m *CustomconfigRegistry) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateType(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -444,6 +620,51 @@ func (m *CustomconfigRegistry) validateExternalurl(formats strfmt.Registry) erro
 	return nil
 }
 
+var customconfigRegistryTypeTypePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["harbor","registry","external"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		customconfigRegistryTypeTypePropEnum = append(customconfigRegistryTypeTypePropEnum, v)
+	}
+}
+
+const (
+
+	// CustomconfigRegistryTypeHarbor captures enum value "harbor"
+	CustomconfigRegistryTypeHarbor string = "harbor"
+
+	// CustomconfigRegistryTypeRegistry captures enum value "registry"
+	CustomconfigRegistryTypeRegistry string = "registry"
+
+	// CustomconfigRegistryTypeExternal captures enum value "external"
+	CustomconfigRegistryTypeExternal string = "external"
+)
+
+// prop value enum
+func (m *CustomconfigRegistry) validateTypeEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, customconfigRegistryTypeTypePropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *CustomconfigRegistry) validateType(formats strfmt.Registry) error {
+	if swag.IsZero(m.Type) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateTypeEnum("registry"+"."+"type", "body", m.Type); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validates this customconfig registry based on context it is used
 func (m *CustomconfigRegistry) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	return nil
@@ -353,11 +353,22 @@ type ExecspecSpecStepsItems0 struct {
 	// commands
 	Commands []*ExecspecSpecStepsItems0CommandsItems0 `json:"commands"`
 
+	// continue on error
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+
+	// max fail percentage
+	// Maximum: 100
+	// Minimum: 0
+	MaxFailPercentage *int64 `json:"max_fail_percentage,omitempty"`
+
 	// name
 	Name string `json:"name,omitempty"`
 
 	// nodes
 	Nodes *ExecspecSpecStepsItems0Nodes `json:"nodes,omitempty"`
+
+	// serial
+	Serial string `json:"serial,omitempty"`
 }
 
 // Validate validates this execspec spec steps items0
@@ -368,6 +379,10 @@ func (m *ExecspecSpecStepsItems0) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateMaxFailPercentage(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateNodes(formats); err != nil {
 		res = append(res, err)
 	}
@@ -404,6 +419,22 @@ func (m *ExecspecSpecStepsItems0) validateCommands(formats strfmt.Registry) erro
 	return nil
 }
 
+func (m *ExecspecSpecStepsItems0) validateMaxFailPercentage(formats strfmt.Registry) error {
+	if swag.IsZero(m.MaxFailPercentage) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("max_fail_percentage", "body", *m.MaxFailPercentage, 0, false); err != nil {
+		return err
+	}
+
+	if err := validate.MaximumInt("max_fail_percentage", "body", *m.MaxFailPercentage, 100, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *ExecspecSpecStepsItems0) validateNodes(formats strfmt.Registry) error {
 	if swag.IsZero(m.Nodes) { // not required
 		return nil
@@ -503,9 +534,24 @@ type ExecspecSpecStepsItems0CommandsItems0 struct {
 	// cmd
 	Cmd []string `json:"cmd"`
 
+	// creates
+	Creates string `json:"creates,omitempty"`
+
+	// ignore errors
+	IgnoreErrors bool `json:"ignore_errors,omitempty"`
+
+	// only if
+	OnlyIf string `json:"onlyIf,omitempty"`
+
+	// register
+	Register string `json:"register,omitempty"`
+
 	// type
 	Type string `json:"type,omitempty"`
 
+	// unless
+	Unless string `json:"unless,omitempty"`
+
 	// when
 	When string `json:"when,omitempty"`
 }
@@ -15,6 +15,7 @@ package plugins
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 
 	"github.com/go-openapi/errors"
@@ -135,6 +136,9 @@ type ImagesItems0 struct {
 
 	// url
 	URL string `json:"url,omitempty"`
+
+	// verification
+	Verification *ImagesItems0Verification `json:"verification,omitempty"`
 }
 
 // Validate validates this images items0
@@ -145,6 +149,10 @@ func (m *ImagesItems0) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateVerification(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -163,8 +171,52 @@ func (m *ImagesItems0) validateName(formats strfmt.Registry) error {
 	return nil
 }
 
-// ContextValidate validates this images items0 based on context it is used
+func (m *ImagesItems0) validateVerification(formats strfmt.Registry) error {
+	if swag.IsZero(m.Verification) { // not required
+		return nil
+	}
+
+	if m.Verification != nil {
+		if err := m.Verification.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("verification")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("verification")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ContextValidate validate this images items0 based on the context it is used
 func (m *ImagesItems0) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateVerification(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *ImagesItems0) contextValidateVerification(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Verification != nil {
+		if err := m.Verification.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("verification")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("verification")
+			}
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -185,3 +237,107 @@ func (m *ImagesItems0) UnmarshalBinary(b []byte) error {
 	*m = res
 	return nil
 }
+
+// ImagesItems0Verification images items0 verification
+//
+// swagger:model ImagesItems0Verification
+type ImagesItems0Verification struct {
+
+	// digest
+	Digest string `json:"digest,omitempty"`
+
+	// error
+	Error string `json:"error,omitempty"`
+
+	// key
+	Key string `json:"key,omitempty"`
+
+	// result
+	// Enum: [verified failed]
+	Result string `json:"result,omitempty"`
+
+	// sbom
+	Sbom string `json:"sbom,omitempty"`
+
+	// signature
+	Signature string `json:"signature,omitempty"`
+}
+
+// Validate validates this images items0 verification
+func (m *ImagesItems0Verification) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateResult(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+var imagesItems0VerificationTypeResultPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["verified","failed"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		imagesItems0VerificationTypeResultPropEnum = append(imagesItems0VerificationTypeResultPropEnum, v)
+	}
+}
+
+const (
+
+	// ImagesItems0VerificationResultVerified captures enum value "verified"
+	ImagesItems0VerificationResultVerified string = "verified"
+
+	// ImagesItems0VerificationResultFailed captures enum value "failed"
+	ImagesItems0VerificationResultFailed string = "failed"
+)
+
+// prop value enum
+func (m *ImagesItems0Verification) validateResultEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, imagesItems0VerificationTypeResultPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *ImagesItems0Verification) validateResult(formats strfmt.Registry) error {
+	if swag.IsZero(m.Result) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateResultEnum("verification"+"."+"result", "body", m.Result); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this images items0 verification based on context it is used
+func (m *ImagesItems0Verification) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *ImagesItems0Verification) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *ImagesItems0Verification) UnmarshalBinary(b []byte) error {
+	var res ImagesItems0Verification
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
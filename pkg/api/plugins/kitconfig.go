@@ -703,6 +703,9 @@ func (m *KitconfigOS) UnmarshalBinary(b []byte) error {
 // swagger:model KitconfigParameters
 type KitconfigParameters struct {
 
+	// bastion
+	Bastion *Node `json:"bastion,omitempty"`
+
 	// customconfig
 	Customconfig *Customconfig `json:"customconfig,omitempty"`
 
@@ -723,6 +726,10 @@ type KitconfigParameters struct {
 func (m *KitconfigParameters) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateBastion(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateCustomconfig(formats); err != nil {
 		res = append(res, err)
 	}
@@ -745,6 +752,25 @@ func (m *KitconfigParameters) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *KitconfigParameters) validateBastion(formats strfmt.Registry) error {
+	if swag.IsZero(m.Bastion) { // not required
+		return nil
+	}
+
+	if m.Bastion != nil {
+		if err := m.Bastion.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("Parameters" + "." + "bastion")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("Parameters" + "." + "bastion")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *KitconfigParameters) validateCustomconfig(formats strfmt.Registry) error {
 	if swag.IsZero(m.Customconfig) { // not required
 		return nil
@@ -829,6 +855,10 @@ func (m *KitconfigParameters) validateNodes(formats strfmt.Registry) error {
 func (m *KitconfigParameters) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateBastion(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateCustomconfig(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -847,6 +877,22 @@ func (m *KitconfigParameters) ContextValidate(ctx context.Context, formats strfm
 	return nil
 }
 
+func (m *KitconfigParameters) contextValidateBastion(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Bastion != nil {
+		if err := m.Bastion.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("Parameters" + "." + "bastion")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("Parameters" + "." + "bastion")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *KitconfigParameters) contextValidateCustomconfig(ctx context.Context, formats strfmt.Registry) error {
 
 	if m.Customconfig != nil {
@@ -948,6 +994,10 @@ type KitconfigParametersGlobalSettings struct {
 	// Pattern: ^((6553[0-5])|(655[0-2][0-9])|(65[0-4][0-9]{2})|(6[0-4][0-9]{3})|([1-5][0-9]{4})|([0-5]{0,5})|([0-9]{1,4}))$
 	RegistryPort string `json:"registry_port,omitempty"`
 
+	// ssh host key policy
+	// Enum: [tofu strict]
+	SSHHostKeyPolicy string `json:"ssh_host_key_policy,omitempty"`
+
 	// workflow port
 	// Pattern: ^((6553[0-5])|(655[0-2][0-9])|(65[0-4][0-9]{2})|(6[0-4][0-9]{3})|([1-5][0-9]{4})|([0-5]{0,5})|([0-9]{1,4}))$
 	WorkflowPort string `json:"workflow_port,omitempty"`
@@ -977,6 +1027,10 @@ func (m *KitconfigParametersGlobalSettings) Validate(formats strfmt.Registry) er
 		res = append(res, err)
 	}
 
+	if err := m.validateSSHHostKeyPolicy(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateWorkflowPort(formats); err != nil {
 		res = append(res, err)
 	}
@@ -1047,6 +1101,48 @@ func (m *KitconfigParametersGlobalSettings) validateRegistryPort(formats strfmt.
 	return nil
 }
 
+var kitconfigParametersGlobalSettingsTypeSSHHostKeyPolicyPropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["tofu","strict"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		kitconfigParametersGlobalSettingsTypeSSHHostKeyPolicyPropEnum = append(kitconfigParametersGlobalSettingsTypeSSHHostKeyPolicyPropEnum, v)
+	}
+}
+
+const (
+
+	// KitconfigParametersGlobalSettingsSSHHostKeyPolicyTofu captures enum value "tofu"
+	KitconfigParametersGlobalSettingsSSHHostKeyPolicyTofu string = "tofu"
+
+	// KitconfigParametersGlobalSettingsSSHHostKeyPolicyStrict captures enum value "strict"
+	KitconfigParametersGlobalSettingsSSHHostKeyPolicyStrict string = "strict"
+)
+
+// prop value enum
+func (m *KitconfigParametersGlobalSettings) validateSSHHostKeyPolicyEnum(path, location string, value string) error {
+	if err := validate.EnumCase(path, location, value, kitconfigParametersGlobalSettingsTypeSSHHostKeyPolicyPropEnum, true); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *KitconfigParametersGlobalSettings) validateSSHHostKeyPolicy(formats strfmt.Registry) error {
+	if swag.IsZero(m.SSHHostKeyPolicy) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateSSHHostKeyPolicyEnum("Parameters"+"."+"global_settings"+"."+"ssh_host_key_policy", "body", m.SSHHostKeyPolicy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *KitconfigParametersGlobalSettings) validateWorkflowPort(formats strfmt.Registry) error {
 	if swag.IsZero(m.WorkflowPort) { // not required
 		return nil
@@ -65,6 +65,9 @@ type Node struct {
 	// role
 	Role []string `json:"role"`
 
+	// ssh host key fingerprint
+	SSHHostKeyFingerprint string `json:"ssh_host_key_fingerprint,omitempty"`
+
 	// ssh key
 	SSHKey string `json:"ssh_key,omitempty"`
 
@@ -0,0 +1,244 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+//
+//   Copyright (c) 2022 Intel Corporation.
+//
+//   SPDX-License-Identifier: Apache-2.0
+//
+//
+//
+
+package workflow
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/go-openapi/validate"
+)
+
+// Hook hook
+//
+// swagger:model hook
+type Hook struct {
+
+	// executor
+	// Pattern: ^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$
+	Executor string `json:"executor,omitempty"`
+
+	// input
+	Input []*HookInputItems0 `json:"input"`
+
+	// plugin
+	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
+	Plugin string `json:"plugin,omitempty"`
+}
+
+// Validate validates this hook
+func (m *Hook) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateExecutor(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateInput(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validatePlugin(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Hook) validateExecutor(formats strfmt.Registry) error {
+	if swag.IsZero(m.Executor) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("executor", "body", m.Executor, `^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Hook) validateInput(formats strfmt.Registry) error {
+	if swag.IsZero(m.Input) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Input); i++ {
+		if swag.IsZero(m.Input[i]) { // not required
+			continue
+		}
+
+		if m.Input[i] != nil {
+			if err := m.Input[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("input" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("input" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Hook) validatePlugin(formats strfmt.Registry) error {
+	if swag.IsZero(m.Plugin) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("plugin", "body", m.Plugin, `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validate this hook based on the context it is used
+func (m *Hook) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateInput(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Hook) contextValidateInput(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Input); i++ {
+
+		if m.Input[i] != nil {
+			if err := m.Input[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("input" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("input" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Hook) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Hook) UnmarshalBinary(b []byte) error {
+	var res Hook
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
+
+// HookInputItems0 hook input items0
+//
+// swagger:model HookInputItems0
+type HookInputItems0 struct {
+
+	// name
+	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
+	Name string `json:"name,omitempty"`
+
+	// schema
+	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
+	Schema string `json:"schema,omitempty"`
+}
+
+// Validate validates this hook input items0
+func (m *HookInputItems0) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateName(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateSchema(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *HookInputItems0) validateName(formats strfmt.Registry) error {
+	if swag.IsZero(m.Name) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("name", "body", m.Name, `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *HookInputItems0) validateSchema(formats strfmt.Registry) error {
+	if swag.IsZero(m.Schema) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("schema", "body", m.Schema, `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ContextValidate validates this hook input items0 based on context it is used
+func (m *HookInputItems0) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *HookInputItems0) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *HookInputItems0) UnmarshalBinary(b []byte) error {
+	var res HookInputItems0
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -0,0 +1,238 @@
+// Code generated by go-swagger; DO NOT EDIT.
+
+//
+//   Copyright (c) 2022 Intel Corporation.
+//
+//   SPDX-License-Identifier: Apache-2.0
+//
+//
+//
+
+package workflow
+
+// This file was generated by the swagger tool.
+// Editing this file might prove futile when you re-run the swagger generate command
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-openapi/errors"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+)
+
+// Hooks hooks
+//
+// swagger:model hooks
+type Hooks struct {
+
+	// on failure
+	OnFailure []*Hook `json:"on-failure"`
+
+	// post
+	Post []*Hook `json:"post"`
+
+	// pre
+	Pre []*Hook `json:"pre"`
+}
+
+// Validate validates this hooks
+func (m *Hooks) Validate(formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.validateOnFailure(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validatePost(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validatePre(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Hooks) validateOnFailure(formats strfmt.Registry) error {
+	if swag.IsZero(m.OnFailure) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.OnFailure); i++ {
+		if swag.IsZero(m.OnFailure[i]) { // not required
+			continue
+		}
+
+		if m.OnFailure[i] != nil {
+			if err := m.OnFailure[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("on-failure" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("on-failure" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Hooks) validatePost(formats strfmt.Registry) error {
+	if swag.IsZero(m.Post) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Post); i++ {
+		if swag.IsZero(m.Post[i]) { // not required
+			continue
+		}
+
+		if m.Post[i] != nil {
+			if err := m.Post[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("post" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("post" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Hooks) validatePre(formats strfmt.Registry) error {
+	if swag.IsZero(m.Pre) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Pre); i++ {
+		if swag.IsZero(m.Pre[i]) { // not required
+			continue
+		}
+
+		if m.Pre[i] != nil {
+			if err := m.Pre[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("pre" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("pre" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// ContextValidate validate this hooks based on the context it is used
+func (m *Hooks) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
+	var res []error
+
+	if err := m.contextValidateOnFailure(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidatePost(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.contextValidatePre(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
+	if len(res) > 0 {
+		return errors.CompositeValidationError(res...)
+	}
+	return nil
+}
+
+func (m *Hooks) contextValidateOnFailure(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.OnFailure); i++ {
+
+		if m.OnFailure[i] != nil {
+			if err := m.OnFailure[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("on-failure" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("on-failure" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Hooks) contextValidatePost(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Post); i++ {
+
+		if m.Post[i] != nil {
+			if err := m.Post[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("post" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("post" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *Hooks) contextValidatePre(ctx context.Context, formats strfmt.Registry) error {
+
+	for i := 0; i < len(m.Pre); i++ {
+
+		if m.Pre[i] != nil {
+			if err := m.Pre[i].ContextValidate(ctx, formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("pre" + "." + strconv.Itoa(i))
+				} else if ce, ok := err.(*errors.CompositeError); ok {
+					return ce.ValidateName("pre" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+// MarshalBinary interface implementation
+func (m *Hooks) MarshalBinary() ([]byte, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return swag.WriteJSON(m)
+}
+
+// UnmarshalBinary interface implementation
+func (m *Hooks) UnmarshalBinary(b []byte) error {
+	var res Hooks
+	if err := swag.ReadJSON(b, &res); err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
@@ -571,6 +571,13 @@ func (m *WorkflowSpecDataItems0) UnmarshalBinary(b []byte) error {
 // swagger:model WorkflowSpecPluginsItems0
 type WorkflowSpecPluginsItems0 struct {
 
+	// args
+	Args []string `json:"args"`
+
+	// command
+	// Pattern: ^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$
+	Command string `json:"command,omitempty"`
+
 	// container
 	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
 	Container string `json:"container,omitempty"`
@@ -584,6 +591,10 @@ type WorkflowSpecPluginsItems0 struct {
 func (m *WorkflowSpecPluginsItems0) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateCommand(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateContainer(formats); err != nil {
 		res = append(res, err)
 	}
@@ -598,6 +609,18 @@ func (m *WorkflowSpecPluginsItems0) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *WorkflowSpecPluginsItems0) validateCommand(formats strfmt.Registry) error {
+	if swag.IsZero(m.Command) { // not required
+		return nil
+	}
+
+	if err := validate.Pattern("command", "body", m.Command, `^[a-zA-Z.\/][a-zA-Z0-9-_.\/]*$`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *WorkflowSpecPluginsItems0) validateContainer(formats strfmt.Registry) error {
 	if swag.IsZero(m.Container) { // not required
 		return nil
@@ -650,10 +673,16 @@ func (m *WorkflowSpecPluginsItems0) UnmarshalBinary(b []byte) error {
 // swagger:model WorkflowSpecWorkflowsItems0
 type WorkflowSpecWorkflowsItems0 struct {
 
+	// hooks
+	Hooks *Hooks `json:"hooks,omitempty"`
+
 	// name
 	// Pattern: ^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$
 	Name string `json:"name,omitempty"`
 
+	// parallel
+	Parallel bool `json:"parallel,omitempty"`
+
 	// steps
 	Steps []*WorkflowSpecWorkflowsItems0StepsItems0 `json:"steps"`
 }
@@ -662,6 +691,10 @@ type WorkflowSpecWorkflowsItems0 struct {
 func (m *WorkflowSpecWorkflowsItems0) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateHooks(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateName(formats); err != nil {
 		res = append(res, err)
 	}
@@ -676,6 +709,25 @@ func (m *WorkflowSpecWorkflowsItems0) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *WorkflowSpecWorkflowsItems0) validateHooks(formats strfmt.Registry) error {
+	if swag.IsZero(m.Hooks) { // not required
+		return nil
+	}
+
+	if m.Hooks != nil {
+		if err := m.Hooks.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("hooks")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("hooks")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *WorkflowSpecWorkflowsItems0) validateName(formats strfmt.Registry) error {
 	if swag.IsZero(m.Name) { // not required
 		return nil
@@ -718,6 +770,10 @@ func (m *WorkflowSpecWorkflowsItems0) validateSteps(formats strfmt.Registry) err
 func (m *WorkflowSpecWorkflowsItems0) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateHooks(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateSteps(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -728,6 +784,22 @@ func (m *WorkflowSpecWorkflowsItems0) ContextValidate(ctx context.Context, forma
 	return nil
 }
 
+func (m *WorkflowSpecWorkflowsItems0) contextValidateHooks(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Hooks != nil {
+		if err := m.Hooks.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("hooks")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("hooks")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *WorkflowSpecWorkflowsItems0) contextValidateSteps(ctx context.Context, formats strfmt.Registry) error {
 
 	for i := 0; i < len(m.Steps); i++ {
@@ -771,6 +843,15 @@ func (m *WorkflowSpecWorkflowsItems0) UnmarshalBinary(b []byte) error {
 // swagger:model WorkflowSpecWorkflowsItems0StepsItems0
 type WorkflowSpecWorkflowsItems0StepsItems0 struct {
 
+	// backoff
+	Backoff string `json:"backoff,omitempty"`
+
+	// depends
+	Depends []string `json:"depends"`
+
+	// hooks
+	Hooks *Hooks `json:"hooks,omitempty"`
+
 	// input
 	Input []*WorkflowSpecWorkflowsItems0StepsItems0InputItems0 `json:"input"`
 
@@ -780,12 +861,30 @@ type WorkflowSpecWorkflowsItems0StepsItems0 struct {
 
 	// output
 	Output []*WorkflowSpecWorkflowsItems0StepsItems0OutputItems0 `json:"output"`
+
+	// retries
+	// Minimum: 0
+	Retries *int64 `json:"retries,omitempty"`
+
+	// timeout
+	Timeout string `json:"timeout,omitempty"`
+
+	// when
+	When string `json:"when,omitempty"`
 }
 
 // Validate validates this workflow spec workflows items0 steps items0
 func (m *WorkflowSpecWorkflowsItems0StepsItems0) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateDepends(formats); err != nil {
+		res = append(res, err)
+	}
+
+	if err := m.validateHooks(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.validateInput(formats); err != nil {
 		res = append(res, err)
 	}
@@ -798,12 +897,51 @@ func (m *WorkflowSpecWorkflowsItems0StepsItems0) Validate(formats strfmt.Registr
 		res = append(res, err)
 	}
 
+	if err := m.validateRetries(formats); err != nil {
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateDepends(formats strfmt.Registry) error {
+	if swag.IsZero(m.Depends) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Depends); i++ {
+
+		if err := validate.Pattern("depends"+"."+strconv.Itoa(i), "body", m.Depends[i], `^[a-zA-Z_$][a-zA-Z_.\-$0-9]*$`); err != nil {
+			return err
+		}
+
+	}
+
+	return nil
+}
+
+func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateHooks(formats strfmt.Registry) error {
+	if swag.IsZero(m.Hooks) { // not required
+		return nil
+	}
+
+	if m.Hooks != nil {
+		if err := m.Hooks.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("hooks")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("hooks")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateInput(formats strfmt.Registry) error {
 	if swag.IsZero(m.Input) { // not required
 		return nil
@@ -868,10 +1006,26 @@ func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateOutput(formats strfmt.R
 	return nil
 }
 
+func (m *WorkflowSpecWorkflowsItems0StepsItems0) validateRetries(formats strfmt.Registry) error {
+	if swag.IsZero(m.Retries) { // not required
+		return nil
+	}
+
+	if err := validate.MinimumInt("retries", "body", *m.Retries, 0, false); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // ContextValidate validate this workflow spec workflows items0 steps items0 based on the context it is used
 func (m *WorkflowSpecWorkflowsItems0StepsItems0) ContextValidate(ctx context.Context, formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.contextValidateHooks(ctx, formats); err != nil {
+		res = append(res, err)
+	}
+
 	if err := m.contextValidateInput(ctx, formats); err != nil {
 		res = append(res, err)
 	}
@@ -886,6 +1040,22 @@ func (m *WorkflowSpecWorkflowsItems0StepsItems0) ContextValidate(ctx context.Con
 	return nil
 }
 
+func (m *WorkflowSpecWorkflowsItems0StepsItems0) contextValidateHooks(ctx context.Context, formats strfmt.Registry) error {
+
+	if m.Hooks != nil {
+		if err := m.Hooks.ContextValidate(ctx, formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("hooks")
+			} else if ce, ok := err.(*errors.CompositeError); ok {
+				return ce.ValidateName("hooks")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *WorkflowSpecWorkflowsItems0StepsItems0) contextValidateInput(ctx context.Context, formats strfmt.Registry) error {
 
 	for i := 0; i < len(m.Input); i++ {
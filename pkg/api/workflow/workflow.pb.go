@@ -124,6 +124,61 @@ func (ConnectResult_Return) EnumDescriptor() ([]byte, []int) {
 	return file_api_proto_workflow_proto_rawDescGZIP(), []int{5, 0}
 }
 
+type Event_Type int32
+
+const (
+	Event_StepStarted   Event_Type = 0
+	Event_StepCompleted Event_Type = 1
+	Event_StepFailed    Event_Type = 2
+	Event_LogLine       Event_Type = 3
+	Event_StepSkipped   Event_Type = 4
+)
+
+// Enum value maps for Event_Type.
+var (
+	Event_Type_name = map[int32]string{
+		0: "StepStarted",
+		1: "StepCompleted",
+		2: "StepFailed",
+		3: "LogLine",
+		4: "StepSkipped",
+	}
+	Event_Type_value = map[string]int32{
+		"StepStarted":   0,
+		"StepCompleted": 1,
+		"StepFailed":    2,
+		"LogLine":       3,
+		"StepSkipped":   4,
+	}
+)
+
+func (x Event_Type) Enum() *Event_Type {
+	p := new(Event_Type)
+	*p = x
+	return p
+}
+
+func (x Event_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Event_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_workflow_proto_enumTypes[2].Descriptor()
+}
+
+func (Event_Type) Type() protoreflect.EnumType {
+	return &file_api_proto_workflow_proto_enumTypes[2]
+}
+
+func (x Event_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Event_Type.Descriptor instead.
+func (Event_Type) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_workflow_proto_rawDescGZIP(), []int{10, 0}
+}
+
 type PluginConnectRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -490,7 +545,8 @@ type Log struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Log string `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	Log    string  `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
+	Plugin *Plugin `protobuf:"bytes,2,opt,name=plugin,proto3" json:"plugin,omitempty"`
 }
 
 func (x *Log) Reset() {
@@ -532,6 +588,201 @@ func (x *Log) GetLog() string {
 	return ""
 }
 
+func (x *Log) GetPlugin() *Plugin {
+	if x != nil {
+		return x.Plugin
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_workflow_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_workflow_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_workflow_proto_rawDescGZIP(), []int{8}
+}
+
+type CancelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_workflow_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_workflow_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_workflow_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      Event_Type `protobuf:"varint,1,opt,name=type,proto3,enum=workflow.Event_Type" json:"type,omitempty"`
+	Workflow  string     `protobuf:"bytes,2,opt,name=workflow,proto3" json:"workflow,omitempty"`
+	Step      int32      `protobuf:"varint,3,opt,name=step,proto3" json:"step,omitempty"`
+	Plugin    *Plugin    `protobuf:"bytes,4,opt,name=plugin,proto3" json:"plugin,omitempty"`
+	Attempt   int32      `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Timestamp int64      `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ErrorCode string     `protobuf:"bytes,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Message   string     `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_workflow_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_workflow_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_api_proto_workflow_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Event) GetType() Event_Type {
+	if x != nil {
+		return x.Type
+	}
+	return Event_StepStarted
+}
+
+func (x *Event) GetWorkflow() string {
+	if x != nil {
+		return x.Workflow
+	}
+	return ""
+}
+
+func (x *Event) GetStep() int32 {
+	if x != nil {
+		return x.Step
+	}
+	return 0
+}
+
+func (x *Event) GetPlugin() *Plugin {
+	if x != nil {
+		return x.Plugin
+	}
+	return nil
+}
+
+func (x *Event) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *Event) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Event) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *Event) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_api_proto_workflow_proto protoreflect.FileDescriptor
 
 var file_api_proto_workflow_proto_rawDesc = []byte{
@@ -580,24 +831,59 @@ var file_api_proto_workflow_proto_rawDesc = []byte{
 	0x09, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05,
 	0x45, 0x72, 0x72, 0x6f, 0x72, 0x10, 0x02, 0x22, 0x1c, 0x0a, 0x06, 0x50, 0x6c, 0x75, 0x67, 0x69,
 	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x17, 0x0a, 0x03, 0x4c, 0x6f, 0x67, 0x12, 0x10, 0x0a, 0x03,
-	0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c, 0x6f, 0x67, 0x32, 0xda,
-	0x01, 0x0a, 0x08, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x52, 0x0a, 0x0d, 0x50,
-	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x1e, 0x2e, 0x77,
-	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f,
-	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x77,
-	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f,
-	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x33, 0x0a, 0x0c, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x50, 0x75, 0x74, 0x4c, 0x6f, 0x67, 0x12,
-	0x0d, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x4c, 0x6f, 0x67, 0x1a, 0x10,
-	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x22, 0x00, 0x28, 0x01, 0x12, 0x45, 0x0a, 0x0e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f,
-	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
-	0x77, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c,
-	0x6f, 0x77, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x42, 0x12, 0x5a, 0x10, 0x70,
-	0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x41, 0x0a, 0x03, 0x4c, 0x6f, 0x67, 0x12, 0x10, 0x0a, 0x03,
+	0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6c, 0x6f, 0x67, 0x12, 0x28,
+	0x0a, 0x06, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x52, 0x06, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x22, 0x0e, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x27, 0x0a, 0x0d, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x22, 0xd6, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x77, 0x6f, 0x72, 0x6b,
+	0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x28, 0x0a, 0x06, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77,
+	0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x52, 0x06, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x22, 0x58, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x74, 0x65, 0x70,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x74, 0x65,
+	0x70, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a,
+	0x53, 0x74, 0x65, 0x70, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07,
+	0x4c, 0x6f, 0x67, 0x4c, 0x69, 0x6e, 0x65, 0x10, 0x03, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x74, 0x65,
+	0x70, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x10, 0x04, 0x32, 0xcd, 0x02, 0x0a, 0x08, 0x57,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x52, 0x0a, 0x0d, 0x50, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x1e, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66,
+	0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33, 0x0a, 0x0c, 0x50,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x50, 0x75, 0x74, 0x4c, 0x6f, 0x67, 0x12, 0x0d, 0x2e, 0x77, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x4c, 0x6f, 0x67, 0x1a, 0x10, 0x2e, 0x77, 0x6f, 0x72,
+	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x28, 0x01,
+	0x12, 0x45, 0x0a, 0x0e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
+	0x74, 0x65, 0x12, 0x1f, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x50, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x16, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f,
+	0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x35, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x17, 0x2e,
+	0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f,
+	0x77, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x42, 0x12, 0x5a, 0x10, 0x70, 0x6b,
+	0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -612,40 +898,51 @@ func file_api_proto_workflow_proto_rawDescGZIP() []byte {
 	return file_api_proto_workflow_proto_rawDescData
 }
 
-var file_api_proto_workflow_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_proto_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_api_proto_workflow_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_api_proto_workflow_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_api_proto_workflow_proto_goTypes = []interface{}{
 	(Result_Return)(0),            // 0: workflow.Result.Return
 	(ConnectResult_Return)(0),     // 1: workflow.ConnectResult.Return
-	(*PluginConnectRequest)(nil),  // 2: workflow.PluginConnectRequest
-	(*PluginCompleteRequest)(nil), // 3: workflow.PluginCompleteRequest
-	(*PluginConnectResponse)(nil), // 4: workflow.PluginConnectResponse
-	(*WorkflowData)(nil),          // 5: workflow.WorkflowData
-	(*Result)(nil),                // 6: workflow.Result
-	(*ConnectResult)(nil),         // 7: workflow.ConnectResult
-	(*Plugin)(nil),                // 8: workflow.Plugin
-	(*Log)(nil),                   // 9: workflow.Log
+	(Event_Type)(0),               // 2: workflow.Event.Type
+	(*PluginConnectRequest)(nil),  // 3: workflow.PluginConnectRequest
+	(*PluginCompleteRequest)(nil), // 4: workflow.PluginCompleteRequest
+	(*PluginConnectResponse)(nil), // 5: workflow.PluginConnectResponse
+	(*WorkflowData)(nil),          // 6: workflow.WorkflowData
+	(*Result)(nil),                // 7: workflow.Result
+	(*ConnectResult)(nil),         // 8: workflow.ConnectResult
+	(*Plugin)(nil),                // 9: workflow.Plugin
+	(*Log)(nil),                   // 10: workflow.Log
+	(*WatchRequest)(nil),          // 11: workflow.WatchRequest
+	(*CancelRequest)(nil),         // 12: workflow.CancelRequest
+	(*Event)(nil),                 // 13: workflow.Event
 }
 var file_api_proto_workflow_proto_depIdxs = []int32{
-	8,  // 0: workflow.PluginConnectRequest.plugin:type_name -> workflow.Plugin
-	8,  // 1: workflow.PluginCompleteRequest.plugin:type_name -> workflow.Plugin
-	6,  // 2: workflow.PluginCompleteRequest.result:type_name -> workflow.Result
-	5,  // 3: workflow.PluginCompleteRequest.workflow_data:type_name -> workflow.WorkflowData
-	5,  // 4: workflow.PluginConnectResponse.workflow_data:type_name -> workflow.WorkflowData
-	7,  // 5: workflow.PluginConnectResponse.result:type_name -> workflow.ConnectResult
+	9,  // 0: workflow.PluginConnectRequest.plugin:type_name -> workflow.Plugin
+	9,  // 1: workflow.PluginCompleteRequest.plugin:type_name -> workflow.Plugin
+	7,  // 2: workflow.PluginCompleteRequest.result:type_name -> workflow.Result
+	6,  // 3: workflow.PluginCompleteRequest.workflow_data:type_name -> workflow.WorkflowData
+	6,  // 4: workflow.PluginConnectResponse.workflow_data:type_name -> workflow.WorkflowData
+	8,  // 5: workflow.PluginConnectResponse.result:type_name -> workflow.ConnectResult
 	0,  // 6: workflow.Result.return:type_name -> workflow.Result.Return
 	1,  // 7: workflow.ConnectResult.return:type_name -> workflow.ConnectResult.Return
-	2,  // 8: workflow.Workflow.PluginConnect:input_type -> workflow.PluginConnectRequest
-	9,  // 9: workflow.Workflow.PluginPutLog:input_type -> workflow.Log
-	3,  // 10: workflow.Workflow.PluginComplete:input_type -> workflow.PluginCompleteRequest
-	4,  // 11: workflow.Workflow.PluginConnect:output_type -> workflow.PluginConnectResponse
-	6,  // 12: workflow.Workflow.PluginPutLog:output_type -> workflow.Result
-	6,  // 13: workflow.Workflow.PluginComplete:output_type -> workflow.Result
-	11, // [11:14] is the sub-list for method output_type
-	8,  // [8:11] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	9,  // 8: workflow.Log.plugin:type_name -> workflow.Plugin
+	2,  // 9: workflow.Event.type:type_name -> workflow.Event.Type
+	9,  // 10: workflow.Event.plugin:type_name -> workflow.Plugin
+	3,  // 11: workflow.Workflow.PluginConnect:input_type -> workflow.PluginConnectRequest
+	10, // 12: workflow.Workflow.PluginPutLog:input_type -> workflow.Log
+	4,  // 13: workflow.Workflow.PluginComplete:input_type -> workflow.PluginCompleteRequest
+	11, // 14: workflow.Workflow.WatchEvents:input_type -> workflow.WatchRequest
+	12, // 15: workflow.Workflow.Cancel:input_type -> workflow.CancelRequest
+	5,  // 16: workflow.Workflow.PluginConnect:output_type -> workflow.PluginConnectResponse
+	7,  // 17: workflow.Workflow.PluginPutLog:output_type -> workflow.Result
+	7,  // 18: workflow.Workflow.PluginComplete:output_type -> workflow.Result
+	13, // 19: workflow.Workflow.WatchEvents:output_type -> workflow.Event
+	7,  // 20: workflow.Workflow.Cancel:output_type -> workflow.Result
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_workflow_proto_init() }
@@ -750,14 +1047,50 @@ func file_api_proto_workflow_proto_init() {
 				return nil
 			}
 		}
+		file_api_proto_workflow_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_workflow_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_workflow_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Event); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_workflow_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   8,
+			NumEnums:      3,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -787,6 +1120,8 @@ type WorkflowClient interface {
 	PluginConnect(ctx context.Context, in *PluginConnectRequest, opts ...grpc.CallOption) (*PluginConnectResponse, error)
 	PluginPutLog(ctx context.Context, opts ...grpc.CallOption) (Workflow_PluginPutLogClient, error)
 	PluginComplete(ctx context.Context, in *PluginCompleteRequest, opts ...grpc.CallOption) (*Result, error)
+	WatchEvents(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Workflow_WatchEventsClient, error)
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*Result, error)
 }
 
 type workflowClient struct {
@@ -849,11 +1184,54 @@ func (c *workflowClient) PluginComplete(ctx context.Context, in *PluginCompleteR
 	return out, nil
 }
 
+func (c *workflowClient) WatchEvents(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Workflow_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Workflow_serviceDesc.Streams[1], "/workflow.Workflow/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Workflow_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type workflowWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workflowClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*Result, error) {
+	out := new(Result)
+	err := c.cc.Invoke(ctx, "/workflow.Workflow/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServer is the server API for Workflow service.
 type WorkflowServer interface {
 	PluginConnect(context.Context, *PluginConnectRequest) (*PluginConnectResponse, error)
 	PluginPutLog(Workflow_PluginPutLogServer) error
 	PluginComplete(context.Context, *PluginCompleteRequest) (*Result, error)
+	WatchEvents(*WatchRequest, Workflow_WatchEventsServer) error
+	Cancel(context.Context, *CancelRequest) (*Result, error)
 }
 
 // UnimplementedWorkflowServer can be embedded to have forward compatible implementations.
@@ -869,6 +1247,12 @@ func (*UnimplementedWorkflowServer) PluginPutLog(Workflow_PluginPutLogServer) er
 func (*UnimplementedWorkflowServer) PluginComplete(context.Context, *PluginCompleteRequest) (*Result, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PluginComplete not implemented")
 }
+func (*UnimplementedWorkflowServer) WatchEvents(*WatchRequest, Workflow_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (*UnimplementedWorkflowServer) Cancel(context.Context, *CancelRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
 
 func RegisterWorkflowServer(s *grpc.Server, srv WorkflowServer) {
 	s.RegisterService(&_Workflow_serviceDesc, srv)
@@ -936,6 +1320,45 @@ func _Workflow_PluginComplete_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Workflow_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServer).WatchEvents(m, &workflowWatchEventsServer{stream})
+}
+
+type Workflow_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type workflowWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Workflow_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/workflow.Workflow/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Cancel(ctx, req.(*CancelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Workflow_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "workflow.Workflow",
 	HandlerType: (*WorkflowServer)(nil),
@@ -948,6 +1371,10 @@ var _Workflow_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PluginComplete",
 			Handler:    _Workflow_PluginComplete_Handler,
 		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Workflow_Cancel_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -955,6 +1382,11 @@ var _Workflow_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Workflow_PluginPutLog_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Workflow_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/proto/workflow.proto",
 }
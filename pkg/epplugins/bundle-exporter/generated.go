@@ -0,0 +1,68 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Auto generated, do not modify.
+
+package bundleexporter
+
+import (
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	epplugin "github.com/intel/edge-conductor/pkg/plugin"
+)
+
+var (
+	Name   = "bundle-exporter"
+	Input  = eputils.NewSchemaMapData()
+	Output = eputils.NewSchemaMapData()
+)
+
+//nolint:unparam,deadcode,unused
+func __name(n string) string {
+	return Name + "." + n
+}
+
+//nolint:deadcode,unused
+func input_ep_params(in eputils.SchemaMapData) *pluginapi.EpParams {
+	return in[__name("ep-params")].(*pluginapi.EpParams)
+}
+
+//nolint:deadcode,unused
+func input_cluster_images(in eputils.SchemaMapData) *pluginapi.Images {
+	return in[__name("cluster-images")].(*pluginapi.Images)
+}
+
+//nolint:deadcode,unused
+func input_cluster_files(in eputils.SchemaMapData) *pluginapi.Files {
+	return in[__name("cluster-files")].(*pluginapi.Files)
+}
+
+//nolint:deadcode,unused
+func input_service_images(in eputils.SchemaMapData) *pluginapi.Images {
+	return in[__name("service-images")].(*pluginapi.Images)
+}
+
+//nolint:deadcode,unused
+func input_service_files(in eputils.SchemaMapData) *pluginapi.Files {
+	return in[__name("service-files")].(*pluginapi.Files)
+}
+
+func init() {
+	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
+	eputils.AddSchemaStruct(__name("cluster-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
+	eputils.AddSchemaStruct(__name("cluster-files"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
+	eputils.AddSchemaStruct(__name("service-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
+	eputils.AddSchemaStruct(__name("service-files"), func() eputils.SchemaStruct { return &pluginapi.Files{} })
+
+	Input[__name("ep-params")] = &pluginapi.EpParams{}
+	Input[__name("cluster-images")] = &pluginapi.Images{}
+	Input[__name("cluster-files")] = &pluginapi.Files{}
+	Input[__name("service-images")] = &pluginapi.Images{}
+	Input[__name("service-files")] = &pluginapi.Files{}
+
+	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
+}
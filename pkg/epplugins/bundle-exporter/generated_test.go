@@ -0,0 +1,107 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Auto generated, do not modify.
+
+package bundleexporter
+
+import (
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+//nolint:deadcode,unused
+func generate_input_ep_params(data []byte, in eputils.SchemaMapData) bool {
+	inputStruct := &pluginapi.EpParams{}
+	if data != nil {
+		if err := inputStruct.UnmarshalBinary(data); err != nil {
+			return false
+		}
+	}
+
+	in[__name("ep-params")] = inputStruct
+	return true
+}
+
+//nolint:deadcode,unused
+func generate_input_cluster_images(data []byte, in eputils.SchemaMapData) bool {
+	inputStruct := &pluginapi.Images{}
+	if data != nil {
+		if err := inputStruct.UnmarshalBinary(data); err != nil {
+			return false
+		}
+	}
+
+	in[__name("cluster-images")] = inputStruct
+	return true
+}
+
+//nolint:deadcode,unused
+func generate_input_cluster_files(data []byte, in eputils.SchemaMapData) bool {
+	inputStruct := &pluginapi.Files{}
+	if data != nil {
+		if err := inputStruct.UnmarshalBinary(data); err != nil {
+			return false
+		}
+	}
+
+	in[__name("cluster-files")] = inputStruct
+	return true
+}
+
+//nolint:deadcode,unused
+func generate_input_service_images(data []byte, in eputils.SchemaMapData) bool {
+	inputStruct := &pluginapi.Images{}
+	if data != nil {
+		if err := inputStruct.UnmarshalBinary(data); err != nil {
+			return false
+		}
+	}
+
+	in[__name("service-images")] = inputStruct
+	return true
+}
+
+//nolint:deadcode,unused
+func generate_input_service_files(data []byte, in eputils.SchemaMapData) bool {
+	inputStruct := &pluginapi.Files{}
+	if data != nil {
+		if err := inputStruct.UnmarshalBinary(data); err != nil {
+			return false
+		}
+	}
+
+	in[__name("service-files")] = inputStruct
+	return true
+}
+
+//nolint:deadcode,unused,unparam
+func generateInput(data map[string][]byte) eputils.SchemaMapData {
+	n := eputils.NewSchemaMapData()
+	if result := generate_input_ep_params(data["ep-params"], n); !result {
+		return nil
+	}
+	if result := generate_input_cluster_images(data["cluster-images"], n); !result {
+		return nil
+	}
+	if result := generate_input_cluster_files(data["cluster-files"], n); !result {
+		return nil
+	}
+	if result := generate_input_service_images(data["service-images"], n); !result {
+		return nil
+	}
+	if result := generate_input_service_files(data["service-files"], n); !result {
+		return nil
+	}
+	return n
+}
+
+//nolint:unparam,deadcode,unused
+func generateOutput(data map[string][]byte) eputils.SchemaMapData {
+	n := eputils.NewSchemaMapData()
+	return n
+}
@@ -0,0 +1,137 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Template auto-generated once, maintained by plugin owner.
+
+package bundleexporter
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	epapi "github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	bundleutils "github.com/intel/edge-conductor/pkg/eputils/bundleutils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	log "github.com/sirupsen/logrus"
+)
+
+func exportImages(dir string, manifest *epapi.Bundlemanifest, images []*pluginapi.ImagesItems0) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	imagesFromHost, err := docker.GetHostImages()
+	if err != nil {
+		return err
+	}
+
+	exported := map[string]bool{}
+	for _, img := range images {
+		if exported[img.URL] {
+			continue
+		}
+		exported[img.URL] = true
+
+		if _, ok := (*imagesFromHost)[strings.TrimPrefix(img.URL, "docker.io/")]; !ok {
+			log.Infof("Pull image %s", img.URL)
+			if err := docker.ImagePull(img.URL, nil); err != nil {
+				return err
+			}
+		}
+		if err := bundleutils.AddImage(dir, manifest, img.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportFiles(dir, tmpFolder string, manifest *epapi.Bundlemanifest, files []*pluginapi.FilesItems0) error {
+	exported := map[string]bool{}
+	for _, file := range files {
+		if exported[file.URL] {
+			continue
+		}
+		exported[file.URL] = true
+
+		targetFile := filepath.Join(tmpFolder, path.Base(file.URL))
+		if err := eputils.CreateFolderIfNotExist(tmpFolder); err != nil {
+			return err
+		}
+
+		if len(file.Mirrorurl) > 0 {
+			if err := repoutils.PullFileFromRepo(targetFile, file.Mirrorurl); err != nil {
+				return err
+			}
+		} else {
+			log.Infof("Downloading %s", file.URL)
+			if err := eputils.DownloadFile(targetFile, file.URL); err != nil {
+				log.Errorln("Failed to download", file.URL, err)
+				return eputils.GetError("errDownload")
+			}
+		}
+		if len(file.Hash) > 0 && file.Hashtype == pluginapi.FilesItems0HashtypeSha256 {
+			if err := eputils.CheckFileSHA256(targetFile, file.Hash); err != nil {
+				log.Errorln("SHA256 check failed for", file.URL)
+				return err
+			}
+		}
+
+		subref := ""
+		if file.Urlreplacement != nil {
+			subref = file.Urlreplacement.New
+		}
+		if err := bundleutils.AddFile(dir, manifest, file.URL, subref, targetFile); err != nil {
+			return err
+		}
+		if err := eputils.RemoveFile(targetFile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
+	input_ep_params := input_ep_params(in)
+	input_cluster_images := input_cluster_images(in)
+	input_cluster_files := input_cluster_files(in)
+	input_service_images := input_service_images(in)
+	input_service_files := input_service_files(in)
+
+	dir := bundleutils.StagingDir(input_ep_params.Runtimedir)
+	if err := os.RemoveAll(dir); err != nil {
+		log.Errorln("Failed to clean", dir, err)
+		return err
+	}
+	if err := eputils.MakeDir(dir); err != nil {
+		return err
+	}
+
+	tmpFolder := filepath.Join(input_ep_params.Runtimedir, "tmp")
+	defer func() {
+		if err := os.RemoveAll(tmpFolder); err != nil {
+			log.Errorln("failed to remove", tmpFolder, err)
+		}
+	}()
+
+	manifest := &epapi.Bundlemanifest{}
+	images := append(input_cluster_images.Images, input_service_images.Images...)
+	if err := exportImages(dir, manifest, images); err != nil {
+		return err
+	}
+	files := append(input_cluster_files.Files, input_service_files.Files...)
+	if err := exportFiles(dir, tmpFolder, manifest, files); err != nil {
+		return err
+	}
+
+	log.Infof("Bundle content: %d images, %d files", len(manifest.Images), len(manifest.Files))
+	return eputils.SaveSchemaStructToYamlFile(manifest, filepath.Join(dir, bundleutils.ManifestFile))
+}
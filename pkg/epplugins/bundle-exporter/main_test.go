@@ -0,0 +1,197 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Template auto-generated once, maintained by plugin owner.
+
+// nolint: dupl
+package bundleexporter
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	epapi "github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	bundleutils "github.com/intel/edge-conductor/pkg/eputils/bundleutils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	"github.com/stretchr/testify/require"
+	"github.com/undefinedlabs/go-mpatch"
+)
+
+var (
+	testErr = fmt.Errorf("test error")
+)
+
+const (
+	fakefileSha256 = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+)
+
+func getRuntimeFolder() string {
+	_, cf, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	return filepath.Dir(cf)
+}
+
+func unpatch(t *testing.T, m *mpatch.Patch) {
+	err := m.Unpatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func patchGetHostImages(t *testing.T, images map[string]int) *mpatch.Patch {
+	patch, err := mpatch.PatchMethod(docker.GetHostImages, func() (*map[string]int, error) {
+		return &images, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return patch
+}
+
+func patchImagePull(t *testing.T, retError error) *mpatch.Patch {
+	patch, err := mpatch.PatchMethod(docker.ImagePull, func(string, *types.AuthConfig) error {
+		return retError
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return patch
+}
+
+func patchImageSave(t *testing.T) *mpatch.Patch {
+	patch, err := mpatch.PatchMethod(docker.ImageSave, func(images []string, tarball string) error {
+		return eputils.WriteStringToFile(images[0], tarball)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return patch
+}
+
+func TestPluginMain(t *testing.T) {
+	fakefile := filepath.Join(getRuntimeFolder(), "testdata", "fakefile")
+	fileItem := `{"url":"file://` + fakefile + `","hash":"` + fakefileSha256 + `","hashtype":"sha256","urlreplacement":{"new":"test"}}`
+
+	cases := []struct {
+		name           string
+		input          map[string][]byte
+		funcBeforeTest func(*testing.T) []*mpatch.Patch
+		expectManifest *epapi.Bundlemanifest
+		expectError    error
+	}{
+		{
+			name: "Success: images and files",
+			input: map[string][]byte{
+				"cluster-images": []byte(`{"images":[{"name":"a","url":"docker.io/library/a:1.0"},{"name":"b","url":"b:1.0"}]}`),
+				"cluster-files":  []byte(`{"files":[` + fileItem + `]}`),
+				"service-images": []byte(`{"images":[{"name":"a","url":"docker.io/library/a:1.0"}]}`),
+				"service-files":  []byte(`{"files":[` + fileItem + `]}`),
+			},
+			funcBeforeTest: func(t *testing.T) []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchGetHostImages(t, map[string]int{"library/a:1.0": 1}),
+					patchImagePull(t, nil),
+					patchImageSave(t),
+				}
+			},
+			expectManifest: &epapi.Bundlemanifest{
+				Images: []*epapi.BundlemanifestImagesItems0{
+					{URL: "docker.io/library/a:1.0", File: "images/0.tar"},
+					{URL: "b:1.0", File: "images/1.tar"},
+				},
+				Files: []*epapi.BundlemanifestFilesItems0{
+					{URL: "file://" + fakefile, Subref: "test", File: "files/0/fakefile", Sha256: fakefileSha256},
+				},
+			},
+		},
+		{
+			name: "Error Case: pull image failed",
+			input: map[string][]byte{
+				"cluster-images": []byte(`{"images":[{"name":"b","url":"b:1.0"}]}`),
+			},
+			funcBeforeTest: func(t *testing.T) []*mpatch.Patch {
+				return []*mpatch.Patch{
+					patchGetHostImages(t, map[string]int{}),
+					patchImagePull(t, testErr),
+				}
+			},
+			expectError: testErr,
+		},
+		{
+			name: "Error Case: download failed",
+			input: map[string][]byte{
+				"cluster-files": []byte(`{"files":[{"url":"file://nodata"}]}`),
+			},
+			expectError: eputils.GetError("errDownload"),
+		},
+		{
+			name: "Error Case: invalid sha256",
+			input: map[string][]byte{
+				"cluster-files": []byte(`{"files":[{"url":"file://` + fakefile + `","hash":"abc","hashtype":"sha256"}]}`),
+			},
+			expectError: eputils.GetError("errShaCheckFailed"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			runtimedir := t.TempDir()
+			tc.input["ep-params"] = []byte(`{"runtimedir":"` + runtimedir + `"}`)
+			input := generateInput(tc.input)
+			if input == nil {
+				t.Fatalf("Failed to generateInput %s", tc.input)
+			}
+			testOutput := generateOutput(nil)
+
+			if tc.funcBeforeTest != nil {
+				for _, p := range tc.funcBeforeTest(t) {
+					defer unpatch(t, p)
+				}
+			}
+
+			err := PluginMain(input, &testOutput)
+			if tc.expectError != nil {
+				require.Equal(t, tc.expectError, err)
+				return
+			}
+			require.NoError(t, err)
+
+			manifest := &epapi.Bundlemanifest{}
+			dir := bundleutils.StagingDir(runtimedir)
+			require.NoError(t, eputils.LoadSchemaStructFromYamlFile(manifest, filepath.Join(dir, bundleutils.ManifestFile)))
+			for i, img := range tc.expectManifest.Images {
+				img.Sha256, _ = eputils.GenFileSHA256(filepath.Join(dir, img.File))
+				require.Equal(t, img, manifest.Images[i])
+			}
+			require.Len(t, manifest.Images, len(tc.expectManifest.Images))
+			require.Equal(t, tc.expectManifest.Files, manifest.Files)
+			require.FileExists(t, filepath.Join(dir, "files", "0", "fakefile"))
+			require.NoDirExists(t, filepath.Join(runtimedir, "tmp"))
+		})
+	}
+}
+
+func TestInitStructFunc(t *testing.T) {
+	schemaStruct := eputils.SchemaStructNew(__name("ep-params"))
+	_, ok := schemaStruct.(*pluginapi.EpParams)
+	require.True(t, ok)
+
+	schemaStruct = eputils.SchemaStructNew(__name("cluster-images"))
+	_, ok = schemaStruct.(*pluginapi.Images)
+	require.True(t, ok)
+
+	schemaStruct = eputils.SchemaStructNew(__name("service-files"))
+	_, ok = schemaStruct.(*pluginapi.Files)
+	require.True(t, ok)
+}
@@ -92,16 +92,16 @@ nodes:
 containerdConfigPatches:
   - |-
       [plugins."io.containerd.grpc.v1.cri".registry.mirrors."k8s.gcr.io"]
-        endpoint = ["https://{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}/v2/k8s.gcr.io", "https://k8s.gcr.io"]
+        endpoint = ["https://{{ registryaddr .Kitconfig }}/v2/k8s.gcr.io", "https://k8s.gcr.io"]
       [plugins."io.containerd.grpc.v1.cri".registry.mirrors."gcr.io"]
-        endpoint = ["https://{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}/v2/gcr.io", "https://gcr.io"]
+        endpoint = ["https://{{ registryaddr .Kitconfig }}/v2/gcr.io", "https://gcr.io"]
       [plugins."io.containerd.grpc.v1.cri".registry.mirrors."quay.io"]
-        endpoint = ["https://{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}/v2/quay.io", "https://quay.io"]
+        endpoint = ["https://{{ registryaddr .Kitconfig }}/v2/quay.io", "https://quay.io"]
       [plugins."io.containerd.grpc.v1.cri".registry.mirrors."projects.registry.vmware.com"]
-        endpoint = ["https://{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}/v2/projects.registry.vmware.com/", "https://projects.registry.vmware.com/"]
-      [plugins."io.containerd.grpc.v1.cri".registry.configs."{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}".tls]
-        ca_file = "/etc/containerd/certs.d/{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}/ca.crt"
-      [plugins."io.containerd.grpc.v1.cri".registry.configs."{{ .Kitconfig.Parameters.GlobalSettings.ProviderIP }}:{{ .Kitconfig.Parameters.GlobalSettings.RegistryPort }}".auth]
+        endpoint = ["https://{{ registryaddr .Kitconfig }}/v2/projects.registry.vmware.com/", "https://projects.registry.vmware.com/"]
+      [plugins."io.containerd.grpc.v1.cri".registry.configs."{{ registryaddr .Kitconfig }}".tls]
+        ca_file = "/etc/containerd/certs.d/{{ registryaddr .Kitconfig }}/ca.crt"
+      [plugins."io.containerd.grpc.v1.cri".registry.configs."{{ registryaddr .Kitconfig }}".auth]
         username = "{{ .Kitconfig.Parameters.Customconfig.Registry.User }}"
         password = "{{ .Kitconfig.Parameters.Customconfig.Registry.Password }}"
 `
@@ -186,9 +186,7 @@ func launchManagementCluster(ep_params *pluginapi.EpParams, clusterManifest *plu
 		return eputils.GetError("errKitConfigParm")
 	}
 
-	imageUrl := ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP +
-		":" + ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort +
-		"/docker.io/" + kindImageNode
+	imageUrl := eputils.RegistryAddress(ep_params.Kitconfig) + "/docker.io/" + kindImageNode
 
 	// If mngr cluster exist, it will be deleled first.
 	cmd := exec.Command(kindBin, "delete", "cluster", "--name", capiutils.MANAGEMENT_CLUSTER_NAME)
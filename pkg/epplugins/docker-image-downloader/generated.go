@@ -36,12 +36,19 @@ func input_docker_images(in eputils.SchemaMapData) *pluginapi.Images {
 	return in[__name("docker-images")].(*pluginapi.Images)
 }
 
+//nolint:deadcode,unused
+func output_docker_images(outp *eputils.SchemaMapData) *pluginapi.Images {
+	return (*outp)[__name("docker-images")].(*pluginapi.Images)
+}
+
 func init() {
 	eputils.AddSchemaStruct(__name("ep-params"), func() eputils.SchemaStruct { return &pluginapi.EpParams{} })
 	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
+	eputils.AddSchemaStruct(__name("docker-images"), func() eputils.SchemaStruct { return &pluginapi.Images{} })
 
 	Input[__name("ep-params")] = &pluginapi.EpParams{}
 	Input[__name("docker-images")] = &pluginapi.Images{}
+	Output[__name("docker-images")] = &pluginapi.Images{}
 
 	epplugin.RegisterPlugin(Name, &Input, &Output, PluginMain)
 }
@@ -52,8 +52,24 @@ func generateInput(data map[string][]byte) eputils.SchemaMapData {
 	return n
 }
 
+//nolint:deadcode,unused
+func generate_output_docker_images(data []byte, out eputils.SchemaMapData) bool {
+	outputStruct := &pluginapi.Images{}
+	if data != nil {
+		if err := outputStruct.UnmarshalBinary(data); err != nil {
+			return false
+		}
+	}
+
+	out[__name("docker-images")] = outputStruct
+	return true
+}
+
 //nolint:unparam,deadcode,unused
 func generateOutput(data map[string][]byte) eputils.SchemaMapData {
 	n := eputils.NewSchemaMapData()
+	if result := generate_output_docker_images(data["docker-images"], n); !result {
+		return nil
+	}
 	return n
 }
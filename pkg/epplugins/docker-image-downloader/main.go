@@ -9,18 +9,114 @@
 package dockerimagedownloader
 
 import (
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
-	restfulcli "github.com/intel/edge-conductor/pkg/eputils/restfulcli"
+	registryutils "github.com/intel/edge-conductor/pkg/eputils/registryutils"
+	"io/ioutil"
 	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
 var (
 	DayZeroCertFilePath = "cert/pki/ca.pem"
+
+	// Number of images pulled or pushed at the same time.
+	mirrorWorkers = 4
+	// Number of retries of a failed pull or push. The wait before the
+	// first retry is mirrorBackoff, and it is doubled after each retry.
+	mirrorRetries = 3
+	mirrorBackoff = 2 * time.Second
 )
 
+// mirrorReport lists the images verified, pulled from the internet, pushed
+// to the day-0 registry, skipped because the registry is up to date, and
+// failed.
+type mirrorReport struct {
+	verified []string
+	pulled   []string
+	pushed   []string
+	skipped  []string
+	failed   []string
+	errs     []error
+}
+
+func (r *mirrorReport) fail(image string, err error) {
+	r.failed = append(r.failed, image)
+	r.errs = append(r.errs, err)
+}
+
+func (r *mirrorReport) print() {
+	if len(r.verified) > 0 {
+		log.Infof("Verified %d images: %s", len(r.verified), strings.Join(r.verified, ", "))
+	}
+	log.Infof("Pulled %d images: %s", len(r.pulled), strings.Join(r.pulled, ", "))
+	log.Infof("Pushed %d images: %s", len(r.pushed), strings.Join(r.pushed, ", "))
+	log.Infof("Skipped %d up-to-date images: %s", len(r.skipped), strings.Join(r.skipped, ", "))
+	if len(r.failed) > 0 {
+		log.Errorf("Failed %d images:", len(r.failed))
+		for i, img := range r.failed {
+			log.Errorf("  %s: %v", img, r.errs[i])
+		}
+	}
+}
+
+// err returns the error of the first failed image.
+func (r *mirrorReport) err() error {
+	if len(r.errs) > 0 {
+		return r.errs[0]
+	}
+	return nil
+}
+
+// withRetry runs op until it succeeds or fails mirrorRetries more times.
+func withRetry(name string, op func() error) error {
+	backoff := mirrorBackoff
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= mirrorRetries {
+			return err
+		}
+		log.Warnf("%s failed: %v, retry in %v", name, err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// forEachImage runs job for the images 0..n-1 on at most mirrorWorkers
+// goroutines, and returns the error of each image.
+func forEachImage(n int, job func(i int) error) []error {
+	errs := make([]error, n)
+	workers := mirrorWorkers
+	if workers > n {
+		workers = n
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = job(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
+
 func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	input_ep_params := input_ep_params(in)
 	input_docker_images := input_docker_images(in)
@@ -33,68 +129,248 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		return eputils.GetError("errKitCfgParameter")
 	}
 
-	imagesFromHost, err := docker.GetHostImages()
-	if err != nil {
-		return err
+	// Without the docker daemon, for example on a host running only
+	// containerd, images are copied between the registries directly.
+	daemonless := !docker.DaemonAvailable()
+	imagesFromHost := &map[string]int{}
+	if !daemonless {
+		var err error
+		if imagesFromHost, err = docker.GetHostImages(); err != nil {
+			return err
+		}
 	}
 
-	auth, err := docker.GetAuthConf(input_ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP,
-		input_ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.User,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.Password)
+	reg, err := registryutils.NewRegistry(input_ep_params)
 	if err != nil {
 		return err
 	}
 
-	var newImages []string
+	// Verified images are pulled again by the digests they were verified
+	// with, so that the images mirrored are the content verified, even if
+	// their tags are moved after the verification.
+	conf := input_ep_params.Kitconfig.Parameters.Customconfig.ImageVerification
+	forceDownload := conf != nil || eputils.CheckCmdline(input_ep_params.Cmdline, "force-download")
+	var images []string
 	var images_download []string
-	var images_push_to_harbor []string
-	var url string
-	if eputils.CheckCmdline(input_ep_params.Cmdline, "force-download") {
-		for _, img := range input_docker_images.Images {
-			url = img.URL
-			images_download = append(images_download, url)
-			images_push_to_harbor = append(images_push_to_harbor, url)
+	found := map[string]bool{}
+	for _, img := range input_docker_images.Images {
+		if found[img.URL] {
+			continue
 		}
-	} else {
-		for _, img := range input_docker_images.Images {
-			url = img.URL
-			images_push_to_harbor = append(images_push_to_harbor, url)
-
-			tmpStr := strings.TrimPrefix(url, "docker.io/")
-			if _, ok := (*imagesFromHost)[tmpStr]; ok {
+		found[img.URL] = true
+		images = append(images, img.URL)
+		if !forceDownload && imagesFromHost != nil {
+			if _, ok := (*imagesFromHost)[strings.TrimPrefix(img.URL, "docker.io/")]; ok {
 				continue
 			}
-			images_download = append(images_download, url)
 		}
+		images_download = append(images_download, img.URL)
+	}
+
+	report := &mirrorReport{}
+	verification := verifyImages(report, images, conf)
+	output_docker_images := output_docker_images(outp)
+	for _, img := range input_docker_images.Images {
+		output_docker_images.Images = append(output_docker_images.Images, &pluginapi.ImagesItems0{
+			Name:         img.Name,
+			URL:          img.URL,
+			Verification: verification[img.URL],
+		})
+	}
+	var pinned map[string]string
+	if verification != nil {
+		pinned = pinImages(report, verification)
+		images = filterPinned(images, pinned)
+		images_download = filterPinned(images_download, pinned)
+	}
+
+	if daemonless {
+		log.Infof("Docker daemon is not available, copy images to %s directly", reg.Address())
+		err = copyImages(report, images, pinned, reg)
+	} else {
+		err = pullPushImages(report, images, images_download, pinned, reg)
+	}
+	if err != nil {
+		return err
+	}
+	report.print()
+	return report.err()
+}
+
+// verifyImages verifies the images on their registries, and returns the
+// verification result of each image. Nothing is verified without the
+// image verification config.
+func verifyImages(report *mirrorReport, images []string, conf *pluginapi.CustomconfigImageVerification) map[string]*pluginapi.ImagesItems0Verification {
+	if conf == nil {
+		return nil
+	}
+	results := make([]*pluginapi.ImagesItems0Verification, len(images))
+	verifyErrs := forEachImage(len(images), func(i int) (err error) {
+		log.Infof("Verify image %s", images[i])
+		results[i], err = docker.ImageVerify(images[i], conf)
+		return err
+	})
+	verification := map[string]*pluginapi.ImagesItems0Verification{}
+	for i, err := range verifyErrs {
+		verification[images[i]] = results[i]
+		if err != nil {
+			report.fail(images[i], err)
+		} else {
+			report.verified = append(report.verified, images[i])
+		}
+	}
+	return verification
+}
+
+// pinImages returns the references by digest of the verified images.
+func pinImages(report *mirrorReport, verification map[string]*pluginapi.ImagesItems0Verification) map[string]string {
+	pinned := map[string]string{}
+	for img, v := range verification {
+		if v == nil || v.Result != pluginapi.ImagesItems0VerificationResultVerified {
+			continue
+		}
+		ref, err := docker.ImagePinned(img, v.Digest)
+		if err != nil {
+			report.fail(img, err)
+			continue
+		}
+		pinned[img] = ref
+	}
+	return pinned
+}
+
+// filterPinned returns the images which are verified and pinned.
+func filterPinned(images []string, pinned map[string]string) []string {
+	var verified []string
+	for _, img := range images {
+		if _, ok := pinned[img]; ok {
+			verified = append(verified, img)
+		}
+	}
+	return verified
+}
+
+// imageSource returns the reference an image is fetched by, which is the
+// verified digest if the image is pinned.
+func imageSource(img string, pinned map[string]string) string {
+	if ref, ok := pinned[img]; ok {
+		return ref
 	}
-	for _, v := range images_download {
-		log.Infof("Pull image %s", v)
-		if err := docker.ImagePull(v, nil); err != nil {
+	return img
+}
+
+// pullPushImages pulls the images to the local docker, and pushes them to
+// the day-0 registry unless it is up to date. Pinned images are pulled by
+// digest and tagged locally.
+func pullPushImages(report *mirrorReport, images, images_download []string, pinned map[string]string, reg registryutils.Registry) error {
+	// Progress bars of concurrent pulls and pushes are interleaved, so
+	// only the per-image log is shown.
+	prev := docker.SetProgressOutput(ioutil.Discard)
+	defer docker.SetProgressOutput(prev)
+
+	pullFailed := map[string]bool{}
+	pullErrs := forEachImage(len(images_download), func(i int) error {
+		src := imageSource(images_download[i], pinned)
+		log.Infof("Pull image %s", src)
+		err := withRetry("Pull "+src, func() error {
+			return docker.ImagePull(src, nil)
+		})
+		if err != nil || src == images_download[i] {
 			return err
 		}
+		return docker.TagImage(src, images_download[i])
+	})
+	for i, err := range pullErrs {
+		if err != nil {
+			pullFailed[images_download[i]] = true
+			report.fail(images_download[i], err)
+		} else {
+			report.pulled = append(report.pulled, images_download[i])
+		}
 	}
 
-	if newImages, err = restfulcli.MapImageURLCreateHarborProject(input_ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP,
-		input_ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.User,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.Password, images_push_to_harbor); err != nil {
-		return err
+	var images_push_to_harbor []string
+	for _, url := range images {
+		if !pullFailed[url] {
+			images_push_to_harbor = append(images_push_to_harbor, url)
+		}
+	}
+	if len(images_push_to_harbor) == 0 {
+		return nil
 	}
 
-	for _, img := range newImages {
-		prefixUrl := img
+	newImages, err := reg.MapImages(images_push_to_harbor)
+	if err != nil {
+		return err
+	}
+	if len(newImages) != len(images_push_to_harbor) {
+		return eputils.GetError("errHarborResponse")
+	}
+	auth := reg.AuthConf()
 
+	skipped := make([]bool, len(newImages))
+	pushErrs := forEachImage(len(newImages), func(i int) error {
+		prefixUrl := newImages[i]
 		newTag, err := docker.TagImageToLocal(prefixUrl, auth.ServerAddress)
 		if err != nil {
 			return err
 		}
+		upToDate, err := docker.ImageUpToDate(newTag, auth)
+		if err != nil {
+			log.Warnf("Failed to check %s on the registry: %v", newTag, err)
+		}
+		if upToDate {
+			log.Infof("Skip %s, the registry is up to date", newTag)
+			skipped[i] = true
+			return nil
+		}
 		log.Infof("Push %s to %s", prefixUrl, newTag)
-		if err := docker.ImagePush(newTag, auth); err != nil {
-			return err
+		return withRetry("Push "+newTag, func() error {
+			return docker.ImagePush(newTag, auth)
+		})
+	})
+	for i, err := range pushErrs {
+		if err != nil {
+			report.fail(newImages[i], err)
+		} else if skipped[i] {
+			report.skipped = append(report.skipped, newImages[i])
+		} else {
+			report.pushed = append(report.pushed, newImages[i])
 		}
+	}
+	return nil
+}
 
+// copyImages copies the images from their registries to the day-0
+// registry without the local docker. Pinned images are copied by digest.
+func copyImages(report *mirrorReport, images []string, pinned map[string]string, reg registryutils.Registry) error {
+	newImages, err := reg.MapImages(images)
+	if err != nil {
+		return err
+	}
+	if len(newImages) != len(images) {
+		return eputils.GetError("errHarborResponse")
 	}
+	auth, cacert := reg.AuthConf(), reg.CACert()
 
+	copied := make([]bool, len(images))
+	copyErrs := forEachImage(len(images), func(i int) error {
+		newTag := docker.GetImageNewTag(newImages[i], auth.ServerAddress)
+		src := imageSource(images[i], pinned)
+		log.Infof("Copy %s to %s", src, newTag)
+		return withRetry("Copy "+src, func() (err error) {
+			copied[i], err = docker.ImageCopy(src, newTag, auth, cacert)
+			return err
+		})
+	})
+	for i, err := range copyErrs {
+		if err != nil {
+			report.fail(images[i], err)
+		} else if copied[i] {
+			report.pushed = append(report.pushed, newImages[i])
+		} else {
+			report.skipped = append(report.skipped, newImages[i])
+		}
+	}
 	return nil
 }
@@ -13,15 +13,21 @@ package dockerimagedownloader
 import (
 	"errors"
 	"fmt"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	"github.com/intel/edge-conductor/pkg/eputils"
+	"strings"
+	"sync"
+	"sync/atomic"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
 	dockermock "github.com/intel/edge-conductor/pkg/eputils/docker/mock"
 	restfulcli "github.com/intel/edge-conductor/pkg/eputils/restfulcli"
 	restfulmock "github.com/intel/edge-conductor/pkg/eputils/restfulcli/mock"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	gomock "github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
 	mpatch "github.com/undefinedlabs/go-mpatch"
 )
 
@@ -36,7 +42,21 @@ func unpatch(t *testing.T, m *mpatch.Patch) {
 	}
 }
 
+func patchDaemonAvailable(t *testing.T, available bool) *mpatch.Patch {
+	patch, err := mpatch.PatchMethod(docker.DaemonAvailable, func() bool {
+		return available
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return patch
+}
+
 func TestPluginMain(t *testing.T) {
+	defer func(backoff time.Duration) { mirrorBackoff = backoff }(mirrorBackoff)
+	mirrorBackoff = 0
+	defer unpatch(t, patchDaemonAvailable(t, true))
+
 	func_Imagedownloader_successful := func(ctrl *gomock.Controller, ctrl1 *gomock.Controller) []*mpatch.Patch {
 		mockDockerCli := dockermock.NewMockDockerClientWrapperImage(ctrl)
 		mockRestyCli := restfulmock.NewMockGoharborClientWrapper(ctrl1)
@@ -77,7 +97,7 @@ func TestPluginMain(t *testing.T) {
 		fakeAuth := &types.AuthConfig{ServerAddress: "10.10.10.10"}
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(fakeAuth, nil)
 		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
-		fakeNewImages := []string{"aaa", "bbb"}
+		fakeNewImages := []string{"aaa"}
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeNewImages, nil)
 		mockDockerCli.EXPECT().TagImageToLocal(gomock.Any(), gomock.Any()).AnyTimes().Return("", nil)
 		mockDockerCli.EXPECT().ImagePush(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
@@ -170,6 +190,41 @@ func TestPluginMain(t *testing.T) {
 		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull, patchMapImageURLCreateHarborProject}
 	}
 
+	func_HarborResponse_err := func(ctrl *gomock.Controller, ctrl1 *gomock.Controller) []*mpatch.Patch {
+		mockDockerCli := dockermock.NewMockDockerClientWrapperImage(ctrl)
+		mockRestyCli := restfulmock.NewMockGoharborClientWrapper(ctrl1)
+		patchGetHostImages, err := mpatch.PatchMethod(docker.GetHostImages, mockDockerCli.GetHostImages)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		patchGetAuthConf, err := mpatch.PatchMethod(docker.GetAuthConf, mockDockerCli.GetAuthConf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patchForcedownload, err := mpatch.PatchMethod(eputils.CheckCmdline, func(cmdline string, cmd string) bool {
+			return true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		patchImagePull, err := mpatch.PatchMethod(docker.ImagePull, mockDockerCli.ImagePull)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patchMapImageURLCreateHarborProject, err := mpatch.PatchMethod(restfulcli.MapImageURLCreateHarborProject, mockRestyCli.MapImageURLCreateHarborProject)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mockDockerCli.EXPECT().GetHostImages().AnyTimes().Return(nil, nil)
+		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+		// Harbor maps fewer images than requested.
+		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return([]string{"aaa"}, nil)
+		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull, patchMapImageURLCreateHarborProject}
+	}
+
 	func_TagImageToLocal_err := func(ctrl *gomock.Controller, ctrl1 *gomock.Controller) []*mpatch.Patch {
 		mockDockerCli := dockermock.NewMockDockerClientWrapperImage(ctrl)
 		mockRestyCli := restfulmock.NewMockGoharborClientWrapper(ctrl1)
@@ -205,7 +260,7 @@ func TestPluginMain(t *testing.T) {
 		fakeAuth := &types.AuthConfig{ServerAddress: "10.10.10.10"}
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(fakeAuth, nil)
 		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
-		fakeNewImages := []string{"aaa", "bbb"}
+		fakeNewImages := []string{"aaa"}
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeNewImages, nil)
 		mockDockerCli.EXPECT().TagImageToLocal(gomock.Any(), gomock.Any()).AnyTimes().Return("", errTest)
 		return []*mpatch.Patch{patchGetHostImages, patchGetAuthConf, patchForcedownload, patchImagePull, patchMapImageURLCreateHarborProject, patchTagImageToLocal}
@@ -250,7 +305,7 @@ func TestPluginMain(t *testing.T) {
 		fakeAuth := &types.AuthConfig{ServerAddress: "10.10.10.10"}
 		mockDockerCli.EXPECT().GetAuthConf(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(fakeAuth, nil)
 		mockDockerCli.EXPECT().ImagePull(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
-		fakeNewImages := []string{"aaa", "bbb"}
+		fakeNewImages := []string{"aaa"}
 		mockRestyCli.EXPECT().MapImageURLCreateHarborProject(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fakeNewImages, nil)
 		mockDockerCli.EXPECT().TagImageToLocal(gomock.Any(), gomock.Any()).AnyTimes().Return("", nil)
 		mockDockerCli.EXPECT().ImagePush(gomock.Any(), gomock.Any()).AnyTimes().Return(errTest)
@@ -339,6 +394,22 @@ func TestPluginMain(t *testing.T) {
 			wantErr:        errTest,
 			funcBeforeTest: func_HarborProject_err,
 		},
+		{
+			name: "Harbor_response_err",
+			input: map[string][]byte{
+				"ep-params": []byte(`{
+					"kitconfig": {
+						"Parameters": {
+							"customconfig": {"registry": {"user": "test","password": "test123"}},
+							"global_settings": {"provider_ip": "10.10.10.10","registry_port": "5678"}}}}`),
+				"docker-images": []byte(`{"images": [
+					{"name": "test","url": "temp/hello-world:latest"},
+					{"name": "busybox","url": "temp/busybox:latest"}]}`),
+			},
+			expectError:    true,
+			wantErr:        eputils.GetError("errHarborResponse"),
+			funcBeforeTest: func_HarborResponse_err,
+		},
 		{
 			name: "Tag_ImageToLocal_err",
 			input: map[string][]byte{
@@ -421,3 +492,329 @@ func TestPluginMain(t *testing.T) {
 	}
 
 }
+
+func TestPluginMainMirror(t *testing.T) {
+	defer func(backoff time.Duration) { mirrorBackoff = backoff }(mirrorBackoff)
+	mirrorBackoff = 0
+	defer unpatch(t, patchDaemonAvailable(t, true))
+
+	input := generateInput(map[string][]byte{
+		"ep-params": []byte(`{
+			"kitconfig": {
+				"Parameters": {
+					"customconfig": {"registry": {"user": "test","password": "test123"}},
+					"global_settings": {"provider_ip": "10.10.10.10","registry_port": "5678"}}}}`),
+		"docker-images": []byte(`{"images": [
+			{"name": "a","url": "docker.io/library/a:1"},
+			{"name": "b","url": "b:1"},
+			{"name": "c","url": "c:1"},
+			{"name": "d","url": "d:1"},
+			{"name": "e","url": "e:1"},
+			{"name": "f","url": "f:1"},
+			{"name": "f","url": "f:1"}]}`),
+	})
+	require.NotNil(t, input)
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	count := func(op, image string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[op+" "+image]++
+		return calls[op+" "+image]
+	}
+	var active, maxActive int32
+	hostImages := map[string]int{"library/a:1": 1}
+
+	patches := []*mpatch.Patch{}
+	patch := func(target, redirection interface{}) {
+		p, err := mpatch.PatchMethod(target, redirection)
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	defer func() {
+		for _, p := range patches {
+			unpatch(t, p)
+		}
+	}()
+	patch(docker.GetHostImages, func() (*(map[string]int), error) {
+		return &hostImages, nil
+	})
+	patch(docker.GetAuthConf, func(harborIP, harborPort, user, password string) (*types.AuthConfig, error) {
+		return &types.AuthConfig{ServerAddress: "10.10.10.10:5678"}, nil
+	})
+	patch(eputils.CheckCmdline, func(cmdline string, cmd string) bool {
+		return false
+	})
+	patch(docker.ImagePull, func(imageRef string, authConf *types.AuthConfig) error {
+		n := count("pull", imageRef)
+		// b fails once and then succeeds, c always fails.
+		if (imageRef == "b:1" && n == 1) || imageRef == "c:1" {
+			return errTest
+		}
+		return nil
+	})
+	patch(restfulcli.MapImageURLCreateHarborProject, func(harborIP, harborPort, harborUser, harborPass string, image []string) ([]string, error) {
+		var newImages []string
+		for _, img := range image {
+			newImages = append(newImages, "library/"+strings.TrimPrefix(img, "docker.io/library/"))
+		}
+		return newImages, nil
+	})
+	patch(docker.TagImageToLocal, func(imageTag, registryURL string) (string, error) {
+		return registryURL + "/" + imageTag, nil
+	})
+	patch(docker.ImageUpToDate, func(imageRef string, authConf *types.AuthConfig) (bool, error) {
+		return imageRef == "10.10.10.10:5678/library/a:1", nil
+	})
+	patch(docker.ImagePush, func(imageRef string, authConf *types.AuthConfig) error {
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		count("push", imageRef)
+		if imageRef == "10.10.10.10:5678/library/e:1" {
+			return errTest
+		}
+		return nil
+	})
+
+	output := generateOutput(nil)
+	require.Equal(t, errTest, PluginMain(input, &output))
+
+	require.Equal(t, map[string]int{
+		"pull b:1": 2,
+		"pull c:1": mirrorRetries + 1,
+		"pull d:1": 1,
+		"pull e:1": 1,
+		"pull f:1": 1,
+		"push 10.10.10.10:5678/library/b:1": 1,
+		"push 10.10.10.10:5678/library/d:1": 1,
+		"push 10.10.10.10:5678/library/e:1": mirrorRetries + 1,
+		"push 10.10.10.10:5678/library/f:1": 1,
+	}, calls)
+	require.LessOrEqual(t, maxActive, int32(mirrorWorkers))
+}
+
+func TestPluginMainDaemonless(t *testing.T) {
+	defer func(backoff time.Duration) { mirrorBackoff = backoff }(mirrorBackoff)
+	mirrorBackoff = 0
+
+	input := generateInput(map[string][]byte{
+		"ep-params": []byte(`{
+			"registrycert": {"ca": {"cert": "ca.pem"}},
+			"kitconfig": {
+				"Parameters": {
+					"customconfig": {"registry": {"user": "test","password": "test123"}},
+					"global_settings": {"provider_ip": "10.10.10.10","registry_port": "5678"}}}}`),
+		"docker-images": []byte(`{"images": [
+			{"name": "a","url": "a:1"},
+			{"name": "b","url": "b:1"},
+			{"name": "c","url": "c:1"},
+			{"name": "c","url": "c:1"}]}`),
+	})
+	require.NotNil(t, input)
+
+	var mu sync.Mutex
+	copies := map[string]int{}
+	patches := []*mpatch.Patch{patchDaemonAvailable(t, false)}
+	patch := func(target, redirection interface{}) {
+		p, err := mpatch.PatchMethod(target, redirection)
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	defer func() {
+		for _, p := range patches {
+			unpatch(t, p)
+		}
+	}()
+	patch(docker.GetHostImages, func() (*(map[string]int), error) {
+		t.Error("Unexpected call of GetHostImages")
+		return nil, errTest
+	})
+	patch(restfulcli.MapImageURLCreateHarborProject, func(harborIP, harborPort, harborUser, harborPass string, image []string) ([]string, error) {
+		var newImages []string
+		for _, img := range image {
+			newImages = append(newImages, "library/"+img)
+		}
+		return newImages, nil
+	})
+	patch(docker.ImageCopy, func(srcRef, dstRef string, authConf *types.AuthConfig, cacert string) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		copies[srcRef+" "+dstRef+" "+cacert]++
+		switch srcRef {
+		case "a:1":
+			return true, nil
+		case "b:1":
+			return false, nil
+		}
+		return false, errTest
+	})
+
+	output := generateOutput(nil)
+	require.Equal(t, errTest, PluginMain(input, &output))
+	require.Equal(t, map[string]int{
+		"a:1 10.10.10.10:5678/library/a:1 ca.pem": 1,
+		"b:1 10.10.10.10:5678/library/b:1 ca.pem": 1,
+		"c:1 10.10.10.10:5678/library/c:1 ca.pem": mirrorRetries + 1,
+	}, copies)
+	// Without the image verification config, the images are not verified.
+	for _, img := range output_docker_images(&output).Images {
+		require.Nil(t, img.Verification)
+	}
+}
+
+const testDigest = "sha256:2d05f2e5ba9e3a0c7bc9ea6e0e4e3de4d8b1c8e6e2b1d9d0e3b2c7a1f0e9d8c7"
+
+func TestPluginMainVerify(t *testing.T) {
+	input := generateInput(map[string][]byte{
+		"ep-params": []byte(`{
+			"kitconfig": {
+				"Parameters": {
+					"customconfig": {
+						"registry": {"user": "test","password": "test123"},
+						"image_verification": {"signature": "cosign", "keys": ["cosign.pub"], "allowed_registries": ["docker.io"]}},
+					"global_settings": {"provider_ip": "10.10.10.10","registry_port": "5678"}}}}`),
+		"docker-images": []byte(`{"images": [
+			{"name": "a","url": "a:1"},
+			{"name": "b","url": "b:1"},
+			{"name": "b","url": "b:1"}]}`),
+	})
+	require.NotNil(t, input)
+
+	var mu sync.Mutex
+	var copies []string
+	patches := []*mpatch.Patch{patchDaemonAvailable(t, false)}
+	patch := func(target, redirection interface{}) {
+		p, err := mpatch.PatchMethod(target, redirection)
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	defer func() {
+		for _, p := range patches {
+			unpatch(t, p)
+		}
+	}()
+	patch(docker.ImageVerify, func(imageRef string, conf *pluginapi.CustomconfigImageVerification) (*pluginapi.ImagesItems0Verification, error) {
+		require.Equal(t, []string{"cosign.pub"}, conf.Keys)
+		if imageRef == "b:1" {
+			err := eputils.GetError("errImageSignature")
+			return &pluginapi.ImagesItems0Verification{Result: "failed", Error: err.Error()}, err
+		}
+		return &pluginapi.ImagesItems0Verification{Result: "verified", Digest: testDigest, Signature: "cosign", Key: "cosign.pub"}, nil
+	})
+	patch(restfulcli.MapImageURLCreateHarborProject, func(harborIP, harborPort, harborUser, harborPass string, image []string) ([]string, error) {
+		var newImages []string
+		for _, img := range image {
+			newImages = append(newImages, "library/"+img)
+		}
+		return newImages, nil
+	})
+	patch(docker.ImageCopy, func(srcRef, dstRef string, authConf *types.AuthConfig, cacert string) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		copies = append(copies, srcRef)
+		return true, nil
+	})
+
+	output := generateOutput(nil)
+	require.Equal(t, eputils.GetError("errImageSignature"), PluginMain(input, &output))
+	// The verified image is copied by the digest it was verified with.
+	require.Equal(t, []string{"docker.io/library/a@" + testDigest}, copies)
+
+	images := output_docker_images(&output).Images
+	require.Len(t, images, 3)
+	require.Equal(t, "a:1", images[0].URL)
+	require.Equal(t, "verified", images[0].Verification.Result)
+	require.Equal(t, testDigest, images[0].Verification.Digest)
+	for _, img := range images[1:] {
+		require.Equal(t, "b:1", img.URL)
+		require.Equal(t, "failed", img.Verification.Result)
+		require.Equal(t, eputils.GetError("errImageSignature").Error(), img.Verification.Error)
+	}
+}
+
+func TestPluginMainVerifyPull(t *testing.T) {
+	input := generateInput(map[string][]byte{
+		"ep-params": []byte(`{
+			"kitconfig": {
+				"Parameters": {
+					"customconfig": {
+						"registry": {"user": "test","password": "test123"},
+						"image_verification": {"signature": "cosign", "keys": ["cosign.pub"]}},
+					"global_settings": {"provider_ip": "10.10.10.10","registry_port": "5678"}}}}`),
+		"docker-images": []byte(`{"images": [
+			{"name": "a","url": "a:1"},
+			{"name": "b","url": "b:1"}]}`),
+	})
+	require.NotNil(t, input)
+
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	patches := []*mpatch.Patch{patchDaemonAvailable(t, true)}
+	patch := func(target, redirection interface{}) {
+		p, err := mpatch.PatchMethod(target, redirection)
+		require.NoError(t, err)
+		patches = append(patches, p)
+	}
+	defer func() {
+		for _, p := range patches {
+			unpatch(t, p)
+		}
+	}()
+	// The local images are verified again even if they are on the host.
+	patch(docker.GetHostImages, func() (*(map[string]int), error) {
+		return &map[string]int{"a:1": 1}, nil
+	})
+	patch(docker.ImageVerify, func(imageRef string, conf *pluginapi.CustomconfigImageVerification) (*pluginapi.ImagesItems0Verification, error) {
+		if imageRef == "b:1" {
+			// A verified result without a valid digest cannot be pinned.
+			return &pluginapi.ImagesItems0Verification{Result: "verified", Digest: "sha256:aaaa"}, nil
+		}
+		return &pluginapi.ImagesItems0Verification{Result: "verified", Digest: testDigest}, nil
+	})
+	patch(docker.ImagePull, func(imageRef string, authConf *types.AuthConfig) error {
+		record("pull " + imageRef)
+		return nil
+	})
+	patch(docker.TagImage, func(imageTag, newTag string) error {
+		record("tag " + imageTag + " " + newTag)
+		return nil
+	})
+	patch(restfulcli.MapImageURLCreateHarborProject, func(harborIP, harborPort, harborUser, harborPass string, image []string) ([]string, error) {
+		var newImages []string
+		for _, img := range image {
+			newImages = append(newImages, "library/"+img)
+		}
+		return newImages, nil
+	})
+	patch(docker.TagImageToLocal, func(imageTag, registryURL string) (string, error) {
+		return registryURL + "/" + imageTag, nil
+	})
+	patch(docker.ImageUpToDate, func(imageRef string, authConf *types.AuthConfig) (bool, error) {
+		return false, nil
+	})
+	patch(docker.ImagePush, func(imageRef string, authConf *types.AuthConfig) error {
+		record("push " + imageRef)
+		return nil
+	})
+
+	output := generateOutput(nil)
+	require.Equal(t, eputils.GetError("errImageNotVerified"), PluginMain(input, &output))
+	require.Equal(t, []string{
+		"pull docker.io/library/a@" + testDigest,
+		"tag docker.io/library/a@" + testDigest + " a:1",
+		"push 10.10.10.10:5678/library/a:1",
+	}, calls)
+}
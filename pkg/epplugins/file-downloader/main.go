@@ -16,10 +16,27 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	bundleutils "github.com/intel/edge-conductor/pkg/eputils/bundleutils"
 	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
 )
 
+// importedMirror returns the mirror of a file imported from an offline
+// bundle, so that the file is not downloaded again.
+func importedMirror(imported *pluginapi.Files, file *pluginapi.FilesItems0) string {
+	for _, f := range imported.Files {
+		if f.URL != file.URL {
+			continue
+		}
+		if len(file.Hash) > 0 && file.Hashtype == f.Hashtype && file.Hash != f.Hash {
+			continue
+		}
+		return f.Mirrorurl
+	}
+	return ""
+}
+
 func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	input_ep_params := input_ep_params(in)
 	input_files := input_files(in)
@@ -34,9 +51,23 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		}
 	}()
 
+	imported, err := bundleutils.ImportedFiles(input_ep_params.Runtimedir)
+	if err != nil {
+		return err
+	}
+
 	for _, file := range input_files.Files {
 		fileurl := file.URL
 
+		if mirror := importedMirror(imported, file); mirror != "" {
+			log.Infof("Use %s imported from bundle", fileurl)
+			file.Mirrorurl = mirror
+			output_files.Files = append(
+				output_files.Files,
+				file)
+			continue
+		}
+
 		fileName := path.Base(fileurl)
 
 		targetFile := filepath.Join(tmpFolder, fileName)
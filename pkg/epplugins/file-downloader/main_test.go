@@ -177,6 +177,27 @@ func TestPluginMain(t *testing.T) {
 			expectError:    false,
 			expectErrorMsg: "",
 		},
+		{
+			name: "Success: file imported from bundle",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"runtimedir":"testdata/bundle"}`),
+				"files":     []byte(`{"files":[{"url":"https://example.com/file.tar.gz","hash":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08","hashtype":"sha256","urlreplacement":{"new":"test"}}]}`),
+			},
+			expectedOutput: map[string][]byte{
+				"files": []byte(`{"files":[{"url":"https://example.com/file.tar.gz","hash":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08","hashtype":"sha256","urlreplacement":{"new":"test"},"mirrorurl":"oci://127.0.0.1:9000/library/test/file.tar.gz:0.0.0"}]}`),
+			},
+			expectError:    false,
+			expectErrorMsg: "",
+		},
+		{
+			name: "Error Case: invalid imported file list",
+			input: map[string][]byte{
+				"ep-params": []byte(`{"runtimedir":"testdata/badbundle"}`),
+				"files":     []byte(`{"files":[]}`),
+			},
+			expectError:    true,
+			expectErrorMsg: "json: cannot unmarshal string into Go value of type plugins.Files",
+		},
 		{
 			name: "Success: RemoveAll error",
 			input: map[string][]byte{
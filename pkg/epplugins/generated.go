@@ -7,6 +7,7 @@
 package epplugins
 
 import (
+	_ "github.com/intel/edge-conductor/pkg/epplugins/bundle-exporter"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/capi-cluster-deploy"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/capi-deinit"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/capi-host-provision"
@@ -55,6 +56,7 @@ var PluginList []string = []string{
 	"debug-dump",
 	"docker-image-downloader",
 	"file-downloader",
+	"bundle-exporter",
 	"file-exporter",
 	"service-parser",
 	"service-build",
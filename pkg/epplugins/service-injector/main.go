@@ -9,7 +9,6 @@
 package serviceinjector
 
 import (
-	"fmt"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -17,6 +16,7 @@ import (
 	papi "github.com/intel/edge-conductor/pkg/api/plugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	registryutils "github.com/intel/edge-conductor/pkg/eputils/registryutils"
 )
 
 func getFileFromList(filelist *papi.Files, url string) (*papi.FilesItems0, error) {
@@ -30,7 +30,6 @@ func getFileFromList(filelist *papi.Files, url string) (*papi.FilesItems0, error
 
 func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 	input_ep_params := input_ep_params(in)
-	input_kitcfg := input_ep_params.Kitconfig
 	input_downloadfiles := input_downloadfiles(in)
 	input_serviceconfig := input_serviceconfig(in)
 
@@ -40,10 +39,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		return eputils.GetError("errNoServerPort")
 	}
 
-	_, err := docker.GetAuthConf(input_kitcfg.Parameters.GlobalSettings.ProviderIP,
-		input_kitcfg.Parameters.GlobalSettings.RegistryPort,
-		input_kitcfg.Parameters.Customconfig.Registry.User,
-		input_kitcfg.Parameters.Customconfig.Registry.Password)
+	reg, err := registryutils.NewRegistry(input_ep_params)
 	if err != nil {
 		log.Warnf("Docker get auth failed")
 		return err
@@ -75,8 +71,7 @@ func PluginMain(in eputils.SchemaMapData, outp *eputils.SchemaMapData) error {
 		}
 		for i, wanted_image := range service.Images {
 			if strings.Index(wanted_image, "/") > 0 {
-				registryUrl := fmt.Sprintf("%s:%s", input_kitcfg.Parameters.GlobalSettings.ProviderIP, input_kitcfg.Parameters.GlobalSettings.RegistryPort)
-				newTag, err := docker.TagImageToLocal(wanted_image, registryUrl)
+				newTag, err := docker.TagImageToLocal(wanted_image, reg.Address())
 				if err != nil {
 					return err
 				}
@@ -0,0 +1,96 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+var sshBastion *pluginapi.Node
+
+// SetSSHBastion sets the bastion host which the SSH connections to the nodes
+// are tunneled through. A nil bastion makes the connections direct.
+func SetSSHBastion(bastion *pluginapi.Node) {
+	if bastion == nil || bastion.IP == "" {
+		sshBastion = nil
+		return
+	}
+	b := *bastion
+	if b.SSHPort == 0 {
+		b.SSHPort = 22
+	}
+	if b.SSHKey == "" && b.SSHKeyPath != "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			b.SSHKeyPath = strings.Replace(b.SSHKeyPath, "~", homeDir, 1)
+		}
+		if key, err := ioutil.ReadFile(b.SSHKeyPath); err == nil {
+			b.SSHKey = string(key)
+		} else {
+			log.Warningf("Failed to read the ssh key of the bastion host: %v", err)
+		}
+	}
+	sshBastion = &b
+}
+
+// dialBastion connects to the bastion host, or returns nil if the SSH server
+// at addr is to be connected directly.
+func dialBastion(addr string) (*ssh.Client, error) {
+	if sshBastion == nil {
+		return nil, nil
+	}
+	bastionAddr := fmt.Sprintf("%s:%d", sshBastion.IP, sshBastion.SSHPort)
+	if addr == bastionAddr {
+		return nil, nil
+	}
+	cfg, err := GenSSHConfig(sshBastion)
+	if err != nil {
+		return nil, err
+	}
+	client, err := ssh.Dial("tcp", bastionAddr, cfg)
+	if err != nil {
+		log.Errorf("Unable to connect to the bastion host %s: %v", bastionAddr, err)
+		return nil, err
+	}
+	return client, nil
+}
+
+// DialSSH connects to the SSH server at addr, through the bastion host if
+// one is set.
+func DialSSH(addr string, cfg *ssh.ClientConfig) (*ssh.Client, error) {
+	jump, err := dialBastion(addr)
+	if err != nil {
+		return nil, err
+	}
+	if jump == nil {
+		return ssh.Dial("tcp", addr, cfg)
+	}
+	conn, err := jump.Dial("tcp", addr)
+	if err != nil {
+		jump.Close()
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		conn.Close()
+		jump.Close()
+		return nil, err
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	go func() {
+		_ = client.Wait()
+		jump.Close()
+	}()
+	return client, nil
+}
@@ -0,0 +1,128 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sshd "github.com/gliderlabs/ssh"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+const bastionPort int64 = 2224
+
+func runBastionServer(t *testing.T, forwards *int32) *sshd.Server {
+	_, pwdpath, _, _ := runtime.Caller(0)
+	server := &sshd.Server{
+		Addr: fmt.Sprintf("%s:%d", sshHost, bastionPort),
+		LocalPortForwardingCallback: func(ctx sshd.Context, host string, port uint32) bool {
+			atomic.AddInt32(forwards, 1)
+			return true
+		},
+		ChannelHandlers: map[string]sshd.ChannelHandler{
+			"session":      sshd.DefaultSessionHandler,
+			"direct-tcpip": sshd.DirectTCPIPHandler,
+		},
+	}
+	require.NoError(t, server.SetOption(sshd.HostKeyFile(filepath.Join(filepath.Dir(pwdpath), "testdata", "test_rsa"))))
+	go func() {
+		_ = server.ListenAndServe()
+	}()
+	return server
+}
+
+func waitForServer(t *testing.T, addr string) {
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDialSSH(t *testing.T) {
+	var forwards int32
+	server := runBastionServer(t, &forwards)
+	defer server.Close()
+	defer SetSSHBastion(nil)
+
+	addr := fmt.Sprintf("%s:%d", sshHost, sshPort)
+	waitForServer(t, addr)
+	waitForServer(t, server.Addr)
+	cases := []struct {
+		name         string
+		bastion      *pluginapi.Node
+		addr         string
+		wantForwards int32
+		expectError  bool
+	}{
+		{
+			name: "direct",
+			addr: addr,
+		},
+		{
+			name: "through bastion",
+			bastion: &pluginapi.Node{
+				IP:                    sshHost,
+				SSHPort:               bastionPort,
+				User:                  "ec",
+				SSHPasswd:             "123456",
+				SSHHostKeyFingerprint: nodeWithKey.SSHHostKeyFingerprint,
+			},
+			addr:         addr,
+			wantForwards: 1,
+		},
+		{
+			name: "bastion itself",
+			bastion: &pluginapi.Node{
+				IP:                    sshHost,
+				SSHPort:               sshPort,
+				User:                  "ec",
+				SSHPasswd:             "123456",
+				SSHHostKeyFingerprint: nodeWithKey.SSHHostKeyFingerprint,
+			},
+			addr: addr,
+		},
+		{
+			name: "bastion unreachable",
+			bastion: &pluginapi.Node{
+				IP:        sshHost,
+				SSHPort:   2225,
+				User:      "ec",
+				SSHPasswd: "123456",
+			},
+			addr:        addr,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&forwards, 0)
+			SetSSHBastion(tc.bastion)
+			cfg, err := GenSSHConfig(&nodeWithKey)
+			require.NoError(t, err)
+			err = RunRemoteCMD(tc.addr, cfg, "hostname")
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantForwards, atomic.LoadInt32(&forwards))
+		})
+	}
+}
@@ -0,0 +1,289 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Package bundleutils packs the images and files needed by a cluster and
+// its services into a signed offline bundle, and imports such a bundle
+// into the day-0 registry and file repo.
+//
+// Layout of a bundle:
+//
+//   manifest.yml       images and files in the bundle, with SHA256 digests
+//   manifest.yml.sig   signature of manifest.yml
+//   images/<n>.tar     images saved with "docker save"
+//   files/<n>/<name>   files resolved by the parsers
+//
+package bundleutils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	epapi "github.com/intel/edge-conductor/pkg/api/ep"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ManifestFile  = "manifest.yml"
+	SignatureFile = "manifest.yml.sig"
+
+	imagesDir  = "images"
+	filesDir   = "files"
+	stagingDir = "bundle"
+)
+
+// StagingDir: directory where the content of a bundle is collected
+// before it is packed.
+func StagingDir(runtimedir string) string {
+	return filepath.Join(runtimedir, stagingDir)
+}
+
+// AddImage: save an image from the local docker into the bundle directory.
+func AddImage(dir string, manifest *epapi.Bundlemanifest, url string) error {
+	file := filepath.Join(imagesDir, strconv.Itoa(len(manifest.Images))+".tar")
+	if err := eputils.CreateFolderIfNotExist(filepath.Join(dir, imagesDir)); err != nil {
+		return err
+	}
+
+	log.Infof("Save image %s", url)
+	if err := docker.ImageSave([]string{url}, filepath.Join(dir, file)); err != nil {
+		return err
+	}
+	digest, err := eputils.GenFileSHA256(filepath.Join(dir, file))
+	if err != nil {
+		return err
+	}
+
+	manifest.Images = append(manifest.Images, &epapi.BundlemanifestImagesItems0{
+		URL:    url,
+		File:   file,
+		Sha256: digest,
+	})
+	return nil
+}
+
+// AddFile: copy a downloaded file into the bundle directory.
+//
+// Parameters:
+//   dir:      bundle directory.
+//   manifest: bundle manifest to update.
+//   url:      original URL of the file.
+//   subref:   sub reference of the file in the file repo.
+//   src:      downloaded file.
+//
+func AddFile(dir string, manifest *epapi.Bundlemanifest, url, subref, src string) error {
+	file := filepath.Join(filesDir, strconv.Itoa(len(manifest.Files)), filepath.Base(src))
+	if err := eputils.CreateFolderIfNotExist(filepath.Dir(filepath.Join(dir, file))); err != nil {
+		return err
+	}
+
+	if _, err := eputils.CopyFile(filepath.Join(dir, file), src); err != nil {
+		log.Errorln("Failed to copy", src, err)
+		return err
+	}
+	digest, err := eputils.GenFileSHA256(filepath.Join(dir, file))
+	if err != nil {
+		return err
+	}
+
+	manifest.Files = append(manifest.Files, &epapi.BundlemanifestFilesItems0{
+		URL:    url,
+		Subref: subref,
+		File:   file,
+		Sha256: digest,
+	})
+	return nil
+}
+
+// Pack: sign the manifest of the bundle directory and pack the directory
+// into a gzipped tarball.
+//
+// Parameters:
+//   dir:        bundle directory, with manifest.yml written.
+//   keyFile:    PEM file of the private key to sign the manifest.
+//   bundleFile: tarball to create.
+//
+func Pack(dir, keyFile, bundleFile string) error {
+	sig, err := Sign(filepath.Join(dir, ManifestFile), keyFile)
+	if err != nil {
+		return err
+	}
+	if err := eputils.WriteStringToFile(sig, filepath.Join(dir, SignatureFile)); err != nil {
+		return err
+	}
+	return packDir(dir, bundleFile)
+}
+
+func packDir(dir, bundleFile string) error {
+	f, err := os.OpenFile(bundleFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		log.Errorln("Failed to create", bundleFile, err)
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		log.Errorln("Failed to pack", dir, err)
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Unpack: extract a bundle, verify the signature of its manifest and the
+// digests of its content.
+//
+// Parameters:
+//   bundleFile: tarball of the bundle.
+//   keyFile:    PEM file of the public key or certificate of the signer.
+//   dir:        directory to extract the bundle to.
+// Output:
+//   manifest:   manifest of the bundle.
+//
+func Unpack(bundleFile, keyFile, dir string) (*epapi.Bundlemanifest, error) {
+	if err := untar(bundleFile, dir); err != nil {
+		return nil, err
+	}
+
+	sig, err := os.ReadFile(filepath.Join(dir, SignatureFile))
+	if err != nil {
+		log.Errorln("No signature found in bundle", bundleFile)
+		return nil, eputils.GetError("errBundleSignature")
+	}
+	if err := Verify(filepath.Join(dir, ManifestFile), string(sig), keyFile); err != nil {
+		return nil, err
+	}
+
+	manifest := &epapi.Bundlemanifest{}
+	if err := eputils.LoadSchemaStructFromYamlFile(manifest, filepath.Join(dir, ManifestFile)); err != nil {
+		return nil, err
+	}
+	for _, img := range manifest.Images {
+		if err := checkDigest(dir, img.File, img.Sha256); err != nil {
+			return nil, err
+		}
+	}
+	for _, file := range manifest.Files {
+		if err := checkDigest(dir, file.File, file.Sha256); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+func checkDigest(dir, file, digest string) error {
+	path, err := bundlePath(dir, file)
+	if err != nil {
+		return err
+	}
+	if err := eputils.CheckFileSHA256(path, digest); err != nil {
+		log.Errorln("Digest check failed for", file)
+		return eputils.GetError("errBundleDigest")
+	}
+	return nil
+}
+
+// bundlePath returns the path of a bundle entry in dir, rejecting names
+// that would escape it.
+func bundlePath(dir, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		log.Errorln("Invalid path in bundle:", name)
+		return "", eputils.GetError("errBundlePath")
+	}
+	return filepath.Join(dir, clean), nil
+}
+
+func untar(bundleFile, dir string) error {
+	f, err := os.Open(bundleFile)
+	if err != nil {
+		log.Errorln("Failed to open", bundleFile, err)
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		log.Errorln("Failed to read", bundleFile, err)
+		return err
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			log.Errorln("Failed to read", bundleFile, err)
+			return err
+		}
+
+		path, err := bundlePath(dir, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			// #nosec G110
+			_, err = io.Copy(file, reader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			log.Errorf("Unsupported entry type %d in bundle: %s", header.Typeflag, header.Name)
+			return eputils.GetError("errBundlePath")
+		}
+	}
+}
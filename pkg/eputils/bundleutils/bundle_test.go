@@ -0,0 +1,241 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package bundleutils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	epapi "github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	"github.com/stretchr/testify/require"
+	"github.com/undefinedlabs/go-mpatch"
+)
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	require.NoError(t, os.WriteFile(file, data, 0600))
+}
+
+// genKeys writes a private and a public PEM key of the given algorithm.
+func genKeys(t *testing.T, dir, algo string) (string, string) {
+	priv := filepath.Join(dir, algo+".key")
+	pub := filepath.Join(dir, algo+".pub")
+
+	var pubKey interface{}
+	switch algo {
+	case "rsa":
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		writePEM(t, priv, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+		pubKey = &key.PublicKey
+	case "ecdsa":
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		writePEM(t, priv, "EC PRIVATE KEY", der)
+		pubKey = &key.PublicKey
+	case "ed25519":
+		pubEd, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		writePEM(t, priv, "PRIVATE KEY", der)
+		pubKey = pubEd
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pubKey)
+	require.NoError(t, err)
+	writePEM(t, pub, "PUBLIC KEY", der)
+	return priv, pub
+}
+
+func TestSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "content")
+	require.NoError(t, os.WriteFile(file, []byte("content"), 0600))
+	other := filepath.Join(dir, "other")
+	require.NoError(t, os.WriteFile(other, []byte("other"), 0600))
+	invalidKey := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidKey, []byte("not a key"), 0600))
+
+	for _, algo := range []string{"rsa", "ecdsa", "ed25519"} {
+		t.Run(algo, func(t *testing.T) {
+			priv, pub := genKeys(t, dir, algo)
+
+			sig, err := Sign(file, priv)
+			require.NoError(t, err)
+			require.NoError(t, Verify(file, sig, pub))
+			require.Equal(t, eputils.GetError("errBundleSignature"), Verify(other, sig, pub))
+			require.Equal(t, eputils.GetError("errBundleSignature"), Verify(file, "!", pub))
+
+			_, err = Sign(file, pub)
+			require.Equal(t, eputils.GetError("errBundleKey"), err)
+			require.Equal(t, eputils.GetError("errBundleKey"), Verify(file, sig, priv))
+		})
+	}
+
+	_, err := Sign(file, invalidKey)
+	require.Equal(t, eputils.GetError("errBundleKey"), err)
+	_, err = Sign(file, filepath.Join(dir, "notexist"))
+	require.Error(t, err)
+}
+
+func TestPackUnpack(t *testing.T) {
+	keyDir := t.TempDir()
+	priv, pub := genKeys(t, keyDir, "ecdsa")
+	_, otherPub := genKeys(t, keyDir, "rsa")
+
+	newBundle := func(t *testing.T) (string, *epapi.Bundlemanifest) {
+		dir := filepath.Join(t.TempDir(), "bundle")
+		src := filepath.Join(t.TempDir(), "file.txt")
+		require.NoError(t, os.WriteFile(src, []byte("test"), 0600))
+
+		manifest := &epapi.Bundlemanifest{}
+		require.NoError(t, AddFile(dir, manifest, "https://example.com/file.txt", "test", src))
+		require.NoError(t, eputils.SaveSchemaStructToYamlFile(manifest, filepath.Join(dir, ManifestFile)))
+		return dir, manifest
+	}
+
+	cases := []struct {
+		name      string
+		modify    func(t *testing.T, dir string)
+		verifyKey string
+		wantErr   error
+	}{
+		{
+			name:      "round trip",
+			verifyKey: pub,
+		},
+		{
+			name:      "wrong key",
+			verifyKey: otherPub,
+			wantErr:   eputils.GetError("errBundleSignature"),
+		},
+		{
+			name: "tampered file",
+			modify: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "files", "0", "file.txt"), []byte("evil"), 0600))
+			},
+			verifyKey: pub,
+			wantErr:   eputils.GetError("errBundleDigest"),
+		},
+		{
+			name: "missing signature",
+			modify: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, SignatureFile)))
+			},
+			verifyKey: pub,
+			wantErr:   eputils.GetError("errBundleSignature"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, manifest := newBundle(t)
+			require.Equal(t, "files/0/file.txt", manifest.Files[0].File)
+			require.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", manifest.Files[0].Sha256)
+
+			bundleFile := filepath.Join(t.TempDir(), "bundle.tar.gz")
+			require.NoError(t, Pack(dir, priv, bundleFile))
+			if tc.modify != nil {
+				// Repack the modified content with the original signature.
+				tc.modify(t, dir)
+				require.NoError(t, packDir(dir, bundleFile))
+			}
+
+			out := t.TempDir()
+			got, err := Unpack(bundleFile, tc.verifyKey, out)
+			if tc.wantErr != nil {
+				require.Equal(t, tc.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, manifest, got)
+			content, err := os.ReadFile(filepath.Join(out, got.Files[0].File))
+			require.NoError(t, err)
+			require.Equal(t, "test", string(content))
+		})
+	}
+}
+
+func TestUntarInvalidPath(t *testing.T) {
+	for _, name := range []string{"../evil", "/evil", "a/../../evil"} {
+		t.Run(name, func(t *testing.T) {
+			bundleFile := filepath.Join(t.TempDir(), "bundle.tar.gz")
+			f, err := os.Create(bundleFile)
+			require.NoError(t, err)
+			gz := gzip.NewWriter(f)
+			tw := tar.NewWriter(gz)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0600, Size: 4}))
+			_, err = tw.Write([]byte("evil"))
+			require.NoError(t, err)
+			require.NoError(t, tw.Close())
+			require.NoError(t, gz.Close())
+			require.NoError(t, f.Close())
+
+			out := filepath.Join(t.TempDir(), "out")
+			require.Equal(t, eputils.GetError("errBundlePath"), untar(bundleFile, out))
+			require.NoFileExists(t, filepath.Join(filepath.Dir(out), "evil"))
+		})
+	}
+}
+
+func TestImportFiles(t *testing.T) {
+	runtimedir := t.TempDir()
+	dir := t.TempDir()
+	manifest := &epapi.Bundlemanifest{
+		Files: []*epapi.BundlemanifestFilesItems0{
+			{URL: "https://example.com/a.txt", Subref: "a", File: "files/0/a.txt", Sha256: "aaa"},
+			{URL: "https://example.com/b.txt", Subref: "b", File: "files/1/b.txt", Sha256: "bbb"},
+		},
+	}
+
+	var pushed []string
+	patch, err := mpatch.PatchMethod(repoutils.PushFileToRepo, func(file, subref, rev string) (string, error) {
+		pushed = append(pushed, file)
+		return "oci://repo/" + subref, nil
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, patch.Unpatch()) }()
+
+	files, err := ImportedFiles(runtimedir)
+	require.NoError(t, err)
+	require.Empty(t, files.Files)
+
+	// Importing twice keeps one record per URL.
+	require.NoError(t, Import(dir, manifest, &pluginapi.EpParams{Runtimedir: runtimedir}))
+	require.NoError(t, Import(dir, manifest, &pluginapi.EpParams{Runtimedir: runtimedir}))
+	require.Equal(t, []string{
+		filepath.Join(dir, "files/0/a.txt"), filepath.Join(dir, "files/1/b.txt"),
+		filepath.Join(dir, "files/0/a.txt"), filepath.Join(dir, "files/1/b.txt"),
+	}, pushed)
+
+	files, err = ImportedFiles(runtimedir)
+	require.NoError(t, err)
+	require.Equal(t, []*pluginapi.FilesItems0{
+		{URL: "https://example.com/a.txt", Hash: "aaa", Hashtype: "sha256", Mirrorurl: "oci://repo/a"},
+		{URL: "https://example.com/b.txt", Hash: "bbb", Hashtype: "sha256", Mirrorurl: "oci://repo/b"},
+	}, files.Files)
+
+	require.Equal(t, eputils.GetError("errKitCfgParameter"),
+		Import(dir, &epapi.Bundlemanifest{Images: []*epapi.BundlemanifestImagesItems0{{URL: "a:1.0"}}}, &pluginapi.EpParams{}))
+}
@@ -0,0 +1,126 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package bundleutils
+
+import (
+	"path/filepath"
+
+	epapi "github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	registryutils "github.com/intel/edge-conductor/pkg/eputils/registryutils"
+	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	importedFilesFile = "bundle-files.yml"
+)
+
+// ImportedFiles: list of files imported from bundles, with the URL of
+// their mirror in the file repo.
+func ImportedFiles(runtimedir string) (*pluginapi.Files, error) {
+	files := &pluginapi.Files{}
+	filename := filepath.Join(runtimedir, importedFilesFile)
+	if !eputils.FileExists(filename) {
+		return files, nil
+	}
+	if err := eputils.LoadSchemaStructFromYamlFile(files, filename); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Import: load the images of an unpacked bundle into the day-0 registry
+// and push its files to the day-0 file repo.
+//
+// Parameters:
+//   dir:      directory of the unpacked bundle.
+//   manifest: manifest of the bundle.
+//   epParams: ep-params of the day-0 host.
+//
+func Import(dir string, manifest *epapi.Bundlemanifest, epParams *pluginapi.EpParams) error {
+	if err := importImages(dir, manifest, epParams); err != nil {
+		return err
+	}
+	return importFiles(dir, manifest, epParams.Runtimedir)
+}
+
+func importImages(dir string, manifest *epapi.Bundlemanifest, epParams *pluginapi.EpParams) error {
+	if len(manifest.Images) == 0 {
+		return nil
+	}
+	reg, err := registryutils.NewRegistry(epParams)
+	if err != nil {
+		return err
+	}
+	auth := reg.AuthConf()
+
+	var images []string
+	for _, img := range manifest.Images {
+		log.Infof("Load image %s", img.URL)
+		if err := docker.ImageLoad(filepath.Join(dir, img.File)); err != nil {
+			return err
+		}
+		images = append(images, img.URL)
+	}
+
+	newImages, err := reg.MapImages(images)
+	if err != nil {
+		return err
+	}
+	for _, img := range newImages {
+		newTag, err := docker.TagImageToLocal(img, auth.ServerAddress)
+		if err != nil {
+			return err
+		}
+		log.Infof("Push %s to %s", img, newTag)
+		if err := docker.ImagePush(newTag, auth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importFiles(dir string, manifest *epapi.Bundlemanifest, runtimedir string) error {
+	if len(manifest.Files) == 0 {
+		return nil
+	}
+	imported, err := ImportedFiles(runtimedir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range manifest.Files {
+		log.Infof("Push file %s", file.URL)
+		ref, err := repoutils.PushFileToRepo(filepath.Join(dir, file.File), file.Subref, "")
+		if err != nil {
+			return err
+		}
+
+		record := &pluginapi.FilesItems0{
+			URL:       file.URL,
+			Hash:      file.Sha256,
+			Hashtype:  pluginapi.FilesItems0HashtypeSha256,
+			Mirrorurl: ref,
+		}
+		replaced := false
+		for i, f := range imported.Files {
+			if f.URL == file.URL {
+				imported.Files[i] = record
+				replaced = true
+			}
+		}
+		if !replaced {
+			imported.Files = append(imported.Files, record)
+		}
+	}
+
+	return eputils.SaveSchemaStructToYamlFile(imported, filepath.Join(runtimedir, importedFilesFile))
+}
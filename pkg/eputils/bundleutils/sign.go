@@ -0,0 +1,162 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package bundleutils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"strings"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	log "github.com/sirupsen/logrus"
+)
+
+func loadPEMBlock(keyFile string) (*pem.Block, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		log.Errorln("Failed to read key file", keyFile, err)
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		log.Errorln("No PEM data found in", keyFile)
+		return nil, eputils.GetError("errBundleKey")
+	}
+	return block, nil
+}
+
+func loadPrivateKey(keyFile string) (crypto.Signer, error) {
+	block, err := loadPEMBlock(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		log.Errorln("Failed to parse private key", keyFile, err)
+		return nil, eputils.GetError("errBundleKey")
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, eputils.GetError("errBundleKey")
+	}
+	return signer, nil
+}
+
+func loadPublicKey(keyFile string) (crypto.PublicKey, error) {
+	block, err := loadPEMBlock(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Errorln("Failed to parse certificate", keyFile, err)
+			return nil, eputils.GetError("errBundleKey")
+		}
+		return cert.PublicKey, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		log.Errorln("Failed to parse public key", keyFile, err)
+		return nil, eputils.GetError("errBundleKey")
+	}
+	return key, nil
+}
+
+// Sign: sign a file with a PEM encoded RSA, ECDSA or Ed25519 private key.
+//
+// Parameters:
+//   file:    file to sign.
+//   keyFile: PEM file of the private key.
+// Output:
+//   signature: base64 encoded signature.
+//
+func Sign(file, keyFile string) (string, error) {
+	signer, err := loadPrivateKey(keyFile)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	var sig []byte
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		sig, err = signer.Sign(rand.Reader, content, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(content)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		log.Errorln("Failed to sign", file, err)
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// Verify: verify the signature of a file with a PEM encoded public key
+// or certificate.
+//
+// Parameters:
+//   file:      file that was signed.
+//   signature: base64 encoded signature.
+//   keyFile:   PEM file of the public key or certificate.
+//
+func Verify(file, signature, keyFile string) error {
+	pub, err := loadPublicKey(keyFile)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return eputils.GetError("errBundleSignature")
+	}
+
+	digest := sha256.Sum256(content)
+	valid := false
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], sig)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, content, sig)
+	default:
+		return eputils.GetError("errBundleKey")
+	}
+	if !valid {
+		log.Errorln("Signature check failed for", file)
+		return eputils.GetError("errBundleSignature")
+	}
+	return nil
+}
@@ -13,7 +13,9 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"github.com/intel/edge-conductor/pkg/eputils"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/signal"
@@ -23,6 +25,7 @@ import (
 
 	api "github.com/intel/edge-conductor/pkg/api/plugins"
 
+	"github.com/docker/distribution/reference"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -45,6 +48,7 @@ const (
 
 var keyList = []string{"http_proxy", "https_proxy", "no_proxy", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
 var gcli *client.Client
+var progressOutput io.Writer = os.Stdout
 
 // getDefaultContext: Get the default context.
 //
@@ -170,8 +174,8 @@ func ImagePull(imageRef string, authConf *types.AuthConfig) error {
 	}
 	defer logreader.Close()
 
-	terminalFD, isTerminal := term.GetFdInfo(os.Stdout)
-	if err = jsonmessage.DisplayJSONMessagesStream(logreader, os.Stdout, terminalFD, isTerminal, nil); err != nil {
+	terminalFD, isTerminal := term.GetFdInfo(progressOutput)
+	if err = jsonmessage.DisplayJSONMessagesStream(logreader, progressOutput, terminalFD, isTerminal, nil); err != nil {
 		log.Error(err)
 		return err
 	}
@@ -209,14 +213,116 @@ func ImagePush(imageRef string, authConf *types.AuthConfig) error {
 	}
 	defer logreader.Close()
 
-	terminalFD, isTerminal := term.GetFdInfo(os.Stdout)
-	if err = jsonmessage.DisplayJSONMessagesStream(logreader, os.Stdout, terminalFD, isTerminal, nil); err != nil {
+	terminalFD, isTerminal := term.GetFdInfo(progressOutput)
+	if err = jsonmessage.DisplayJSONMessagesStream(logreader, progressOutput, terminalFD, isTerminal, nil); err != nil {
 		log.Error(err)
 		return err
 	}
 	return nil
 }
 
+// SetProgressOutput: Set the writer of the progress of ImagePull and
+// ImagePush, os.Stdout by default. Set it before pulling or pushing images
+// concurrently, as the progress of concurrent operations is interleaved.
+//
+// Parameters:
+//   out:   Writer of the progress.
+// Output:
+//   prev:  Previous writer of the progress.
+//
+func SetProgressOutput(out io.Writer) io.Writer {
+	prev := progressOutput
+	progressOutput = out
+	return prev
+}
+
+// ImageUpToDate: Check if a registry has the same image as the local one.
+//   The registry is up to date if the digest of the image manifest on the
+//   registry is the digest the local image was last pushed or pulled with.
+//
+// Parameters:
+//   imageRef:   Tag of the image on the registry
+//   authConf:   The authentication configuration
+//
+func ImageUpToDate(imageRef string, authConf *types.AuthConfig) (bool, error) {
+	ctx := getDefaultContext()
+	cli, err := getDockerClient()
+	if err != nil {
+		return false, err
+	}
+
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return false, err
+	}
+	imginspect, _, err := cli.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		log.Errorln("Failed to inspect Docker image:", imageRef)
+		return false, err
+	}
+	localDigest := repoDigest(imginspect.RepoDigests, named.Name())
+	if localDigest == "" {
+		return false, nil
+	}
+
+	var authStr string
+	if authConf != nil {
+		encodedJSON, err := json.Marshal(authConf)
+		if err != nil {
+			return false, err
+		}
+		authStr = base64.URLEncoding.EncodeToString(encodedJSON)
+	}
+	distinspect, err := cli.DistributionInspect(ctx, imageRef, authStr)
+	if err != nil {
+		log.Debugf("Image %s is not found on registry: %v", imageRef, err)
+		return false, nil
+	}
+	return distinspect.Descriptor.Digest.String() == localDigest, nil
+}
+
+// ImageDigest: Get the digest the local image was last pushed or pulled
+//   with on its repository, or an empty string if it was never pushed or
+//   pulled there.
+//
+// Parameters:
+//   imageRef:   Tag of the local image
+//
+func ImageDigest(imageRef string) (string, error) {
+	ctx := getDefaultContext()
+	cli, err := getDockerClient()
+	if err != nil {
+		return "", err
+	}
+
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", err
+	}
+	imginspect, _, err := cli.ImageInspectWithRaw(ctx, imageRef)
+	if err != nil {
+		log.Errorln("Failed to inspect Docker image:", imageRef)
+		return "", err
+	}
+	return repoDigest(imginspect.RepoDigests, named.Name()), nil
+}
+
+// repoDigest returns the digest of the repo digests of a local image on the
+// repository name.
+func repoDigest(repoDigests []string, name string) string {
+	localDigest := ""
+	for _, repoDigest := range repoDigests {
+		ref, err := reference.ParseNormalizedNamed(repoDigest)
+		if err != nil {
+			continue
+		}
+		if canonical, ok := ref.(reference.Canonical); ok && ref.Name() == name {
+			localDigest = canonical.Digest().String()
+		}
+	}
+	return localDigest
+}
+
 // ImageBuild: build image
 //
 // Parameters:
@@ -329,6 +435,41 @@ func ImageLoad(tarball string) error {
 	return nil
 }
 
+// ImageSave: save images
+//   "docker save"
+//
+// Parameters:
+//   images:  images to save
+//   tarball: tarball to write the images to
+//
+func ImageSave(images []string, tarball string) error {
+	ctx := getDefaultContext()
+	cli, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+
+	saveResponse, err := cli.ImageSave(ctx, images)
+	if err != nil {
+		log.Errorf("Failed to save image %s", err)
+		return err
+	}
+	defer saveResponse.Close()
+
+	dockerSaveContext, err := os.Create(tarball)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(dockerSaveContext, saveResponse); err != nil {
+		log.Error(err)
+		dockerSaveContext.Close()
+		return err
+	}
+
+	return dockerSaveContext.Close()
+}
+
 // CreateContainer: Create a Container
 //   "docker create"
 //
@@ -697,6 +838,141 @@ func RemoveContainer(containerName string) error {
 	return nil
 }
 
+// ExecExitError is returned by ExecContainer when the command exits with a
+// non-zero status.
+type ExecExitError struct {
+	ExitCode int
+}
+
+func (e *ExecExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.ExitCode)
+}
+
+// ExecContainer: Run a command in a running Container
+//   "docker exec -i <containerName> <cmd>"
+//
+// Parameters:
+//   ctx:            Context to cancel the command.
+//   containerName:  Name of the container.
+//   cmd:            A list of string of the command and its arguments.
+//   stdin:          Standard input of the command, or nil.
+//   stdout:         Standard output of the command, or nil.
+//   stderr:         Standard error of the command, or nil.
+//   tty:            If allocate a tty for the command.
+// Output:
+//   An *ExecExitError if the command exits with a non-zero status.
+//
+func ExecContainer(ctx context.Context, containerName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	cli, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+
+	exec, err := cli.ContainerExecCreate(ctx, containerName, types.ExecConfig{
+		Tty:          tty,
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		log.Errorln("Failed to create exec in container", containerName, err)
+		return err
+	}
+	resp, err := cli.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{Tty: tty})
+	if err != nil {
+		log.Errorln("Failed to attach exec in container", containerName, err)
+		return err
+	}
+	defer resp.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Close()
+		case <-done:
+		}
+	}()
+
+	if stdin != nil {
+		go func() {
+			if _, err := io.Copy(resp.Conn, stdin); err != nil {
+				log.Debugf("Failed to copy stdin to container %s: %v", containerName, err)
+			}
+			if err := resp.CloseWrite(); err != nil {
+				log.Debugf("Failed to close stdin of container %s: %v", containerName, err)
+			}
+		}()
+	}
+	if stdout == nil {
+		stdout = ioutil.Discard
+	}
+	if stderr == nil {
+		stderr = ioutil.Discard
+	}
+	if tty {
+		_, err = io.Copy(stdout, resp.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, resp.Reader)
+	}
+	if err != nil {
+		return err
+	}
+
+	inspect, err := cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		log.Errorln("Failed to inspect exec in container", containerName, err)
+		return err
+	}
+	if inspect.ExitCode != 0 {
+		return &ExecExitError{ExitCode: inspect.ExitCode}
+	}
+	return nil
+}
+
+// CreateNetwork: Create a bridge network
+//   "docker network create <networkName>"
+//
+// Parameters:
+//   networkName:  Name of the network, which must not exist yet.
+//
+func CreateNetwork(networkName string) error {
+	ctx := getDefaultContext()
+	cli, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+
+	if _, err := cli.NetworkCreate(ctx, networkName, types.NetworkCreate{CheckDuplicate: true}); err != nil {
+		log.Errorln("Failed to create network", networkName, err)
+		return err
+	}
+	log.Infoln("Successfully created network", networkName)
+	return nil
+}
+
+// RemoveNetwork: Remove a network
+//   "docker network rm <networkName>"
+//
+// Parameters:
+//   networkName:  Name of the network.
+//
+func RemoveNetwork(networkName string) error {
+	ctx := getDefaultContext()
+	cli, err := getDockerClient()
+	if err != nil {
+		return err
+	}
+
+	if err := cli.NetworkRemove(ctx, networkName); err != nil {
+		log.Errorln("Failed to remove network", networkName, err)
+		return err
+	}
+	log.Infoln("Successfully removed network", networkName)
+	return nil
+}
+
 // GetImageNewTag: Get a new tag of a image with the new registry URL.
 //
 // Parameters:
@@ -9,6 +9,9 @@
 package docker
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
@@ -19,6 +22,7 @@ import (
 	"io"
 	"io/fs"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -28,9 +32,13 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/golang/mock/gomock"
 	"github.com/moby/moby/pkg/jsonmessage"
+	digest "github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	mpatch "github.com/undefinedlabs/go-mpatch"
 )
 
@@ -793,6 +801,239 @@ func TestImageLoad(t *testing.T) {
 	t.Log("Done")
 }
 
+func TestImageSave(t *testing.T) {
+	saveFunc := func(body io.ReadCloser, saveErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+			cli := &client.Client{}
+			patch, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "ImageSave", mockDockerClientInterface.ImageSave)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			mockDockerClientInterface.EXPECT().
+				ImageSave(gomock.Any(), gomock.Any(), []string{"test:1.0"}).Return(body, saveErr)
+			return []*mpatch.Patch{patch}
+		}
+	}
+
+	tmpDir := t.TempDir()
+	cases := []struct {
+		tarball        string
+		wantErr        error
+		wantContent    string
+		funcBeforeTest func(*testing.T, *gomock.Controller) []*mpatch.Patch
+	}{
+		{
+			tarball:        filepath.Join(tmpDir, "image.tar"),
+			wantContent:    "TestImageSave Body!",
+			funcBeforeTest: saveFunc(io.NopCloser(strings.NewReader("TestImageSave Body!")), nil),
+		},
+		{
+			tarball:        filepath.Join(tmpDir, "image.tar"),
+			wantErr:        testError,
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+		{
+			tarball:        filepath.Join(tmpDir, "image.tar"),
+			wantErr:        testError,
+			funcBeforeTest: saveFunc(nil, testError),
+		},
+		{
+			tarball:        filepath.Join(tmpDir, "notexist", "image.tar"),
+			wantErr:        errFileDir,
+			funcBeforeTest: saveFunc(io.NopCloser(strings.NewReader("")), nil),
+		},
+	}
+	for n, testCase := range cases {
+		t.Logf("TestImageSave case %d start", n)
+		func() {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			pList := testCase.funcBeforeTest(t, ctrl)
+			defer unpatchAll(t, pList)
+
+			err := ImageSave([]string{"test:1.0"}, testCase.tarball)
+
+			if testCase.wantErr == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				content, _ := os.ReadFile(testCase.tarball)
+				if string(content) != testCase.wantContent {
+					t.Errorf("Unexpected content: %s", content)
+				}
+			} else if !errors.Is(err, testCase.wantErr) &&
+				(err == nil || !strings.Contains(err.Error(), testCase.wantErr.Error())) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+		t.Logf("TestImageSave case %d end", n)
+	}
+
+	t.Log("Done")
+}
+
+func TestImageUpToDate(t *testing.T) {
+	const (
+		imageRef     = "10.10.10.10:9000/library/test:1.0"
+		pushedDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		otherDigest  = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	upToDateFunc := func(repoDigests []string, inspectErr error, remoteDigest string, distErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+			cli := &client.Client{}
+			patchInspect, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "ImageInspectWithRaw", mockDockerClientInterface.ImageInspectWithRaw)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			patchDist, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "DistributionInspect", mockDockerClientInterface.DistributionInspect)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			mockDockerClientInterface.EXPECT().
+				ImageInspectWithRaw(gomock.Any(), gomock.Any(), imageRef).
+				Return(types.ImageInspect{RepoDigests: repoDigests}, nil, inspectErr)
+			if inspectErr == nil && remoteDigest != "" || distErr != nil {
+				mockDockerClientInterface.EXPECT().
+					DistributionInspect(gomock.Any(), gomock.Any(), imageRef, gomock.Any()).
+					Return(registry.DistributionInspect{Descriptor: ocispec.Descriptor{Digest: digest.Digest(remoteDigest)}}, distErr)
+			}
+			return []*mpatch.Patch{patchInspect, patchDist}
+		}
+	}
+
+	cases := []struct {
+		name           string
+		want           bool
+		wantErr        error
+		funcBeforeTest func(*testing.T, *gomock.Controller) []*mpatch.Patch
+	}{
+		{
+			name:           "same digest",
+			want:           true,
+			funcBeforeTest: upToDateFunc([]string{"test@" + otherDigest, "10.10.10.10:9000/library/test@" + pushedDigest}, nil, pushedDigest, nil),
+		},
+		{
+			name:           "different digest",
+			funcBeforeTest: upToDateFunc([]string{"10.10.10.10:9000/library/test@" + pushedDigest}, nil, otherDigest, nil),
+		},
+		{
+			name:           "never pushed",
+			funcBeforeTest: upToDateFunc([]string{"test@" + pushedDigest}, nil, "", nil),
+		},
+		{
+			name:           "not on registry",
+			funcBeforeTest: upToDateFunc([]string{"10.10.10.10:9000/library/test@" + pushedDigest}, nil, "", testError),
+		},
+		{
+			name:           "inspect error",
+			wantErr:        testError,
+			funcBeforeTest: upToDateFunc(nil, testError, "", nil),
+		},
+		{
+			name:           "docker client error",
+			wantErr:        testError,
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			pList := testCase.funcBeforeTest(t, ctrl)
+			defer unpatchAll(t, pList)
+
+			got, err := ImageUpToDate(imageRef, &types.AuthConfig{})
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if got != testCase.want {
+				t.Errorf("Unexpected result: %v", got)
+			}
+		})
+	}
+}
+
+func TestImageDigest(t *testing.T) {
+	const (
+		imageRef     = "10.10.10.10:9000/library/test:1.0"
+		pulledDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		otherDigest  = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	digestFunc := func(repoDigests []string, inspectErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+			cli := &client.Client{}
+			patchInspect, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "ImageInspectWithRaw", mockDockerClientInterface.ImageInspectWithRaw)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			mockDockerClientInterface.EXPECT().
+				ImageInspectWithRaw(gomock.Any(), gomock.Any(), imageRef).
+				Return(types.ImageInspect{RepoDigests: repoDigests}, nil, inspectErr)
+			return []*mpatch.Patch{patchInspect}
+		}
+	}
+
+	cases := []struct {
+		name           string
+		want           string
+		wantErr        error
+		funcBeforeTest func(*testing.T, *gomock.Controller) []*mpatch.Patch
+	}{
+		{
+			name:           "pulled",
+			want:           pulledDigest,
+			funcBeforeTest: digestFunc([]string{"test@" + otherDigest, "10.10.10.10:9000/library/test@" + pulledDigest}, nil),
+		},
+		{
+			name:           "never pulled",
+			funcBeforeTest: digestFunc([]string{"test@" + otherDigest}, nil),
+		},
+		{
+			name:           "inspect error",
+			wantErr:        testError,
+			funcBeforeTest: digestFunc(nil, testError),
+		},
+		{
+			name:           "docker client error",
+			wantErr:        testError,
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			pList := testCase.funcBeforeTest(t, ctrl)
+			defer unpatchAll(t, pList)
+
+			got, err := ImageDigest(imageRef)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if got != testCase.want {
+				t.Errorf("Unexpected digest: %v", got)
+			}
+		})
+	}
+}
+
+func TestSetProgressOutput(t *testing.T) {
+	var out bytes.Buffer
+	prev := SetProgressOutput(&out)
+	if prev != os.Stdout {
+		t.Errorf("Unexpected default progress output: %v", prev)
+	}
+	if SetProgressOutput(prev) != &out {
+		t.Errorf("Unexpected progress output")
+	}
+}
+
 func TestCreateContainer(t *testing.T) {
 
 	normalFunc := func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
@@ -2160,3 +2401,215 @@ func TestGetHostImages(t *testing.T) {
 
 	t.Log("Done")
 }
+
+func TestExecContainer(t *testing.T) {
+	execFunc := func(exitCode int, createErr, attachErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			cli := &client.Client{}
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+
+			patchExecCreate, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "ContainerExecCreate", mockDockerClientInterface.ContainerExecCreate)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			patchExecAttach, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "ContainerExecAttach", mockDockerClientInterface.ContainerExecAttach)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			patchExecInspect, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "ContainerExecInspect", mockDockerClientInterface.ContainerExecInspect)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+
+			mockDockerClientInterface.EXPECT().
+				ContainerExecCreate(gomock.Any(), gomock.Any(), "test", types.ExecConfig{
+					AttachStdout: true,
+					AttachStderr: true,
+					Cmd:          []string{"sh", "-c", "echo hello"},
+				}).
+				Return(types.IDResponse{ID: "exec1"}, createErr)
+			if createErr != nil {
+				return []*mpatch.Patch{patchExecCreate, patchExecAttach, patchExecInspect}
+			}
+
+			var output bytes.Buffer
+			_, _ = stdcopy.NewStdWriter(&output, stdcopy.Stdout).Write([]byte("hello\n"))
+			_, _ = stdcopy.NewStdWriter(&output, stdcopy.Stderr).Write([]byte("warning\n"))
+			conn, _ := net.Pipe()
+			mockDockerClientInterface.EXPECT().
+				ContainerExecAttach(gomock.Any(), gomock.Any(), "exec1", gomock.Any()).
+				Return(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&output)}, attachErr)
+			if attachErr != nil {
+				return []*mpatch.Patch{patchExecCreate, patchExecAttach, patchExecInspect}
+			}
+
+			mockDockerClientInterface.EXPECT().
+				ContainerExecInspect(gomock.Any(), gomock.Any(), "exec1").
+				Return(types.ContainerExecInspect{ExecID: "exec1", ExitCode: exitCode}, nil)
+			return []*mpatch.Patch{patchExecCreate, patchExecAttach, patchExecInspect}
+		}
+	}
+
+	cases := []struct {
+		name           string
+		wantErr        error
+		wantExitCode   int
+		wantStdout     string
+		wantStderr     string
+		funcBeforeTest func(*testing.T, *gomock.Controller) []*mpatch.Patch
+	}{
+		{
+			name:           "test_normal",
+			wantStdout:     "hello\n",
+			wantStderr:     "warning\n",
+			funcBeforeTest: execFunc(0, nil, nil),
+		},
+		{
+			name:           "test_exit_code",
+			wantExitCode:   3,
+			wantStdout:     "hello\n",
+			wantStderr:     "warning\n",
+			funcBeforeTest: execFunc(3, nil, nil),
+		},
+		{
+			name:           "test_get_docker_client_err",
+			wantErr:        testError,
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+		{
+			name:           "test_exec_create_err",
+			wantErr:        testError,
+			funcBeforeTest: execFunc(0, testError, nil),
+		},
+		{
+			name:           "test_exec_attach_err",
+			wantErr:        testError,
+			funcBeforeTest: execFunc(0, nil, testError),
+		},
+	}
+	for _, testCase := range cases {
+		t.Logf("TestExecContainer case %s start", testCase.name)
+		func() {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			if testCase.funcBeforeTest != nil {
+				pList := testCase.funcBeforeTest(t, ctrl)
+				defer unpatchAll(t, pList)
+			}
+
+			var stdout, stderr bytes.Buffer
+			err := ExecContainer(context.Background(), "test", []string{"sh", "-c", "echo hello"}, nil, &stdout, &stderr, false)
+
+			var exitErr *ExecExitError
+			if testCase.wantExitCode != 0 {
+				if !errors.As(err, &exitErr) || exitErr.ExitCode != testCase.wantExitCode {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			} else if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if stdout.String() != testCase.wantStdout || stderr.String() != testCase.wantStderr {
+				t.Errorf("Unexpected output: %q, %q", stdout.String(), stderr.String())
+			}
+		}()
+		t.Logf("TestExecContainer case %s end", testCase.name)
+	}
+
+	t.Log("Done")
+}
+
+func TestNetwork(t *testing.T) {
+	createFunc := func(createErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			cli := &client.Client{}
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+			patchNetworkCreate, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "NetworkCreate", mockDockerClientInterface.NetworkCreate)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			mockDockerClientInterface.EXPECT().
+				NetworkCreate(gomock.Any(), gomock.Any(), "test", types.NetworkCreate{CheckDuplicate: true}).
+				Return(types.NetworkCreateResponse{ID: "network1"}, createErr)
+			return []*mpatch.Patch{patchNetworkCreate}
+		}
+	}
+	removeFunc := func(removeErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			cli := &client.Client{}
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+			patchNetworkRemove, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "NetworkRemove", mockDockerClientInterface.NetworkRemove)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			mockDockerClientInterface.EXPECT().
+				NetworkRemove(gomock.Any(), gomock.Any(), "test").
+				Return(removeErr)
+			return []*mpatch.Patch{patchNetworkRemove}
+		}
+	}
+
+	cases := []struct {
+		name           string
+		remove         bool
+		wantErr        error
+		funcBeforeTest func(*testing.T, *gomock.Controller) []*mpatch.Patch
+	}{
+		{
+			name:           "test_create_normal",
+			funcBeforeTest: createFunc(nil),
+		},
+		{
+			name:           "test_create_get_docker_client_err",
+			wantErr:        testError,
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+		{
+			name:           "test_network_create_err",
+			wantErr:        testError,
+			funcBeforeTest: createFunc(testError),
+		},
+		{
+			name:           "test_remove_normal",
+			remove:         true,
+			funcBeforeTest: removeFunc(nil),
+		},
+		{
+			name:           "test_remove_get_docker_client_err",
+			remove:         true,
+			wantErr:        testError,
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+		{
+			name:           "test_network_remove_err",
+			remove:         true,
+			wantErr:        testError,
+			funcBeforeTest: removeFunc(testError),
+		},
+	}
+	for _, testCase := range cases {
+		t.Logf("TestNetwork case %s start", testCase.name)
+		func() {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			if testCase.funcBeforeTest != nil {
+				pList := testCase.funcBeforeTest(t, ctrl)
+				defer unpatchAll(t, pList)
+			}
+
+			var err error
+			if testCase.remove {
+				err = RemoveNetwork("test")
+			} else {
+				err = CreateNetwork("test")
+			}
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+		t.Logf("TestNetwork case %s end", testCase.name)
+	}
+
+	t.Log("Done")
+}
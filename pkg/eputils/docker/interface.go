@@ -17,6 +17,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 )
 
@@ -126,6 +127,35 @@ type DockerClientWrapperContainer interface {
 	//   containerName: Name of the container to stop and remove.
 	//
 	RemoveContainer(containerName string) error
+	// ExecContainer: Run a command in a running Container
+	//   "docker exec -i <containerName> <cmd>"
+	//
+	// Parameters:
+	//   ctx:            Context to cancel the command.
+	//   containerName:  Name of the container.
+	//   cmd:            A list of string of the command and its arguments.
+	//   stdin:          Standard input of the command, or nil.
+	//   stdout:         Standard output of the command, or nil.
+	//   stderr:         Standard error of the command, or nil.
+	//   tty:            If allocate a tty for the command.
+	// Output:
+	//   An *ExecExitError if the command exits with a non-zero status.
+	//
+	ExecContainer(ctx context.Context, containerName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error
+	// CreateNetwork: Create a bridge network
+	//   "docker network create <networkName>"
+	//
+	// Parameters:
+	//   networkName:  Name of the network, which must not exist yet.
+	//
+	CreateNetwork(networkName string) error
+	// RemoveNetwork: Remove a network
+	//   "docker network rm <networkName>"
+	//
+	// Parameters:
+	//   networkName:  Name of the network.
+	//
+	RemoveNetwork(networkName string) error
 	// GetContainerByName: Get a Container by its name
 	//
 	// Parameters:
@@ -159,6 +189,36 @@ type DockerClientWrapperImage interface {
 	//   authConf:   The authentication configuration
 	//
 	ImagePush(imageRef string, authConf *types.AuthConfig) error
+	// SetProgressOutput: Set the writer of the progress of ImagePull and ImagePush
+	//
+	// Parameters:
+	//   out:   Writer of the progress
+	// Output:
+	//   prev:  Previous writer of the progress
+	//
+	SetProgressOutput(out io.Writer) io.Writer
+	// ImageUpToDate: Check if a registry has the same image as the local one
+	//
+	// Parameters:
+	//   imageRef:   Tag of the image on the registry
+	//   authConf:   The authentication configuration
+	//
+	ImageUpToDate(imageRef string, authConf *types.AuthConfig) (bool, error)
+	// DaemonAvailable: Check if the local docker daemon is reachable.
+	//
+	DaemonAvailable() bool
+	// ImageCopy: Copy an image from a registry to another one without the
+	//   local docker daemon.
+	//
+	// Parameters:
+	//   srcRef:     Tag of the image on the source registry
+	//   dstRef:     Tag of the image on the destination registry
+	//   authConf:   The authentication configuration of the destination registry
+	//   cacert:     CA certificate of the destination registry, or ""
+	// Output:
+	//   copied:     false if the destination already has the same image
+	//
+	ImageCopy(srcRef, dstRef string, authConf *types.AuthConfig, cacert string) (bool, error)
 	// ImageBuild: build image
 	//
 	// Parameters:
@@ -173,6 +233,13 @@ type DockerClientWrapperImage interface {
 	//   tarball: tarball to load docker image
 	//
 	ImageLoad(tarball string) error
+	// ImageSave: save images
+	//
+	// Parameters:
+	//   images:  images to save
+	//   tarball: tarball to write the images to
+	//
+	ImageSave(images []string, tarball string) error
 	// GetImageNewTag: Get a new tag of a image with the new registry URL.
 	//
 	// Parameters:
@@ -228,6 +295,12 @@ type DockerClientInterface interface {
 	// ImageLoad: github.com/docker/docker/client.ImageLoad
 	//
 	ImageLoad(cli *client.Client, ctx context.Context, input io.Reader, quiet bool) (types.ImageLoadResponse, error)
+	// ImageSave: github.com/docker/docker/client.ImageSave
+	//
+	ImageSave(cli *client.Client, ctx context.Context, imageIDs []string) (io.ReadCloser, error)
+	// DistributionInspect: github.com/docker/docker/client.DistributionInspect
+	//
+	DistributionInspect(cli *client.Client, ctx context.Context, image, encodedRegistryAuth string) (registry.DistributionInspect, error)
 	// ImageLoad: github.com/docker/docker/client.ImageList
 	//
 	ImageList(cli *client.Client, ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error)
@@ -237,6 +310,9 @@ type DockerClientInterface interface {
 	// NetworkCreate: github.com/docker/docker/client.NetworkCreate
 	//
 	NetworkCreate(cli *client.Client, ctx context.Context, name string, options types.NetworkCreate) (types.NetworkCreateResponse, error)
+	// NetworkRemove: github.com/docker/docker/client.NetworkRemove
+	//
+	NetworkRemove(cli *client.Client, ctx context.Context, networkID string) error
 	// ContainerCreate: github.com/docker/docker/client.ContainerCreate
 	//
 	ContainerCreate(cli *client.Client, ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string) (container.ContainerCreateCreatedBody, error)
@@ -249,6 +325,15 @@ type DockerClientInterface interface {
 	// ContainerStop: github.com/docker/docker/client.ContainerStop
 	//
 	ContainerStop(cli *client.Client, ctx context.Context, containerID string, timeout *time.Duration) error
+	// ContainerExecCreate: github.com/docker/docker/client.ContainerExecCreate
+	//
+	ContainerExecCreate(cli *client.Client, ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error)
+	// ContainerExecAttach: github.com/docker/docker/client.ContainerExecAttach
+	//
+	ContainerExecAttach(cli *client.Client, ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error)
+	// ContainerExecInspect: github.com/docker/docker/client.ContainerExecInspect
+	//
+	ContainerExecInspect(cli *client.Client, ctx context.Context, execID string) (types.ContainerExecInspect, error)
 	// ContainerLogs: github.com/docker/docker/client.ContainerLogs
 	//
 	ContainerLogs(cli *client.Client, ctx context.Context, container string, options types.ContainerLogsOptions) (io.ReadCloser, error)
@@ -261,6 +346,9 @@ type DockerClientInterface interface {
 	// ImageTag: github.com/docker/docker/client.ImageTag
 	//
 	ImageTag(cli *client.Client, ctx context.Context, source, target string) error
+	// Ping: github.com/docker/docker/client.Ping
+	//
+	Ping(cli *client.Client, ctx context.Context) (types.Ping, error)
 	// NewClientWithOpts: github.com/docker/docker/client.NewClientWithOpts
 	//
 	NewClientWithOpts(ops ...client.Opt) (*client.Client, error)
@@ -22,6 +22,7 @@ import (
 	container "github.com/docker/docker/api/types/container"
 	mount "github.com/docker/docker/api/types/mount"
 	network "github.com/docker/docker/api/types/network"
+	registry "github.com/docker/docker/api/types/registry"
 	client "github.com/docker/docker/client"
 	gomock "github.com/golang/mock/gomock"
 	plugins "github.com/intel/edge-conductor/pkg/api/plugins"
@@ -65,6 +66,34 @@ func (mr *MockDockerClientWrapperContainerMockRecorder) CreateContainer(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContainer", reflect.TypeOf((*MockDockerClientWrapperContainer)(nil).CreateContainer), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11, arg12, arg13, arg14, arg15, arg16, arg17, arg18, arg19)
 }
 
+// CreateNetwork mocks base method.
+func (m *MockDockerClientWrapperContainer) CreateNetwork(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNetwork", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNetwork indicates an expected call of CreateNetwork.
+func (mr *MockDockerClientWrapperContainerMockRecorder) CreateNetwork(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNetwork", reflect.TypeOf((*MockDockerClientWrapperContainer)(nil).CreateNetwork), arg0)
+}
+
+// ExecContainer mocks base method.
+func (m *MockDockerClientWrapperContainer) ExecContainer(arg0 context.Context, arg1 string, arg2 []string, arg3 io.Reader, arg4, arg5 io.Writer, arg6 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecContainer", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecContainer indicates an expected call of ExecContainer.
+func (mr *MockDockerClientWrapperContainerMockRecorder) ExecContainer(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecContainer", reflect.TypeOf((*MockDockerClientWrapperContainer)(nil).ExecContainer), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
 // GetContainerByName mocks base method.
 func (m *MockDockerClientWrapperContainer) GetContainerByName(arg0 string) (*types.Container, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +123,20 @@ func (mr *MockDockerClientWrapperContainerMockRecorder) RemoveContainer(arg0 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveContainer", reflect.TypeOf((*MockDockerClientWrapperContainer)(nil).RemoveContainer), arg0)
 }
 
+// RemoveNetwork mocks base method.
+func (m *MockDockerClientWrapperContainer) RemoveNetwork(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveNetwork", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveNetwork indicates an expected call of RemoveNetwork.
+func (mr *MockDockerClientWrapperContainerMockRecorder) RemoveNetwork(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveNetwork", reflect.TypeOf((*MockDockerClientWrapperContainer)(nil).RemoveNetwork), arg0)
+}
+
 // RunContainer mocks base method.
 func (m *MockDockerClientWrapperContainer) RunContainer(arg0, arg1, arg2, arg3 string, arg4 []string, arg5 string, arg6, arg7, arg8, arg9 bool, arg10, arg11, arg12 []string, arg13 []mount.Mount, arg14 map[string]struct{}, arg15, arg16, arg17, arg18 []string, arg19 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -160,6 +203,20 @@ func (m *MockDockerClientWrapperImage) EXPECT() *MockDockerClientWrapperImageMoc
 	return m.recorder
 }
 
+// DaemonAvailable mocks base method.
+func (m *MockDockerClientWrapperImage) DaemonAvailable() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DaemonAvailable")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// DaemonAvailable indicates an expected call of DaemonAvailable.
+func (mr *MockDockerClientWrapperImageMockRecorder) DaemonAvailable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DaemonAvailable", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).DaemonAvailable))
+}
+
 // GetAuthConf mocks base method.
 func (m *MockDockerClientWrapperImage) GetAuthConf(arg0, arg1, arg2, arg3 string) (*types.AuthConfig, error) {
 	m.ctrl.T.Helper()
@@ -218,6 +275,21 @@ func (mr *MockDockerClientWrapperImageMockRecorder) ImageBuild(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageBuild", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImageBuild), arg0, arg1, arg2)
 }
 
+// ImageCopy mocks base method.
+func (m *MockDockerClientWrapperImage) ImageCopy(arg0, arg1 string, arg2 *types.AuthConfig, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageCopy", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageCopy indicates an expected call of ImageCopy.
+func (mr *MockDockerClientWrapperImageMockRecorder) ImageCopy(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageCopy", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImageCopy), arg0, arg1, arg2, arg3)
+}
+
 // ImageLoad mocks base method.
 func (m *MockDockerClientWrapperImage) ImageLoad(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -274,6 +346,49 @@ func (mr *MockDockerClientWrapperImageMockRecorder) ImagePushToRegistry(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePushToRegistry", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImagePushToRegistry), arg0, arg1, arg2)
 }
 
+// ImageSave mocks base method.
+func (m *MockDockerClientWrapperImage) ImageSave(arg0 []string, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageSave", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImageSave indicates an expected call of ImageSave.
+func (mr *MockDockerClientWrapperImageMockRecorder) ImageSave(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageSave", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImageSave), arg0, arg1)
+}
+
+// ImageUpToDate mocks base method.
+func (m *MockDockerClientWrapperImage) ImageUpToDate(arg0 string, arg1 *types.AuthConfig) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageUpToDate", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageUpToDate indicates an expected call of ImageUpToDate.
+func (mr *MockDockerClientWrapperImageMockRecorder) ImageUpToDate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageUpToDate", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).ImageUpToDate), arg0, arg1)
+}
+
+// SetProgressOutput mocks base method.
+func (m *MockDockerClientWrapperImage) SetProgressOutput(arg0 io.Writer) io.Writer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProgressOutput", arg0)
+	ret0, _ := ret[0].(io.Writer)
+	return ret0
+}
+
+// SetProgressOutput indicates an expected call of SetProgressOutput.
+func (mr *MockDockerClientWrapperImageMockRecorder) SetProgressOutput(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProgressOutput", reflect.TypeOf((*MockDockerClientWrapperImage)(nil).SetProgressOutput), arg0)
+}
+
 // TagImage mocks base method.
 func (m *MockDockerClientWrapperImage) TagImage(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -341,6 +456,51 @@ func (mr *MockDockerClientInterfaceMockRecorder) ContainerCreate(arg0, arg1, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerCreate", reflect.TypeOf((*MockDockerClientInterface)(nil).ContainerCreate), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// ContainerExecAttach mocks base method.
+func (m *MockDockerClientInterface) ContainerExecAttach(arg0 *client.Client, arg1 context.Context, arg2 string, arg3 types.ExecStartCheck) (types.HijackedResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerExecAttach", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(types.HijackedResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerExecAttach indicates an expected call of ContainerExecAttach.
+func (mr *MockDockerClientInterfaceMockRecorder) ContainerExecAttach(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerExecAttach", reflect.TypeOf((*MockDockerClientInterface)(nil).ContainerExecAttach), arg0, arg1, arg2, arg3)
+}
+
+// ContainerExecCreate mocks base method.
+func (m *MockDockerClientInterface) ContainerExecCreate(arg0 *client.Client, arg1 context.Context, arg2 string, arg3 types.ExecConfig) (types.IDResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerExecCreate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(types.IDResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerExecCreate indicates an expected call of ContainerExecCreate.
+func (mr *MockDockerClientInterfaceMockRecorder) ContainerExecCreate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerExecCreate", reflect.TypeOf((*MockDockerClientInterface)(nil).ContainerExecCreate), arg0, arg1, arg2, arg3)
+}
+
+// ContainerExecInspect mocks base method.
+func (m *MockDockerClientInterface) ContainerExecInspect(arg0 *client.Client, arg1 context.Context, arg2 string) (types.ContainerExecInspect, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContainerExecInspect", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.ContainerExecInspect)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContainerExecInspect indicates an expected call of ContainerExecInspect.
+func (mr *MockDockerClientInterfaceMockRecorder) ContainerExecInspect(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerExecInspect", reflect.TypeOf((*MockDockerClientInterface)(nil).ContainerExecInspect), arg0, arg1, arg2)
+}
+
 // ContainerInspect mocks base method.
 func (m *MockDockerClientInterface) ContainerInspect(arg0 *client.Client, arg1 context.Context, arg2 string) (types.ContainerJSON, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +588,21 @@ func (mr *MockDockerClientInterfaceMockRecorder) ContainerStop(arg0, arg1, arg2,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContainerStop", reflect.TypeOf((*MockDockerClientInterface)(nil).ContainerStop), arg0, arg1, arg2, arg3)
 }
 
+// DistributionInspect mocks base method.
+func (m *MockDockerClientInterface) DistributionInspect(arg0 *client.Client, arg1 context.Context, arg2, arg3 string) (registry.DistributionInspect, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistributionInspect", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(registry.DistributionInspect)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DistributionInspect indicates an expected call of DistributionInspect.
+func (mr *MockDockerClientInterfaceMockRecorder) DistributionInspect(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributionInspect", reflect.TypeOf((*MockDockerClientInterface)(nil).DistributionInspect), arg0, arg1, arg2, arg3)
+}
+
 // ImageBuild mocks base method.
 func (m *MockDockerClientInterface) ImageBuild(arg0 *client.Client, arg1 context.Context, arg2 io.Reader, arg3 types.ImageBuildOptions) (types.ImageBuildResponse, error) {
 	m.ctrl.T.Helper()
@@ -519,6 +694,21 @@ func (mr *MockDockerClientInterfaceMockRecorder) ImagePush(arg0, arg1, arg2, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImagePush", reflect.TypeOf((*MockDockerClientInterface)(nil).ImagePush), arg0, arg1, arg2, arg3)
 }
 
+// ImageSave mocks base method.
+func (m *MockDockerClientInterface) ImageSave(arg0 *client.Client, arg1 context.Context, arg2 []string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImageSave", arg0, arg1, arg2)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImageSave indicates an expected call of ImageSave.
+func (mr *MockDockerClientInterfaceMockRecorder) ImageSave(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImageSave", reflect.TypeOf((*MockDockerClientInterface)(nil).ImageSave), arg0, arg1, arg2)
+}
+
 // ImageTag mocks base method.
 func (m *MockDockerClientInterface) ImageTag(arg0 *client.Client, arg1 context.Context, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
@@ -563,6 +753,20 @@ func (mr *MockDockerClientInterfaceMockRecorder) NetworkInspect(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkInspect", reflect.TypeOf((*MockDockerClientInterface)(nil).NetworkInspect), arg0, arg1, arg2, arg3)
 }
 
+// NetworkRemove mocks base method.
+func (m *MockDockerClientInterface) NetworkRemove(arg0 *client.Client, arg1 context.Context, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NetworkRemove", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NetworkRemove indicates an expected call of NetworkRemove.
+func (mr *MockDockerClientInterfaceMockRecorder) NetworkRemove(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NetworkRemove", reflect.TypeOf((*MockDockerClientInterface)(nil).NetworkRemove), arg0, arg1, arg2)
+}
+
 // NewClientWithOpts mocks base method.
 func (m *MockDockerClientInterface) NewClientWithOpts(arg0 ...client.Opt) (*client.Client, error) {
 	m.ctrl.T.Helper()
@@ -581,3 +785,18 @@ func (mr *MockDockerClientInterfaceMockRecorder) NewClientWithOpts(arg0 ...inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewClientWithOpts", reflect.TypeOf((*MockDockerClientInterface)(nil).NewClientWithOpts), arg0...)
 }
+
+// Ping mocks base method.
+func (m *MockDockerClientInterface) Ping(arg0 *client.Client, arg1 context.Context) (types.Ping, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0, arg1)
+	ret0, _ := ret[0].(types.Ping)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockDockerClientInterfaceMockRecorder) Ping(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockDockerClientInterface)(nil).Ping), arg0, arg1)
+}
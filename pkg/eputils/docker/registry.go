@@ -0,0 +1,220 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	ctrddocker "github.com/containerd/containerd/remotes/docker"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+// DaemonAvailable: Check if the local docker daemon is reachable.
+//
+func DaemonAvailable() bool {
+	ctx := getDefaultContext()
+	cli, err := getDockerClient()
+	if err != nil {
+		return false
+	}
+	if _, err := cli.Ping(ctx); err != nil {
+		log.Debugf("Docker daemon is not available: %v", err)
+		return false
+	}
+	return true
+}
+
+// ImageCopy: Copy an image from a registry to another one without the
+//   local docker daemon. Manifests and blobs are streamed from the source
+//   registry to the destination registry as they are, so the digests are
+//   kept, and all the platforms of a manifest list are copied.
+//
+// Parameters:
+//   srcRef:     Tag of the image on the source registry
+//   dstRef:     Tag of the image on the destination registry
+//   authConf:   The authentication configuration of the destination registry
+//   cacert:     CA certificate of the destination registry, or ""
+// Output:
+//   copied:     false if the destination already has the same image
+//
+func ImageCopy(srcRef, dstRef string, authConf *types.AuthConfig, cacert string) (bool, error) {
+	ctx := getDefaultContext()
+
+	src, err := normalizeRef(srcRef)
+	if err != nil {
+		return false, err
+	}
+	dst, err := normalizeRef(dstRef)
+	if err != nil {
+		return false, err
+	}
+	srcAuth, err := LoadDockerCliCredentials(srcRef)
+	if err != nil {
+		return false, err
+	}
+	tlsCfg, err := GetCertPoolCfgWithCustomCa(cacert)
+	if err != nil {
+		return false, err
+	}
+	httpClient := http.DefaultClient
+	if tlsCfg != nil {
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsCfg,
+			},
+		}
+	}
+
+	// A new resolver for each copy, as the resolver remembers the pushed
+	// blobs without their repository.
+	resolver := ctrddocker.NewResolver(ctrddocker.ResolverOptions{
+		Client: httpClient,
+		Credentials: func(host string) (string, string, error) {
+			if authConf != nil && host == authConf.ServerAddress {
+				return authConf.Username, authConf.Password, nil
+			}
+			if srcAuth != nil {
+				return srcAuth.Username, srcAuth.Password, nil
+			}
+			return "", "", nil
+		},
+	})
+
+	name, desc, err := resolver.Resolve(ctx, src)
+	if err != nil {
+		log.Errorf("Failed to resolve image %s: %v", src, err)
+		return false, err
+	}
+	if _, dstDesc, err := resolver.Resolve(ctx, dst); err == nil && dstDesc.Digest == desc.Digest {
+		log.Debugf("Image %s is up to date", dst)
+		return false, nil
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	// With the digest in the reference, only the root manifest is pushed
+	// to the tag, the other manifests are pushed by digest.
+	pusher, err := resolver.Pusher(ctx, dst+"@"+desc.Digest.String())
+	if err != nil {
+		return false, err
+	}
+	if err := copyContent(ctx, fetcher, pusher, desc); err != nil {
+		log.Errorf("Failed to copy image %s to %s: %v", src, dst, err)
+		return false, err
+	}
+	return true, nil
+}
+
+func normalizeRef(imageRef string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", err
+	}
+	return reference.TagNameOnly(named).String(), nil
+}
+
+// copyContent copies desc and the content it references, children first,
+// so that the registry has all the blobs of a manifest when it is pushed.
+func copyContent(ctx context.Context, fetcher remotes.Fetcher, pusher remotes.Pusher, desc ocispec.Descriptor) error {
+	switch desc.MediaType {
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest,
+		images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		data, err := fetchManifest(ctx, fetcher, desc)
+		if err != nil {
+			return err
+		}
+		children, err := manifestChildren(desc.MediaType, data)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if err := copyContent(ctx, fetcher, pusher, child); err != nil {
+				return err
+			}
+		}
+		return pushContent(ctx, pusher, desc, bytes.NewReader(data))
+	case images.MediaTypeDockerSchema1Manifest:
+		log.Errorf("Image manifest %s is not supported", desc.MediaType)
+		return eputils.GetError("errImageMediaType")
+	default:
+		if len(desc.URLs) > 0 {
+			// Foreign layers are not distributed by registries.
+			return nil
+		}
+		rc, err := fetcher.Fetch(ctx, desc)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return pushContent(ctx, pusher, desc, rc)
+	}
+}
+
+func fetchManifest(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(rc, desc.Size+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != desc.Size || digest.FromBytes(data) != desc.Digest {
+		log.Errorf("Digest check failed for %s", desc.Digest)
+		return nil, eputils.GetError("errImageDigest")
+	}
+	return data, nil
+}
+
+func manifestChildren(mediaType string, data []byte) ([]ocispec.Descriptor, error) {
+	switch mediaType {
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(data, &index); err != nil {
+			return nil, err
+		}
+		return index.Manifests, nil
+	default:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, err
+		}
+		return append([]ocispec.Descriptor{manifest.Config}, manifest.Layers...), nil
+	}
+}
+
+func pushContent(ctx context.Context, pusher remotes.Pusher, desc ocispec.Descriptor, r io.Reader) error {
+	w, err := pusher.Push(ctx, desc)
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	defer w.Close()
+	// The registry checks the digest of the content when it is committed.
+	return content.Copy(ctx, w, r, desc.Size, desc.Digest)
+}
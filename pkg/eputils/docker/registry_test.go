@@ -0,0 +1,338 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/containerd/containerd/images"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	gomock "github.com/golang/mock/gomock"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	clientmock "github.com/intel/edge-conductor/pkg/eputils/docker/mock"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+type fakeManifest struct {
+	mediaType string
+	digest    digest.Digest
+	data      []byte
+}
+
+// fakeRegistry is a minimal registry serving the distribution API used
+// by ImageCopy.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	manifests map[string]fakeManifest
+	blobs     map[string][]byte
+	uploads   int
+	user      string
+	password  string
+}
+
+func newFakeRegistry(t *testing.T, user, password string) (*fakeRegistry, string) {
+	r := &fakeRegistry{
+		manifests: map[string]fakeManifest{},
+		blobs:     map[string][]byte{},
+		user:      user,
+		password:  password,
+	}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, strings.TrimPrefix(server.URL, "http://")
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.user != "" {
+		if user, password, ok := req.BasicAuth(); !ok || user != r.user || password != r.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if i := strings.LastIndex(path, "/manifests/"); i >= 0 {
+		r.serveManifest(w, req, path[:i], path[i+len("/manifests/"):])
+	} else if i := strings.LastIndex(path, "/blobs/uploads/"); i >= 0 {
+		r.serveUpload(w, req, path[:i], path[i+len("/blobs/uploads/"):])
+	} else if i := strings.LastIndex(path, "/blobs/"); i >= 0 {
+		r.serveBlob(w, req, path[:i], path[i+len("/blobs/"):])
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (r *fakeRegistry) serveManifest(w http.ResponseWriter, req *http.Request, repo, ref string) {
+	if req.Method == http.MethodPut {
+		data, _ := ioutil.ReadAll(req.Body)
+		m := fakeManifest{mediaType: req.Header.Get("Content-Type"), digest: digest.FromBytes(data), data: data}
+		r.manifests[repo+"@"+ref] = m
+		r.manifests[repo+"@"+m.digest.String()] = m
+		w.Header().Set("Docker-Content-Digest", m.digest.String())
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	m, ok := r.manifests[repo+"@"+ref]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", m.mediaType)
+	w.Header().Set("Docker-Content-Digest", m.digest.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(m.data)))
+	if req.Method == http.MethodGet {
+		_, _ = w.Write(m.data)
+	}
+}
+
+func (r *fakeRegistry) serveBlob(w http.ResponseWriter, req *http.Request, repo, dgst string) {
+	data, ok := r.blobs[repo+"@"+dgst]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", dgst)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func (r *fakeRegistry) serveUpload(w http.ResponseWriter, req *http.Request, repo, id string) {
+	if req.Method == http.MethodPost {
+		r.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%s/blobs/uploads/%d", repo, r.uploads))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	data, _ := ioutil.ReadAll(req.Body)
+	dgst := req.URL.Query().Get("digest")
+	if digest.FromBytes(data).String() != dgst {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.blobs[repo+"@"+dgst] = data
+	w.Header().Set("Docker-Content-Digest", dgst)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (r *fakeRegistry) addBlob(repo, mediaType string, data []byte) ocispec.Descriptor {
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	r.blobs[repo+"@"+desc.Digest.String()] = data
+	return desc
+}
+
+func (r *fakeRegistry) addManifest(t *testing.T, repo, tag, mediaType string, v interface{}) ocispec.Descriptor {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	desc := ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+	m := fakeManifest{mediaType: mediaType, digest: desc.Digest, data: data}
+	r.manifests[repo+"@"+desc.Digest.String()] = m
+	if tag != "" {
+		r.manifests[repo+"@"+tag] = m
+	}
+	return desc
+}
+
+// addMultiArchImage adds a manifest list of two platforms sharing a layer.
+func (r *fakeRegistry) addMultiArchImage(t *testing.T, repo, tag string) ocispec.Descriptor {
+	shared := r.addBlob(repo, images.MediaTypeDockerSchema2LayerGzip, []byte("shared layer"))
+	var manifests []ocispec.Descriptor
+	for _, arch := range []string{"amd64", "arm64"} {
+		config := r.addBlob(repo, images.MediaTypeDockerSchema2Config, []byte(`{"architecture":"`+arch+`"}`))
+		layer := r.addBlob(repo, images.MediaTypeDockerSchema2LayerGzip, []byte(arch+" layer"))
+		desc := r.addManifest(t, repo, "", images.MediaTypeDockerSchema2Manifest, ocispec.Manifest{
+			Versioned: specs.Versioned{SchemaVersion: 2},
+			Config:    config,
+			Layers:    []ocispec.Descriptor{shared, layer},
+		})
+		desc.Platform = &ocispec.Platform{OS: "linux", Architecture: arch}
+		manifests = append(manifests, desc)
+	}
+	return r.addManifest(t, repo, tag, images.MediaTypeDockerSchema2ManifestList, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: manifests,
+	})
+}
+
+func TestImageCopy(t *testing.T) {
+	src, srcHost := newFakeRegistry(t, "", "")
+	dst, dstHost := newFakeRegistry(t, "admin", "secret")
+	auth := &types.AuthConfig{ServerAddress: dstHost, Username: "admin", Password: "secret"}
+
+	index := src.addMultiArchImage(t, "library/app", "1.0")
+	src.addManifest(t, "library/old", "1.0", images.MediaTypeDockerSchema1Manifest, map[string]int{"schemaVersion": 1})
+	bad := src.addManifest(t, "library/bad", "", images.MediaTypeDockerSchema2Manifest, ocispec.Manifest{})
+	src.manifests["library/bad@"+bad.Digest.String()] = fakeManifest{mediaType: bad.MediaType, digest: bad.Digest, data: []byte("{}")}
+	src.addManifest(t, "library/bad", "1.0", images.MediaTypeDockerSchema2ManifestList, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{bad},
+	})
+
+	cases := []struct {
+		name       string
+		src, dst   string
+		auth       *types.AuthConfig
+		wantCopied bool
+		wantErr    error
+	}{
+		{
+			name:       "multi-arch image",
+			src:        srcHost + "/library/app:1.0",
+			dst:        dstHost + "/library/app:1.0",
+			auth:       auth,
+			wantCopied: true,
+		},
+		{
+			name: "up to date",
+			src:  srcHost + "/library/app:1.0",
+			dst:  dstHost + "/library/app:1.0",
+			auth: auth,
+		},
+		{
+			name:       "another repository",
+			src:        srcHost + "/library/app:1.0",
+			dst:        dstHost + "/mirror/app:1.0",
+			auth:       auth,
+			wantCopied: true,
+		},
+		{
+			name:    "unauthorized",
+			src:     srcHost + "/library/app:1.0",
+			dst:     dstHost + "/library/app:2.0",
+			auth:    &types.AuthConfig{ServerAddress: dstHost, Username: "admin", Password: "wrong"},
+			wantErr: fmt.Errorf("any"),
+		},
+		{
+			name:    "schema1 manifest",
+			src:     srcHost + "/library/old:1.0",
+			dst:     dstHost + "/library/old:1.0",
+			auth:    auth,
+			wantErr: eputils.GetError("errImageMediaType"),
+		},
+		{
+			name:    "digest mismatch",
+			src:     srcHost + "/library/bad:1.0",
+			dst:     dstHost + "/library/bad:1.0",
+			auth:    auth,
+			wantErr: eputils.GetError("errImageDigest"),
+		},
+		{
+			name:    "not found",
+			src:     srcHost + "/library/none:1.0",
+			dst:     dstHost + "/library/none:1.0",
+			auth:    auth,
+			wantErr: fmt.Errorf("any"),
+		},
+		{
+			name:    "invalid reference",
+			src:     "Invalid:Ref",
+			dst:     dstHost + "/library/none:1.0",
+			auth:    auth,
+			wantErr: fmt.Errorf("any"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			copied, err := ImageCopy(tc.src, tc.dst, tc.auth, "")
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				if _, ok := tc.wantErr.(*eputils.EC_errors); ok {
+					require.Equal(t, tc.wantErr, err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCopied, copied)
+		})
+	}
+
+	// The manifests and blobs are copied as they are.
+	repos := map[string]string{"library/app": "1.0", "mirror/app": "1.0"}
+	for repo, tag := range repos {
+		require.Equal(t, src.manifests["library/app@1.0"], dst.manifests[repo+"@"+tag])
+		require.Equal(t, index.Digest, dst.manifests[repo+"@"+tag].digest)
+		for key, data := range src.blobs {
+			if strings.HasPrefix(key, "library/app@") {
+				require.Equal(t, data, dst.blobs[repo+strings.TrimPrefix(key, "library/app")])
+			}
+		}
+		for key, m := range src.manifests {
+			if strings.HasPrefix(key, "library/app@sha256:") {
+				require.Equal(t, m, dst.manifests[repo+strings.TrimPrefix(key, "library/app")])
+			}
+		}
+	}
+	require.NotContains(t, dst.manifests, "library/app@2.0")
+}
+
+func TestDaemonAvailable(t *testing.T) {
+	pingFunc := func(pingErr error) func(*testing.T, *gomock.Controller) []*mpatch.Patch {
+		return func(t *testing.T, ctrl *gomock.Controller) []*mpatch.Patch {
+			mockDockerClientInterface := clientmock.NewMockDockerClientInterface(ctrl)
+			cli := &client.Client{}
+			patchPing, err := mpatch.PatchInstanceMethodByName(reflect.TypeOf(cli), "Ping", mockDockerClientInterface.Ping)
+			if err != nil {
+				t.Errorf("mpatch error")
+			}
+			mockDockerClientInterface.EXPECT().Ping(gomock.Any(), gomock.Any()).Return(types.Ping{}, pingErr)
+			return []*mpatch.Patch{patchPing}
+		}
+	}
+
+	cases := []struct {
+		name           string
+		want           bool
+		funcBeforeTest func(*testing.T, *gomock.Controller) []*mpatch.Patch
+	}{
+		{
+			name:           "daemon running",
+			want:           true,
+			funcBeforeTest: pingFunc(nil),
+		},
+		{
+			name:           "daemon not running",
+			funcBeforeTest: pingFunc(testError),
+		},
+		{
+			name:           "docker client error",
+			funcBeforeTest: getDockerClientErrFunc,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			pList := testCase.funcBeforeTest(t, ctrl)
+			defer unpatchAll(t, pList)
+
+			if got := DaemonAvailable(); got != testCase.want {
+				t.Errorf("Unexpected result: %v", got)
+			}
+		})
+	}
+}
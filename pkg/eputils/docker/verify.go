@@ -0,0 +1,550 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package docker
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	ctrddocker "github.com/containerd/containerd/remotes/docker"
+	"github.com/docker/distribution/reference"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	notationArtifactType      = "application/vnd.cncf.notary.signature"
+	notationJWSMediaType      = "application/jose+json"
+
+	// Signatures and SBOM manifests are small, larger content is not
+	// fetched.
+	maxArtifactSize = 4 << 20
+)
+
+// verifyKey is a public key or a certificate configured to verify images.
+type verifyKey struct {
+	file string
+	pub  crypto.PublicKey
+	cert *x509.Certificate
+}
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// referrer is a manifest of the OCI referrers index, the image-spec
+// version in use does not have the artifact type.
+type referrer struct {
+	ocispec.Descriptor
+	ArtifactType string `json:"artifactType,omitempty"`
+}
+
+type referrersIndex struct {
+	Manifests []referrer `json:"manifests"`
+}
+
+// notationEnvelope is the JWS JSON envelope of a notation signature.
+type notationEnvelope struct {
+	Payload   string `json:"payload"`
+	Protected string `json:"protected"`
+	Header    struct {
+		CertChain [][]byte `json:"x5c"`
+	} `json:"header"`
+	Signature string `json:"signature"`
+}
+
+type notationProtected struct {
+	Alg         string `json:"alg"`
+	SigningTime string `json:"io.cncf.notary.signingTime"`
+}
+
+type notationPayload struct {
+	TargetArtifact ocispec.Descriptor `json:"targetArtifact"`
+}
+
+// ImageVerify: Verify an image on its registry with the image verification
+//   config of a kit. The registry of the image must be one of the allowed
+//   registries, the signature of the image digest is checked with the
+//   configured keys, and an SBOM must be attached to the image if it is
+//   required.
+//
+// Parameters:
+//   imageRef:   Tag of the image
+//   conf:       Image verification config of the kit
+// Output:
+//   verification: The verification result, it is also returned on failure
+//
+func ImageVerify(imageRef string, conf *pluginapi.CustomconfigImageVerification) (*pluginapi.ImagesItems0Verification, error) {
+	result := &pluginapi.ImagesItems0Verification{
+		Result: pluginapi.ImagesItems0VerificationResultFailed,
+	}
+	if err := imageVerify(imageRef, conf, result); err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	result.Result = pluginapi.ImagesItems0VerificationResultVerified
+	return result, nil
+}
+
+// ImagePinned: Get the reference of an image by the digest it was verified
+//   with, e.g. "docker.io/library/busybox@sha256:...", so that the verified
+//   content is fetched instead of the content the tag points to later.
+//
+// Parameters:
+//   imageRef:   Tag of the image
+//   dgst:       Digest of the verification result
+// Output:
+//   pinnedRef:  Reference of the image by digest
+//
+func ImagePinned(imageRef, dgst string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", err
+	}
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		log.Errorf("Invalid digest %q of image %s: %v", dgst, imageRef, err)
+		return "", eputils.GetError("errImageNotVerified")
+	}
+	pinned, err := reference.WithDigest(reference.TrimNamed(named), d)
+	if err != nil {
+		return "", err
+	}
+	return pinned.String(), nil
+}
+
+func imageVerify(imageRef string, conf *pluginapi.CustomconfigImageVerification, result *pluginapi.ImagesItems0Verification) error {
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return err
+	}
+	if !RegistryAllowed(named.Name(), conf.AllowedRegistries) {
+		log.Errorf("Registry of image %s is not allowed", imageRef)
+		return eputils.GetError("errImageRegistry")
+	}
+
+	var keys []verifyKey
+	if conf.Signature != "" && conf.Signature != pluginapi.CustomconfigImageVerificationSignatureNone {
+		if keys, err = loadVerifyKeys(conf.Keys); err != nil {
+			return err
+		}
+	}
+
+	auth, err := LoadDockerCliCredentials(imageRef)
+	if err != nil {
+		return err
+	}
+	resolver := ctrddocker.NewResolver(ctrddocker.ResolverOptions{
+		Credentials: func(host string) (string, string, error) {
+			if auth != nil {
+				return auth.Username, auth.Password, nil
+			}
+			return "", "", nil
+		},
+	})
+	ctx := getDefaultContext()
+	name, desc, err := resolver.Resolve(ctx, reference.TagNameOnly(named).String())
+	if err != nil {
+		log.Errorf("Failed to resolve image %s: %v", imageRef, err)
+		return err
+	}
+	result.Digest = desc.Digest.String()
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return err
+	}
+	v := &artifactVerifier{
+		ctx:      ctx,
+		resolver: resolver,
+		fetcher:  fetcher,
+		repo:     named.Name(),
+		digest:   desc.Digest,
+	}
+
+	switch conf.Signature {
+	case "", pluginapi.CustomconfigImageVerificationSignatureNone:
+	case pluginapi.CustomconfigImageVerificationSignatureCosign:
+		result.Key, err = v.verifyCosign(keys)
+	case pluginapi.CustomconfigImageVerificationSignatureNotation:
+		result.Key, err = v.verifyNotation(keys)
+	default:
+		log.Errorf("Unknown signature type %q", conf.Signature)
+		err = eputils.GetError("errImageSignature")
+	}
+	if err != nil {
+		return err
+	}
+	result.Signature = conf.Signature
+
+	if conf.RequireSbom {
+		if result.Sbom, err = v.findSBOM(); err != nil {
+			return err
+		}
+	}
+	log.Infof("Image %s@%s is verified", imageRef, desc.Digest)
+	return nil
+}
+
+// RegistryAllowed: Check if an image is in one of the allowed registries.
+//   An entry allows a registry, such as "docker.io", or the repositories
+//   under a path, such as "quay.io/calico". All images are allowed if
+//   there is no entry.
+//
+// Parameters:
+//   imageName:  Normalized name of the image, such as "docker.io/library/busybox"
+//   allowed:    Allowed registries
+//
+func RegistryAllowed(imageName string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, entry := range allowed {
+		entry = strings.TrimSuffix(entry, "/")
+		if imageName == entry || strings.HasPrefix(imageName, entry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func loadVerifyKeys(keyFiles []string) ([]verifyKey, error) {
+	var keys []verifyKey
+	for _, keyFile := range keyFiles {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			log.Errorln("Failed to read key file", keyFile, err)
+			return nil, err
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			log.Errorln("No PEM data found in", keyFile)
+			return nil, eputils.GetError("errImageVerifyKey")
+		}
+		key := verifyKey{file: keyFile}
+		if block.Type == "CERTIFICATE" {
+			key.cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key.pub = key.cert.PublicKey
+			}
+		} else {
+			key.pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+		if err != nil {
+			log.Errorln("Failed to parse key file", keyFile, err)
+			return nil, eputils.GetError("errImageVerifyKey")
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		log.Errorln("No key is configured to verify image signatures")
+		return nil, eputils.GetError("errImageVerifyKey")
+	}
+	return keys, nil
+}
+
+// artifactVerifier finds the artifacts attached to an image digest, with
+// the tags of cosign and the OCI referrers tag schema.
+type artifactVerifier struct {
+	ctx      context.Context
+	resolver remotes.Resolver
+	fetcher  remotes.Fetcher
+	repo     string
+	digest   digest.Digest
+}
+
+// tag returns the tag of an artifact attached to the image, such as
+// "sha256-<hex>.sig" for the cosign signature.
+func (v *artifactVerifier) tag(suffix string) string {
+	return v.repo + ":" + v.digest.Algorithm().String() + "-" + v.digest.Encoded() + suffix
+}
+
+// fetchTag returns the manifest of a tag, and nil if the tag is not found.
+func (v *artifactVerifier) fetchTag(ref string) (*ocispec.Descriptor, []byte, error) {
+	_, desc, err := v.resolver.Resolve(v.ctx, ref)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	data, err := v.fetch(desc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &desc, data, nil
+}
+
+func (v *artifactVerifier) fetch(desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxArtifactSize {
+		log.Errorf("Artifact %s of %s is too large", desc.Digest, v.repo)
+		return nil, eputils.GetError("errImageSignature")
+	}
+	return fetchManifest(v.ctx, v.fetcher, desc)
+}
+
+// referrers returns the manifests which refer to the image in the OCI
+// referrers tag schema.
+func (v *artifactVerifier) referrers() ([]referrer, error) {
+	_, data, err := v.fetchTag(v.tag(""))
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var index referrersIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, err
+	}
+	return index.Manifests, nil
+}
+
+func (v *artifactVerifier) verifyCosign(keys []verifyKey) (string, error) {
+	_, data, err := v.fetchTag(v.tag(".sig"))
+	if err != nil {
+		return "", err
+	}
+	if data == nil {
+		log.Errorf("No cosign signature found for %s@%s", v.repo, v.digest)
+		return "", eputils.GetError("errImageSignature")
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", err
+	}
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		payload, err := v.fetch(layer)
+		if err != nil {
+			return "", err
+		}
+		var p cosignPayload
+		if err := json.Unmarshal(payload, &p); err != nil || p.Critical.Image.DockerManifestDigest != v.digest.String() {
+			continue
+		}
+		for _, key := range keys {
+			if verifyCosignSignature(key.pub, payload, sig) {
+				return key.file, nil
+			}
+		}
+	}
+	log.Errorf("No valid cosign signature found for %s@%s", v.repo, v.digest)
+	return "", eputils.GetError("errImageSignature")
+}
+
+// verifyCosignSignature checks a signature of SHA256 as cosign makes it.
+func verifyCosignSignature(pub crypto.PublicKey, payload, sig []byte) bool {
+	hashed := sha256.Sum256(payload)
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig) == nil
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(key, hashed[:], sig)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, sig)
+	}
+	return false
+}
+
+func (v *artifactVerifier) verifyNotation(keys []verifyKey) (string, error) {
+	roots := x509.NewCertPool()
+	for _, key := range keys {
+		if key.cert == nil {
+			log.Errorf("Key %s is not a certificate, notation requires certificates", key.file)
+			return "", eputils.GetError("errImageVerifyKey")
+		}
+		roots.AddCert(key.cert)
+	}
+
+	refs, err := v.referrers()
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.ArtifactType != notationArtifactType {
+			continue
+		}
+		data, err := v.fetch(ref.Descriptor)
+		if err != nil {
+			return "", err
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			continue
+		}
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != notationJWSMediaType {
+				continue
+			}
+			envelope, err := v.fetch(layer)
+			if err != nil {
+				return "", err
+			}
+			if root := v.verifyJWS(envelope, roots); root != nil {
+				for _, key := range keys {
+					if key.cert.Equal(root) {
+						return key.file, nil
+					}
+				}
+			}
+		}
+	}
+	log.Errorf("No valid notation signature found for %s@%s", v.repo, v.digest)
+	return "", eputils.GetError("errImageSignature")
+}
+
+// verifyJWS checks a notation JWS envelope of the image, and returns the
+// trusted root certificate of the signing certificate.
+func (v *artifactVerifier) verifyJWS(data []byte, roots *x509.CertPool) *x509.Certificate {
+	var envelope notationEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || len(envelope.Header.CertChain) == 0 {
+		return nil
+	}
+	protectedData, err := base64.RawURLEncoding.DecodeString(envelope.Protected)
+	if err != nil {
+		return nil
+	}
+	var protected notationProtected
+	if err := json.Unmarshal(protectedData, &protected); err != nil {
+		return nil
+	}
+	payloadData, err := base64.RawURLEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil
+	}
+	var payload notationPayload
+	if err := json.Unmarshal(payloadData, &payload); err != nil || payload.TargetArtifact.Digest != v.digest {
+		return nil
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(envelope.Signature)
+	if err != nil {
+		return nil
+	}
+
+	var certs []*x509.Certificate
+	for _, der := range envelope.Header.CertChain {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil
+		}
+		certs = append(certs, cert)
+	}
+	if !verifyJWSSignature(protected.Alg, certs[0].PublicKey, []byte(envelope.Protected+"."+envelope.Payload), sig) {
+		return nil
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	// The certificates are checked at the signing time, so signatures
+	// stay valid after the signing certificate expires.
+	if signingTime, err := time.Parse(time.RFC3339, protected.SigningTime); err == nil {
+		opts.CurrentTime = signingTime
+	}
+	chains, err := certs[0].Verify(opts)
+	if err != nil || len(chains) == 0 {
+		log.Debugf("Certificate chain of notation signature is not trusted: %v", err)
+		return nil
+	}
+	return chains[0][len(chains[0])-1]
+}
+
+// verifyJWSSignature checks a JWS signature with the RSASSA-PSS or ECDSA
+// algorithms allowed by notation.
+func verifyJWSSignature(alg string, pub crypto.PublicKey, signingInput, sig []byte) bool {
+	if len(alg) != 5 {
+		return false
+	}
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	h := hash.New()
+	h.Write(signingInput)
+	hashed := h.Sum(nil)
+
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "PS") {
+			return false
+		}
+		return rsa.VerifyPSS(key, hash, hashed, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(key, hashed, r, s)
+	}
+	return false
+}
+
+// findSBOM returns the digest of an SBOM attached to the image, by cosign
+// or as an OCI referrer.
+func (v *artifactVerifier) findSBOM() (string, error) {
+	desc, _, err := v.fetchTag(v.tag(".sbom"))
+	if err != nil {
+		return "", err
+	}
+	if desc != nil {
+		return desc.Digest.String(), nil
+	}
+	refs, err := v.referrers()
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		artifactType := strings.ToLower(ref.ArtifactType)
+		if strings.Contains(artifactType, "spdx") || strings.Contains(artifactType, "cyclonedx") {
+			return ref.Digest.String(), nil
+		}
+	}
+	log.Errorf("No SBOM found for %s@%s", v.repo, v.digest)
+	return "", eputils.GetError("errImageSBOM")
+}
@@ -0,0 +1,370 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package docker
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return file
+}
+
+func writePublicKey(t *testing.T, name string, pub crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	return writePEM(t, name, "PUBLIC KEY", der)
+}
+
+func newTestCert(t *testing.T, cn string, pub crypto.PublicKey, parent *x509.Certificate, parentKey crypto.Signer) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+		parent = tmpl
+	} else {
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// addTestImage adds an image with a config of its repository, so that
+// the images of the tests have different digests.
+func (r *fakeRegistry) addTestImage(t *testing.T, repo, tag string) ocispec.Descriptor {
+	return r.addManifest(t, repo, tag, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    r.addBlob(repo, ocispec.MediaTypeImageConfig, []byte(`{"repository":"`+repo+`"}`)),
+	})
+}
+
+// addCosignSignature attaches a cosign signature of target to an image.
+func (r *fakeRegistry) addCosignSignature(t *testing.T, repo string, image, target digest.Digest, key crypto.Signer) {
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + repo + `"},"image":{"docker-manifest-digest":"` +
+		target.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	hashed := sha256.Sum256(payload)
+	sig, err := key.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	layer := r.addBlob(repo, "application/vnd.dev.cosign.simplesigning.v1+json", payload)
+	layer.Annotations = map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+	r.addManifest(t, repo, "sha256-"+image.Encoded()+".sig", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		Config:    r.addBlob(repo, "application/vnd.oci.image.config.v1+json", []byte("{}")),
+		Layers:    []ocispec.Descriptor{layer},
+	})
+}
+
+// notationSignature makes a notation JWS envelope of target signed with
+// ES256 by a leaf certificate.
+func notationSignature(t *testing.T, target digest.Digest, key *ecdsa.PrivateKey, chain ...*x509.Certificate) []byte {
+	protected := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","cty":"application/vnd.cncf.notary.payload.v1+json",` +
+		`"io.cncf.notary.signingScheme":"notary.x509","io.cncf.notary.signingTime":"` + time.Now().Format(time.RFC3339) + `"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"targetArtifact":{"mediaType":"` + ocispec.MediaTypeImageManifest +
+		`","digest":"` + target.String() + `","size":16}}`))
+	hashed := sha256.Sum256([]byte(protected + "." + payload))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hashed[:])
+	require.NoError(t, err)
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	var x5c [][]byte
+	for _, cert := range chain {
+		x5c = append(x5c, cert.Raw)
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"payload":   payload,
+		"protected": protected,
+		"header":    map[string]interface{}{"x5c": x5c},
+		"signature": base64.RawURLEncoding.EncodeToString(sig),
+	})
+	require.NoError(t, err)
+	return data
+}
+
+// addReferrer adds an artifact referring to an image to the referrers
+// index of the image.
+func (r *fakeRegistry) addReferrer(t *testing.T, repo string, image digest.Digest, artifactType, layerType string, data []byte) {
+	desc := r.addManifest(t, repo, "", ocispec.MediaTypeImageManifest, map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     ocispec.MediaTypeImageManifest,
+		"artifactType":  artifactType,
+		"config":        r.addBlob(repo, "application/vnd.oci.empty.v1+json", []byte("{}")),
+		"layers":        []ocispec.Descriptor{r.addBlob(repo, layerType, data)},
+		"subject":       ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: image},
+	})
+	tag := "sha256-" + image.Encoded()
+	index := referrersIndex{}
+	if m, ok := r.manifests[repo+"@"+tag]; ok {
+		require.NoError(t, json.Unmarshal(m.data, &index))
+	}
+	index.Manifests = append(index.Manifests, referrer{Descriptor: desc, ArtifactType: artifactType})
+	r.addManifest(t, repo, tag, ocispec.MediaTypeImageIndex, map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     ocispec.MediaTypeImageIndex,
+		"manifests":     index.Manifests,
+	})
+}
+
+func TestImageVerify(t *testing.T) {
+	reg, host := newFakeRegistry(t, "", "")
+
+	cosignKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	cosignKeyFile := writePublicKey(t, "cosign.pub", cosignKey.Public())
+	otherKeyFile := writePublicKey(t, "other.pub", otherKey.Public())
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ca := newTestCert(t, "test ca", caKey.Public(), nil, caKey)
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leaf := newTestCert(t, "test signer", leafKey.Public(), ca, caKey)
+	caFile := writePEM(t, "ca.crt", "CERTIFICATE", ca.Raw)
+	otherCA := newTestCert(t, "other ca", otherKey.Public(), nil, otherKey)
+	otherCAFile := writePEM(t, "other-ca.crt", "CERTIFICATE", otherCA.Raw)
+	invalidKeyFile := writePEM(t, "invalid.pem", "PUBLIC KEY", []byte("invalid"))
+
+	signed := reg.addTestImage(t, "library/signed", "1.0")
+	reg.addCosignSignature(t, "library/signed", signed.Digest, signed.Digest, cosignKey)
+	reg.addManifest(t, "library/signed", "sha256-"+signed.Digest.Encoded()+".sbom", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+	})
+	sbom := reg.manifests["library/signed@sha256-"+signed.Digest.Encoded()+".sbom"].digest
+
+	replayed := reg.addTestImage(t, "library/replayed", "1.0")
+	reg.addCosignSignature(t, "library/replayed", replayed.Digest, signed.Digest, cosignKey)
+
+	notation := reg.addTestImage(t, "library/notation", "1.0")
+	reg.addReferrer(t, "library/notation", notation.Digest, notationArtifactType, notationJWSMediaType,
+		notationSignature(t, notation.Digest, leafKey, leaf, ca))
+	reg.addReferrer(t, "library/notation", notation.Digest, "application/spdx+json", "application/spdx+json", []byte("{}"))
+	notationSBOM := reg.manifests["library/notation@sha256-"+notation.Digest.Encoded()]
+
+	unsigned := reg.addTestImage(t, "library/unsigned", "1.0")
+
+	cases := []struct {
+		name    string
+		image   string
+		conf    *pluginapi.CustomconfigImageVerification
+		want    *pluginapi.ImagesItems0Verification
+		wantErr error
+	}{
+		{
+			name:  "no signature",
+			image: host + "/library/unsigned:1.0",
+			conf:  &pluginapi.CustomconfigImageVerification{Signature: "none"},
+			want:  &pluginapi.ImagesItems0Verification{Result: "verified", Digest: unsigned.Digest.String(), Signature: "none"},
+		},
+		{
+			name:  "cosign",
+			image: host + "/library/signed:1.0",
+			conf: &pluginapi.CustomconfigImageVerification{
+				Signature:         "cosign",
+				Keys:              []string{otherKeyFile, cosignKeyFile},
+				RequireSbom:       true,
+				AllowedRegistries: []string{"docker.io", host + "/library"},
+			},
+			want: &pluginapi.ImagesItems0Verification{
+				Result:    "verified",
+				Digest:    signed.Digest.String(),
+				Signature: "cosign",
+				Key:       cosignKeyFile,
+				Sbom:      sbom.String(),
+			},
+		},
+		{
+			name:    "cosign wrong key",
+			image:   host + "/library/signed:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "cosign", Keys: []string{otherKeyFile}},
+			wantErr: eputils.GetError("errImageSignature"),
+		},
+		{
+			name:    "cosign signature of another image",
+			image:   host + "/library/replayed:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "cosign", Keys: []string{cosignKeyFile}},
+			wantErr: eputils.GetError("errImageSignature"),
+		},
+		{
+			name:    "cosign unsigned",
+			image:   host + "/library/unsigned:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "cosign", Keys: []string{cosignKeyFile}},
+			wantErr: eputils.GetError("errImageSignature"),
+		},
+		{
+			name:  "notation",
+			image: host + "/library/notation:1.0",
+			conf: &pluginapi.CustomconfigImageVerification{
+				Signature:   "notation",
+				Keys:        []string{otherCAFile, caFile},
+				RequireSbom: true,
+			},
+			want: &pluginapi.ImagesItems0Verification{
+				Result:    "verified",
+				Digest:    notation.Digest.String(),
+				Signature: "notation",
+				Key:       caFile,
+				Sbom:      getReferrer(t, notationSBOM.data, "application/spdx+json").String(),
+			},
+		},
+		{
+			name:    "notation untrusted",
+			image:   host + "/library/notation:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "notation", Keys: []string{otherCAFile}},
+			wantErr: eputils.GetError("errImageSignature"),
+		},
+		{
+			name:    "notation public key",
+			image:   host + "/library/notation:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "notation", Keys: []string{cosignKeyFile}},
+			wantErr: eputils.GetError("errImageVerifyKey"),
+		},
+		{
+			name:    "notation unsigned",
+			image:   host + "/library/unsigned:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "notation", Keys: []string{caFile}},
+			wantErr: eputils.GetError("errImageSignature"),
+		},
+		{
+			name:    "no key",
+			image:   host + "/library/signed:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "cosign"},
+			wantErr: eputils.GetError("errImageVerifyKey"),
+		},
+		{
+			name:    "invalid key",
+			image:   host + "/library/signed:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{Signature: "cosign", Keys: []string{invalidKeyFile}},
+			wantErr: eputils.GetError("errImageVerifyKey"),
+		},
+		{
+			name:    "no sbom",
+			image:   host + "/library/unsigned:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{RequireSbom: true},
+			wantErr: eputils.GetError("errImageSBOM"),
+		},
+		{
+			name:    "registry not allowed",
+			image:   host + "/library/signed:1.0",
+			conf:    &pluginapi.CustomconfigImageVerification{AllowedRegistries: []string{"docker.io"}},
+			wantErr: eputils.GetError("errImageRegistry"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ImageVerify(tc.image, tc.conf)
+			if tc.wantErr != nil {
+				require.Equal(t, tc.wantErr, err)
+				require.Equal(t, "failed", result.Result)
+				require.Equal(t, tc.wantErr.Error(), result.Error)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, result)
+		})
+	}
+}
+
+func getReferrer(t *testing.T, data []byte, artifactType string) digest.Digest {
+	var index referrersIndex
+	require.NoError(t, json.Unmarshal(data, &index))
+	for _, ref := range index.Manifests {
+		if ref.ArtifactType == artifactType {
+			return ref.Digest
+		}
+	}
+	t.Fatalf("no referrer of type %s", artifactType)
+	return ""
+}
+
+func TestRegistryAllowed(t *testing.T) {
+	cases := []struct {
+		name    string
+		image   string
+		allowed []string
+		want    bool
+	}{
+		{name: "no allow-list", image: "docker.io/library/busybox", want: true},
+		{name: "registry", image: "docker.io/library/busybox", allowed: []string{"k8s.gcr.io", "docker.io"}, want: true},
+		{name: "path", image: "quay.io/calico/node", allowed: []string{"quay.io/calico/"}, want: true},
+		{name: "other path", image: "quay.io/tigera/operator", allowed: []string{"quay.io/calico"}},
+		{name: "name prefix", image: "docker.io.example.com/app", allowed: []string{"docker.io"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, RegistryAllowed(tc.image, tc.allowed))
+		})
+	}
+}
+
+func TestImagePinned(t *testing.T) {
+	const dgst = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	cases := []struct {
+		name    string
+		image   string
+		digest  string
+		want    string
+		wantErr bool
+	}{
+		{name: "tag", image: "busybox:1.35", digest: dgst, want: "docker.io/library/busybox@" + dgst},
+		{name: "registry", image: "10.10.10.10:9000/library/test:1.0", digest: dgst, want: "10.10.10.10:9000/library/test@" + dgst},
+		{name: "digest", image: "quay.io/calico/node@sha256:2222222222222222222222222222222222222222222222222222222222222222", digest: dgst, want: "quay.io/calico/node@" + dgst},
+		{name: "no digest", image: "busybox:1.35", wantErr: true},
+		{name: "invalid digest", image: "busybox:1.35", digest: "sha256:1111", wantErr: true},
+		{name: "invalid image", image: "Busybox", digest: dgst, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ImagePinned(tc.image, tc.digest)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
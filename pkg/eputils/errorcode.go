@@ -8,6 +8,7 @@
 package eputils
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -96,6 +97,23 @@ var ErrorGroup = map[string]error{
 	"errIgnoreFormat":           &EC_errors{"E001.049", "Ignore format error", ""},
 	"errUnknownCmdType":         &EC_errors{"E001.050", "Unknown command type", ""},
 	"errBinary":                 &EC_errors{"E001.051", "binary is not specified in cluster manifest", ""},
+	"errStepDepends":            &EC_errors{"E001.052", "Workflow step depends on an unknown step", ""},
+	"errWorkflowPlan":           &EC_errors{"E001.053", "Workflow plan has errors", ""},
+	"errStepLimits":             &EC_errors{"E001.054", "Invalid timeout or backoff in workflow step", ""},
+	"errStepExhausted":          &EC_errors{"E001.055", "Workflow step failed after exhausting its timeout and retries", ""},
+	"errOutputFormat":           &EC_errors{"E001.056", "Unsupported output format", ""},
+	"errPluginCommand":          &EC_errors{"E001.057", "Failed to run plugin command", ""},
+	"errHookSpec":               &EC_errors{"E001.058", "Workflow hook must run either an executor spec or a plugin", ""},
+	"errHookFailed":             &EC_errors{"E001.059", "Workflow hook failed", ""},
+	"errWorkflowCancelled":      &EC_errors{"E001.060", "Workflow is cancelled", ""},
+	"errStepWhen":               &EC_errors{"E001.061", "Invalid when expression in workflow step", ""},
+	"errNodeNotFound":           &EC_errors{"E001.062", "Node is not found in kit config", ""},
+	"errExecSerial":             &EC_errors{"E001.063", "Invalid serial in executor step", ""},
+	"errExecMaxFail":            &EC_errors{"E001.064", "Executor step failed on more nodes than its max_fail_percentage allows", ""},
+	"errExecCmdArgs":            &EC_errors{"E001.065", "Invalid arguments of executor command", ""},
+	"errWaitForTimeout":         &EC_errors{"E001.066", "Timed out waiting for the condition of executor command", ""},
+	"errRegistryUrl":            &EC_errors{"E001.067", "External registry URL is not set or invalid", ""},
+	"errRegistryType":           &EC_errors{"E001.068", "Unknown registry type in custom config", ""},
 
 	// E001.1**: kind cluster errors
 	"errCreateKIND": &EC_errors{"E001.101", "Failed to create KIND cluster", ""},
@@ -168,6 +186,10 @@ var ErrorGroup = map[string]error{
 	"errCertNil":         &EC_errors{"E004.009", "cert path or Key path is nil", ""},
 	"errKeyAlgo":         &EC_errors{"E004.010", "unsupported key algo", ""},
 	"errRootCert":        &EC_errors{"E004.011", "failed to parse root certificate", ""},
+	"errHostKeyUnknown":  &EC_errors{"E004.012", "SSH host key of the node is unknown, run \"conductor node trust\" to accept it", ""},
+	"errHostKeyMismatch": &EC_errors{"E004.013", "SSH host key of the node has changed, run \"conductor node trust\" if it is expected", ""},
+	"errBundleSignature": &EC_errors{"E004.014", "signature of the bundle manifest is not valid", ""},
+	"errBundleKey":       &EC_errors{"E004.015", "unsupported or invalid bundle signing key", ""},
 
 	// E005: Utility errors
 	// E005.0**: Docker errors
@@ -188,6 +210,14 @@ var ErrorGroup = map[string]error{
 	"errOrasDefaultResolver": &EC_errors{"E005.015", "Oras default resolver not found", ""},
 	"errOrasResolver":        &EC_errors{"E005.016", "Oras resolver not found", ""},
 	"errOras":                &EC_errors{"E005.017", "It's an oras error", ""},
+	"errContainerNotRunning": &EC_errors{"E005.018", "container is not running", ""},
+	"errImageMediaType":      &EC_errors{"E005.019", "unsupported media type of image manifest", ""},
+	"errImageDigest":         &EC_errors{"E005.020", "digest of the image content does not match its descriptor", ""},
+	"errImageSignature":      &EC_errors{"E005.021", "image signature is not found or not valid", ""},
+	"errImageSBOM":           &EC_errors{"E005.022", "no SBOM is attached to the image", ""},
+	"errImageRegistry":       &EC_errors{"E005.023", "image registry is not in the allowed registries", ""},
+	"errImageVerifyKey":      &EC_errors{"E005.024", "unsupported or invalid image verification key", ""},
+	"errImageNotVerified":    &EC_errors{"E005.025", "image does not match the digest it was verified with", ""},
 
 	// E005.1**: Harbor errors
 	"errHarborIPEmpty":  &EC_errors{"E005.101", "input harbor IP is empty", ""},
@@ -214,6 +244,8 @@ var ErrorGroup = map[string]error{
 	"errInputArryEmpty": &EC_errors{"E005.209", "Files for cluster deployment are not found. Please run \"cluster build\" first", ""},
 	"errUrlSchema":      &EC_errors{"E005.210", "URL Schema Not Supported", ""},
 	"errNoFileDir":      &EC_errors{"E005.211", "no such file or directory", ""},
+	"errBundleDigest":   &EC_errors{"E005.212", "digest of the bundle content does not match the manifest", ""},
+	"errBundlePath":     &EC_errors{"E005.213", "bundle contains an invalid file path", ""},
 
 	// E005.3**: Hash errors
 	"errShaCheckFailed": &EC_errors{"E005.301", "SHA256 check failed", ""},
@@ -235,3 +267,13 @@ var ErrorGroup = map[string]error{
 func GetError(errName string) error {
 	return ErrorGroup[errName]
 }
+
+// GetErrorCode returns the code of an Edge Conductor error, or an empty
+// string for any other error.
+func GetErrorCode(err error) string {
+	var ecerr *EC_errors
+	if errors.As(err, &ecerr) {
+		return ecerr.Code()
+	}
+	return ""
+}
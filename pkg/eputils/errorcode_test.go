@@ -7,6 +7,7 @@
 package eputils
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -30,3 +31,15 @@ func TestErrorcode(t *testing.T) {
 		t.Fatalf("Get Error message failed.")
 	}
 }
+
+func TestGetErrorCode(t *testing.T) {
+	if code := GetErrorCode(GetError("errPluginComplete")); code != "E001.024" {
+		t.Fatalf("Get error code failed, got %s.", code)
+	}
+	if code := GetErrorCode(fmt.Errorf("wrapped: %w", GetError("errPluginComplete"))); code != "E001.024" {
+		t.Fatalf("Get wrapped error code failed, got %s.", code)
+	}
+	if code := GetErrorCode(fmt.Errorf("other error")); code != "" {
+		t.Fatalf("Get code of other error failed, got %s.", code)
+	}
+}
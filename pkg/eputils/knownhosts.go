@@ -0,0 +1,162 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// Trust on first use: the key of an unknown node is added to the
+	// known_hosts file.
+	HostKeyPolicyTofu = "tofu"
+	// Only the keys already in the known_hosts file are accepted.
+	HostKeyPolicyStrict = "strict"
+)
+
+var (
+	// KnownHostsFile is the known_hosts file managed by the conductor.
+	KnownHostsFile = "runtime/known_hosts"
+
+	hostKeyPolicy = HostKeyPolicyTofu
+	knownHostsMu  sync.Mutex
+)
+
+// SetHostKeyPolicy sets how the keys of unknown nodes are handled. The
+// default policy is HostKeyPolicyTofu.
+func SetHostKeyPolicy(policy string) {
+	if policy == "" {
+		policy = HostKeyPolicyTofu
+	}
+	hostKeyPolicy = policy
+}
+
+func openKnownHosts() (ssh.HostKeyCallback, error) {
+	if err := os.MkdirAll(filepath.Dir(KnownHostsFile), os.FileMode(0700)); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(KnownHostsFile, os.O_CREATE|os.O_RDONLY, os.FileMode(0600))
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return knownhosts.New(KnownHostsFile)
+}
+
+func addKnownHost(address string, key ssh.PublicKey) error {
+	f, err := os.OpenFile(KnownHostsFile, os.O_APPEND|os.O_WRONLY, os.FileMode(0600))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(address)}, key))
+	return err
+}
+
+// HostKeyCallback verifies the host key of a node. A node with a pinned
+// fingerprint, e.g. "SHA256:..." as printed by ssh-keygen -l, must present
+// the key with this fingerprint. Other nodes are verified with the
+// known_hosts file of the conductor, according to the host key policy.
+func HostKeyCallback(fingerprint string) ssh.HostKeyCallback {
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if fingerprint != "" {
+			if ssh.FingerprintSHA256(key) != fingerprint {
+				log.Errorf("Host key of %s is %s, but %s is pinned", hostname, ssh.FingerprintSHA256(key), fingerprint)
+				return GetError("errHostKeyMismatch")
+			}
+			return nil
+		}
+
+		knownHostsMu.Lock()
+		defer knownHostsMu.Unlock()
+		callback, err := openKnownHosts()
+		if err != nil {
+			return err
+		}
+		err = callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			log.Errorf("Host key of %s is %s, which does not match %s:%d",
+				hostname, ssh.FingerprintSHA256(key), keyErr.Want[0].Filename, keyErr.Want[0].Line)
+			return GetError("errHostKeyMismatch")
+		}
+		if hostKeyPolicy == HostKeyPolicyStrict {
+			log.Errorf("Host key of %s is not in %s", hostname, KnownHostsFile)
+			return GetError("errHostKeyUnknown")
+		}
+		log.Warningf("Trust host key %s of %s on first use", ssh.FingerprintSHA256(key), hostname)
+		return addKnownHost(hostname, key)
+	}
+}
+
+// TrustHostKey replaces the keys of the address in the known_hosts file
+// with key, e.g. after the node is reprovisioned.
+func TrustHostKey(address string, key ssh.PublicKey) error {
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+	if _, err := openKnownHosts(); err != nil {
+		return err
+	}
+	f, err := os.Open(KnownHostsFile)
+	if err != nil {
+		return err
+	}
+	host := knownhosts.Normalize(address)
+	lines := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == host {
+			continue
+		}
+		lines = append(lines, scanner.Text())
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	lines = append(lines, knownhosts.Line([]string{host}, key))
+	return WriteStringToFile(strings.Join(lines, "\n")+"\n", KnownHostsFile)
+}
+
+// ScanHostKey returns the host key presented by the SSH server at address,
+// without authenticating.
+func ScanHostKey(address string) (ssh.PublicKey, error) {
+	var hostKey ssh.PublicKey
+	config := &ssh.ClientConfig{
+		User:    "conductor",
+		Timeout: 5 * time.Second,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return nil
+		},
+	}
+	client, err := DialSSH(address, config)
+	if client != nil {
+		client.Close()
+	}
+	if hostKey == nil {
+		return nil, err
+	}
+	return hostKey, nil
+}
@@ -0,0 +1,118 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return key
+}
+
+func TestHostKeyCallback(t *testing.T) {
+	defer SetHostKeyPolicy("")
+	key := newHostKey(t)
+	newKey := newHostKey(t)
+
+	cases := []struct {
+		name        string
+		policy      string
+		host        string
+		fingerprint string
+		key         ssh.PublicKey
+		trust       bool
+		expectError error
+	}{
+		{
+			name:        "pinned fingerprint",
+			host:        "10.0.0.1:22",
+			fingerprint: ssh.FingerprintSHA256(key),
+			key:         key,
+		},
+		{
+			name:        "pinned fingerprint mismatch",
+			host:        "10.0.0.1:22",
+			fingerprint: ssh.FingerprintSHA256(key),
+			key:         newKey,
+			expectError: GetError("errHostKeyMismatch"),
+		},
+		{
+			name:        "strict unknown host",
+			policy:      HostKeyPolicyStrict,
+			host:        "10.0.0.2:22",
+			key:         key,
+			expectError: GetError("errHostKeyUnknown"),
+		},
+		{
+			name: "trust on first use",
+			host: "10.0.0.2:22",
+			key:  key,
+		},
+		{
+			name:   "strict known host",
+			policy: HostKeyPolicyStrict,
+			host:   "10.0.0.2:22",
+			key:    key,
+		},
+		{
+			name:        "changed host key",
+			host:        "10.0.0.2:22",
+			key:         newKey,
+			expectError: GetError("errHostKeyMismatch"),
+		},
+		{
+			name:   "trusted new host key",
+			policy: HostKeyPolicyStrict,
+			host:   "10.0.0.2:22",
+			key:    newKey,
+			trust:  true,
+		},
+		{
+			name:   "other port",
+			policy: HostKeyPolicyTofu,
+			host:   "10.0.0.2:2222",
+			key:    key,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetHostKeyPolicy(tc.policy)
+			if tc.trust {
+				require.NoError(t, TrustHostKey(tc.host, tc.key))
+			}
+			host, port, err := net.SplitHostPort(tc.host)
+			require.NoError(t, err)
+			remote, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:%s", host, port))
+			require.NoError(t, err)
+			err = HostKeyCallback(tc.fingerprint)(tc.host, remote, tc.key)
+			require.Equal(t, tc.expectError, err)
+		})
+	}
+}
+
+func TestScanHostKey(t *testing.T) {
+	key, err := ScanHostKey(fmt.Sprintf("%s:%d", sshHost, sshPort))
+	require.NoError(t, err)
+	require.NotNil(t, key)
+
+	_, err = ScanHostKey(fmt.Sprintf("%s:%d", sshHost, 2223))
+	require.Error(t, err)
+}
@@ -0,0 +1,80 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package eputils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	papi "github.com/intel/edge-conductor/pkg/api/plugins"
+)
+
+func init() {
+	templateFuncs["registrytype"] = RegistryType
+	templateFuncs["registryaddr"] = RegistryAddress
+}
+
+// RegistryType: Get the type of the day-0 registry in a kit config.
+//   The type defaults to "external" if an external URL is given, and to
+//   "harbor" otherwise. It is available in templates as "registrytype".
+//
+// Parameters:
+//   kitcfg: Kit config
+//
+func RegistryType(kitcfg *papi.Kitconfig) string {
+	registry := kitRegistry(kitcfg)
+	if registry == nil {
+		return papi.CustomconfigRegistryTypeHarbor
+	}
+	if registry.Type != "" {
+		return registry.Type
+	}
+	if registry.Externalurl != "" {
+		return papi.CustomconfigRegistryTypeExternal
+	}
+	return papi.CustomconfigRegistryTypeHarbor
+}
+
+// RegistryAddress: Get the "host:port" address of the day-0 registry in a
+//   kit config. It is available in templates as "registryaddr".
+//
+// Parameters:
+//   kitcfg: Kit config
+//
+func RegistryAddress(kitcfg *papi.Kitconfig) string {
+	if kitcfg == nil || kitcfg.Parameters == nil {
+		return ""
+	}
+	if RegistryType(kitcfg) == papi.CustomconfigRegistryTypeExternal {
+		return registryURLHost(kitRegistry(kitcfg).Externalurl)
+	}
+	settings := kitcfg.Parameters.GlobalSettings
+	if settings == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", settings.ProviderIP, settings.RegistryPort)
+}
+
+func kitRegistry(kitcfg *papi.Kitconfig) *papi.CustomconfigRegistry {
+	if kitcfg == nil || kitcfg.Parameters == nil || kitcfg.Parameters.Customconfig == nil {
+		return nil
+	}
+	return kitcfg.Parameters.Customconfig.Registry
+}
+
+func registryURLHost(externalurl string) string {
+	if !strings.Contains(externalurl, "://") {
+		externalurl = "https://" + externalurl
+	}
+	u, err := url.Parse(externalurl)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
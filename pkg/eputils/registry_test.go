@@ -0,0 +1,108 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package eputils
+
+import (
+	"testing"
+
+	papi "github.com/intel/edge-conductor/pkg/api/plugins"
+)
+
+func registryKitConfig(registry *papi.CustomconfigRegistry) *papi.Kitconfig {
+	return &papi.Kitconfig{
+		Parameters: &papi.KitconfigParameters{
+			GlobalSettings: &papi.KitconfigParametersGlobalSettings{
+				ProviderIP:   "10.0.0.1",
+				RegistryPort: "9000",
+			},
+			Customconfig: &papi.Customconfig{
+				Registry: registry,
+			},
+		},
+	}
+}
+
+func TestRegistryTypeAddress(t *testing.T) {
+	cases := []struct {
+		name            string
+		kitcfg          *papi.Kitconfig
+		expectedType    string
+		expectedAddress string
+	}{
+		{
+			name:            "nil kit config",
+			kitcfg:          nil,
+			expectedType:    "harbor",
+			expectedAddress: "",
+		},
+		{
+			name:            "no registry",
+			kitcfg:          registryKitConfig(nil),
+			expectedType:    "harbor",
+			expectedAddress: "10.0.0.1:9000",
+		},
+		{
+			name:            "default harbor",
+			kitcfg:          registryKitConfig(&papi.CustomconfigRegistry{User: "admin"}),
+			expectedType:    "harbor",
+			expectedAddress: "10.0.0.1:9000",
+		},
+		{
+			name:            "registry",
+			kitcfg:          registryKitConfig(&papi.CustomconfigRegistry{Type: "registry"}),
+			expectedType:    "registry",
+			expectedAddress: "10.0.0.1:9000",
+		},
+		{
+			name:            "default external",
+			kitcfg:          registryKitConfig(&papi.CustomconfigRegistry{Externalurl: "https://registry.example.com:5000/v2/"}),
+			expectedType:    "external",
+			expectedAddress: "registry.example.com:5000",
+		},
+		{
+			name:            "external without scheme",
+			kitcfg:          registryKitConfig(&papi.CustomconfigRegistry{Type: "external", Externalurl: "registry.example.com"}),
+			expectedType:    "external",
+			expectedAddress: "registry.example.com",
+		},
+		{
+			name:            "external without url",
+			kitcfg:          registryKitConfig(&papi.CustomconfigRegistry{Type: "external"}),
+			expectedType:    "external",
+			expectedAddress: "",
+		},
+		{
+			name:            "harbor with external url",
+			kitcfg:          registryKitConfig(&papi.CustomconfigRegistry{Type: "harbor", Externalurl: "https://registry.example.com"}),
+			expectedType:    "harbor",
+			expectedAddress: "10.0.0.1:9000",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if output := RegistryType(tc.kitcfg); output != tc.expectedType {
+				t.Errorf("Unexpected type: %v, expected: %v", output, tc.expectedType)
+			}
+			if output := RegistryAddress(tc.kitcfg); output != tc.expectedAddress {
+				t.Errorf("Unexpected address: %v, expected: %v", output, tc.expectedAddress)
+			}
+		})
+	}
+}
+
+func TestRegistryTemplateFuncs(t *testing.T) {
+	kitcfg := registryKitConfig(&papi.CustomconfigRegistry{Type: "registry"})
+	output, err := StringTemplateConvertWithParams(`{{ registrytype .Kitconfig }} {{ registryaddr .Kitconfig }}`,
+		&papi.EpParams{Kitconfig: kitcfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "registry 10.0.0.1:9000" {
+		t.Errorf("Unexpected output: %v", output)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package registryutils
+
+import (
+	"github.com/docker/docker/api/types"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/intel/edge-conductor/pkg/eputils/docker"
+	"github.com/intel/edge-conductor/pkg/eputils/restfulcli"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Registry is the day-0 registry which the images are mirrored to. Kits
+// choose its implementation with the "type" of "customconfig.registry".
+type Registry interface {
+	// Type returns the type of the registry in the custom config.
+	Type() string
+	// Address returns the "host:port" of the registry.
+	Address() string
+	// AuthConf returns the credentials to push images to the registry.
+	AuthConf() *types.AuthConfig
+	// CACert returns the CA certificate file of the registry, or "".
+	CACert() string
+	// MapImages maps image URLs to their repositories on the registry, and
+	// prepares the registry to receive them.
+	MapImages(images []string) ([]string, error)
+}
+
+type registry struct {
+	regType string
+	auth    *types.AuthConfig
+	cacert  string
+}
+
+func (r *registry) Type() string {
+	return r.regType
+}
+
+func (r *registry) Address() string {
+	return r.auth.ServerAddress
+}
+
+func (r *registry) AuthConf() *types.AuthConfig {
+	return r.auth
+}
+
+func (r *registry) CACert() string {
+	return r.cacert
+}
+
+// MapImages of a plain registry only adds the default namespaces to the
+// image URLs, repositories are created when the images are pushed.
+func (r *registry) MapImages(images []string) ([]string, error) {
+	return restfulcli.MapImageURLOnHarbor(append([]string{}, images...))
+}
+
+// harborRegistry is the Harbor started on the day-0 host by "init".
+type harborRegistry struct {
+	registry
+	settings *pluginapi.KitconfigParametersGlobalSettings
+	user     string
+	password string
+}
+
+// MapImages of Harbor creates a project for the first part of the image
+// URLs, as Harbor does not create projects on push.
+func (r *harborRegistry) MapImages(images []string) ([]string, error) {
+	return restfulcli.MapImageURLCreateHarborProject(r.settings.ProviderIP, r.settings.RegistryPort,
+		r.user, r.password, append([]string{}, images...))
+}
+
+// NewRegistry: Get the day-0 registry of a kit config.
+//
+// Parameters:
+//   epParams: EP parameters with the kit config and the registry certificate
+//
+func NewRegistry(epParams *pluginapi.EpParams) (Registry, error) {
+	if epParams == nil || epParams.Kitconfig == nil || epParams.Kitconfig.Parameters == nil ||
+		epParams.Kitconfig.Parameters.GlobalSettings == nil || epParams.Kitconfig.Parameters.Customconfig == nil ||
+		epParams.Kitconfig.Parameters.Customconfig.Registry == nil {
+		return nil, eputils.GetError("errKitCfgParameter")
+	}
+	settings := epParams.Kitconfig.Parameters.GlobalSettings
+	conf := epParams.Kitconfig.Parameters.Customconfig.Registry
+	regType := eputils.RegistryType(epParams.Kitconfig)
+
+	if regType == pluginapi.CustomconfigRegistryTypeExternal {
+		address := eputils.RegistryAddress(epParams.Kitconfig)
+		if address == "" {
+			log.Errorf("Invalid external registry URL %q", conf.Externalurl)
+			return nil, eputils.GetError("errRegistryUrl")
+		}
+		return &registry{
+			regType: regType,
+			auth: &types.AuthConfig{
+				Username:      conf.User,
+				Password:      conf.Password,
+				ServerAddress: address,
+			},
+			cacert: conf.Capath,
+		}, nil
+	}
+
+	auth, err := docker.GetAuthConf(settings.ProviderIP, settings.RegistryPort, conf.User, conf.Password)
+	if err != nil {
+		return nil, err
+	}
+	cacert := ""
+	if epParams.Registrycert != nil && epParams.Registrycert.Ca != nil {
+		cacert = epParams.Registrycert.Ca.Cert
+	}
+	base := registry{regType: regType, auth: auth, cacert: cacert}
+
+	switch regType {
+	case pluginapi.CustomconfigRegistryTypeHarbor:
+		return &harborRegistry{
+			registry: base,
+			settings: settings,
+			user:     conf.User,
+			password: conf.Password,
+		}, nil
+	case pluginapi.CustomconfigRegistryTypeRegistry:
+		return &base, nil
+	default:
+		log.Errorf("Unknown registry type %q", regType)
+		return nil, eputils.GetError("errRegistryType")
+	}
+}
@@ -0,0 +1,210 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package registryutils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/intel/edge-conductor/pkg/eputils/docker"
+	"github.com/intel/edge-conductor/pkg/eputils/restfulcli"
+	"github.com/stretchr/testify/require"
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+var errTest = errors.New("test error")
+
+func unpatchAll(t *testing.T, pList []*mpatch.Patch) {
+	for _, p := range pList {
+		if p != nil {
+			if err := p.Unpatch(); err != nil {
+				t.Errorf("unpatch error: %v", err)
+			}
+		}
+	}
+}
+
+func patchGetAuthConf(t *testing.T, err error) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(docker.GetAuthConf, func(server, port, user, password string) (*types.AuthConfig, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &types.AuthConfig{Username: user, Password: password, ServerAddress: server + ":" + port}, nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+	}
+	return patch
+}
+
+func patchCreateHarborProject(t *testing.T, called *[]string) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(restfulcli.CreateHarborProject, func(authServerAddress, projectName, authStr, DayZeroCertFilePath string) error {
+		*called = append(*called, authServerAddress+"/"+projectName)
+		return nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+	}
+	return patch
+}
+
+func epParamsWithRegistry(registry *pluginapi.CustomconfigRegistry) *pluginapi.EpParams {
+	return &pluginapi.EpParams{
+		Registrycert: &pluginapi.Certificate{
+			Ca: &pluginapi.CertificateCa{Cert: "cert/pki/ca.pem"},
+		},
+		Kitconfig: &pluginapi.Kitconfig{
+			Parameters: &pluginapi.KitconfigParameters{
+				GlobalSettings: &pluginapi.KitconfigParametersGlobalSettings{
+					ProviderIP:   "10.0.0.1",
+					RegistryPort: "9000",
+				},
+				Customconfig: &pluginapi.Customconfig{
+					Registry: registry,
+				},
+			},
+		},
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	cases := []struct {
+		name            string
+		epParams        *pluginapi.EpParams
+		authErr         error
+		wantError       error
+		expectedType    string
+		expectedAddress string
+		expectedUser    string
+		expectedCACert  string
+	}{
+		{
+			name:      "no kit config",
+			epParams:  &pluginapi.EpParams{},
+			wantError: eputils.GetError("errKitCfgParameter"),
+		},
+		{
+			name:      "no registry",
+			epParams:  epParamsWithRegistry(nil),
+			wantError: eputils.GetError("errKitCfgParameter"),
+		},
+		{
+			name:      "auth fail",
+			epParams:  epParamsWithRegistry(&pluginapi.CustomconfigRegistry{User: "admin", Password: "pass"}),
+			authErr:   errTest,
+			wantError: errTest,
+		},
+		{
+			name:            "harbor",
+			epParams:        epParamsWithRegistry(&pluginapi.CustomconfigRegistry{User: "admin", Password: "pass"}),
+			expectedType:    "harbor",
+			expectedAddress: "10.0.0.1:9000",
+			expectedUser:    "admin",
+			expectedCACert:  "cert/pki/ca.pem",
+		},
+		{
+			name:            "registry",
+			epParams:        epParamsWithRegistry(&pluginapi.CustomconfigRegistry{Type: "registry", User: "edge", Password: "pass"}),
+			expectedType:    "registry",
+			expectedAddress: "10.0.0.1:9000",
+			expectedUser:    "edge",
+			expectedCACert:  "cert/pki/ca.pem",
+		},
+		{
+			name: "external",
+			epParams: epParamsWithRegistry(&pluginapi.CustomconfigRegistry{
+				Externalurl: "https://registry.example.com:5000",
+				User:        "edge",
+				Password:    "pass",
+				Capath:      "/etc/ssl/registry-ca.pem",
+			}),
+			authErr:         errTest,
+			expectedType:    "external",
+			expectedAddress: "registry.example.com:5000",
+			expectedUser:    "edge",
+			expectedCACert:  "/etc/ssl/registry-ca.pem",
+		},
+		{
+			name:      "external without url",
+			epParams:  epParamsWithRegistry(&pluginapi.CustomconfigRegistry{Type: "external"}),
+			wantError: eputils.GetError("errRegistryUrl"),
+		},
+		{
+			name:      "unknown type",
+			epParams:  epParamsWithRegistry(&pluginapi.CustomconfigRegistry{Type: "quay"}),
+			wantError: eputils.GetError("errRegistryType"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pList := []*mpatch.Patch{patchGetAuthConf(t, tc.authErr)}
+			defer unpatchAll(t, pList)
+
+			reg, err := NewRegistry(tc.epParams)
+			if tc.wantError != nil {
+				require.ErrorIs(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedType, reg.Type())
+			require.Equal(t, tc.expectedAddress, reg.Address())
+			require.Equal(t, tc.expectedUser, reg.AuthConf().Username)
+			require.Equal(t, tc.expectedCACert, reg.CACert())
+		})
+	}
+}
+
+func TestRegistryMapImages(t *testing.T) {
+	images := []string{"busybox", "calico/node:v3.23", "k8s.gcr.io/pause:3.6"}
+	mapped := []string{"docker.io/library/busybox", "docker.io/calico/node:v3.23", "k8s.gcr.io/pause:3.6"}
+
+	cases := []struct {
+		name             string
+		registry         *pluginapi.CustomconfigRegistry
+		expectedProjects []string
+	}{
+		{
+			name:     "harbor",
+			registry: &pluginapi.CustomconfigRegistry{User: "admin", Password: "pass"},
+			expectedProjects: []string{
+				"10.0.0.1:9000/docker.io",
+				"10.0.0.1:9000/docker.io",
+				"10.0.0.1:9000/k8s.gcr.io",
+			},
+		},
+		{
+			name:     "registry",
+			registry: &pluginapi.CustomconfigRegistry{Type: "registry", User: "edge", Password: "pass"},
+		},
+		{
+			name:     "external",
+			registry: &pluginapi.CustomconfigRegistry{Externalurl: "https://registry.example.com"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var projects []string
+			pList := []*mpatch.Patch{patchGetAuthConf(t, nil), patchCreateHarborProject(t, &projects)}
+			defer unpatchAll(t, pList)
+
+			reg, err := NewRegistry(epParamsWithRegistry(tc.registry))
+			require.NoError(t, err)
+
+			input := append([]string{}, images...)
+			output, err := reg.MapImages(input)
+			require.NoError(t, err)
+			require.Equal(t, mapped, output)
+			require.Equal(t, images, input, "input images are modified")
+			require.Equal(t, tc.expectedProjects, projects)
+		})
+	}
+}
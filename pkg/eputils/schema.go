@@ -43,6 +43,11 @@ func AddSchemaStruct(name string, newFunc func() SchemaStruct) {
 	schemaStructNew[name] = newFunc
 }
 
+func SchemaStructExists(name string) bool {
+	_, has := schemaStructNew[name]
+	return has
+}
+
 func SchemaStructNew(name string) SchemaStruct {
 	if _, has := schemaStructNew[name]; !has {
 		log.Infof("Cannot find schema name: %v, set it to interface{}", name)
@@ -203,7 +208,10 @@ func StringTemplateConvertWithParams(str string, tempParams interface{}) (string
 			return "", GetError("errCustom")
 		}
 	}
-	tpl := template.Must(template.New("StringTemplateConvertWithValue").Funcs(templateFuncs).Parse(str))
+	tpl, err := template.New("StringTemplateConvertWithValue").Funcs(templateFuncs).Parse(str)
+	if err != nil {
+		return "", err
+	}
 	var b bytes.Buffer
 	if err := tpl.Execute(&b, tempParams); err != nil {
 		return "", err
@@ -67,8 +67,7 @@ func GenSSHConfig(server *pluginapi.Node) (*ssh.ClientConfig, error) {
 			Auth: []ssh.AuthMethod{
 				ssh.PublicKeys(signer),
 			},
-			// #nosec G106
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			HostKeyCallback: HostKeyCallback(server.SSHHostKeyFingerprint),
 		}
 	} else {
 		config = &ssh.ClientConfig{
@@ -82,15 +81,14 @@ func GenSSHConfig(server *pluginapi.Node) (*ssh.ClientConfig, error) {
 			Auth: []ssh.AuthMethod{
 				ssh.Password(server.SSHPasswd),
 			},
-			// #nosec G106
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			HostKeyCallback: HostKeyCallback(server.SSHHostKeyFingerprint),
 		}
 	}
 	return config, nil
 }
 
 func RunRemoteCMD(addr string, cfg *ssh.ClientConfig, cmd string) error {
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -112,7 +110,7 @@ func RunRemoteCMD(addr string, cfg *ssh.ClientConfig, cmd string) error {
 }
 
 func RunRemoteMultiCMD(addr string, cfg *ssh.ClientConfig, commands []string) error {
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -183,7 +181,7 @@ func RunRemoteNodeMultiCMD(server *pluginapi.Node, commands []string) error {
 
 func WriteRemoteFile(addr string, cfg *ssh.ClientConfig, content, path string) error {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -223,7 +221,7 @@ func WriteRemoteFile(addr string, cfg *ssh.ClientConfig, content, path string) e
 }
 
 func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error {
-	_, err := ssh.Dial("tcp", addr, cfg)
+	_, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -251,7 +249,7 @@ func CopyLocalFileToRemoteRootFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 
 func CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, localPath, remotePath string) error {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -299,7 +297,7 @@ func CopyLocalFileToRemoteFile(addr string, cfg *ssh.ClientConfig, localPath, re
 }
 
 func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfig, remotePath, localPath string, perm os.FileMode) error {
-	_, err := ssh.Dial("tcp", addr, cfg)
+	_, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -339,7 +337,7 @@ func CopyRemoteRootFileToLocalFileSudoNoPasswd(addr string, cfg *ssh.ClientConfi
 
 func CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, remotePath, localPath string, perm os.FileMode) error {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -393,7 +391,7 @@ func CopyRemoteFileToLocalFile(addr string, cfg *ssh.ClientConfig, remotePath, l
 
 func ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientConfig, containerdcertpath, registry string) error {
 
-	_, err := ssh.Dial("tcp", addr, cfg)
+	_, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -414,7 +412,7 @@ func ContainerdCertificatePathCreateSudoNoPasswd(addr string, cfg *ssh.ClientCon
 }
 
 func ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, serviceName string) error {
-	_, err := ssh.Dial("tcp", addr, cfg)
+	_, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return err
@@ -433,7 +431,7 @@ func ServiceRestartSudoNoPasswd(addr string, cfg *ssh.ClientConfig, serviceName
 
 func RemoteFileExists(addr string, cfg *ssh.ClientConfig, remotePath string) (bool, error) {
 
-	client, err := ssh.Dial("tcp", addr, cfg)
+	client, err := DialSSH(addr, cfg)
 	if err != nil {
 		log.Errorf("Unable to connect:%s %v", addr, err)
 		return false, err
@@ -1544,7 +1544,7 @@ func TestRemoteFileExists(t *testing.T) {
 
 }
 
-func runSSHServer() {
+func runSSHServer(hostKeyFile string) {
 	addr := fmt.Sprintf("%s:%d", sshHost, sshPort)
 	sshd.Handle(func(s sshd.Session) {
 		_, err := io.WriteString(s, "Hello world\n")
@@ -1552,7 +1552,7 @@ func runSSHServer() {
 			log.Errorln(err)
 		}
 	})
-	log.Fatal(sshd.ListenAndServe(addr, nil))
+	log.Fatal(sshd.ListenAndServe(addr, nil, sshd.HostKeyFile(hostKeyFile)))
 }
 
 func TestMain(m *testing.M) {
@@ -1565,6 +1565,23 @@ func TestMain(m *testing.M) {
 		log.Error(err)
 	}
 	nodeWithKey.SSHKey = keystring
-	go runSSHServer()
-	os.Exit(m.Run())
+	// The test server uses the same key as its host key, which is pinned
+	// for the test nodes.
+	if signer, err := ssh.ParsePrivateKey(SSHKey); err == nil {
+		fingerprint := ssh.FingerprintSHA256(signer.PublicKey())
+		for _, n := range []*pluginapi.Node{&nodeWithKey, &nodeWithWrongPort, &nodeWithWrongKey, &nodeNoKey} {
+			n.SSHHostKeyFingerprint = fingerprint
+		}
+	} else {
+		log.Error(err)
+	}
+	knownHostsDir, err := ioutil.TempDir("", "known_hosts")
+	if err != nil {
+		log.Error(err)
+	}
+	KnownHostsFile = filepath.Join(knownHostsDir, "known_hosts")
+	go runSSHServer(keyfile)
+	code := m.Run()
+	os.RemoveAll(knownHostsDir)
+	os.Exit(code)
 }
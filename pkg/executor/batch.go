@@ -0,0 +1,263 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	api "github.com/intel/edge-conductor/pkg/api/ep"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// nodeErrors collects the errors of a command on several nodes, by node IP.
+type nodeErrors struct {
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+func newNodeErrors() *nodeErrors {
+	return &nodeErrors{errs: map[string]error{}}
+}
+
+func (ne *nodeErrors) set(n *nodeInfo, err error) {
+	ne.mu.Lock()
+	defer ne.mu.Unlock()
+	if _, has := ne.errs[n.ip]; !has {
+		ne.errs[n.ip] = err
+	}
+}
+
+func (ne *nodeErrors) Error() string {
+	ips := make([]string, 0, len(ne.errs))
+	for ip := range ne.errs {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	msgs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", ip, ne.errs[ip]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// err returns nil if no node failed.
+func (ne *nodeErrors) err() error {
+	if len(ne.errs) == 0 {
+		return nil
+	}
+	return ne
+}
+
+// nodeResults returns the error of a command on each failed node. An error
+// which is not per node fails all the nodes of the command.
+func nodeResults(err error, nodes map[string]*nodeInfo) map[string]error {
+	results := map[string]error{}
+	var ne *nodeErrors
+	if errors.As(err, &ne) {
+		for ip, nerr := range ne.errs {
+			results[ip] = nerr
+		}
+		return results
+	}
+	for ip := range nodes {
+		results[ip] = err
+	}
+	return results
+}
+
+// splitBatches splits the nodes into batches of the size set by serial, as
+// a number of nodes or as a percentage of the nodes, e.g. "1" or "30%". All
+// the nodes are in one batch if serial is empty.
+func splitBatches(nodes map[string]*nodeInfo, serial string) ([][]*nodeInfo, error) {
+	sorted := make([]*nodeInfo, 0, len(nodes))
+	for _, n := range nodes {
+		sorted = append(sorted, n)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].name != sorted[j].name {
+			return sorted[i].name < sorted[j].name
+		}
+		return sorted[i].ip < sorted[j].ip
+	})
+
+	size := len(sorted)
+	if serial != "" {
+		pct := strings.HasSuffix(serial, "%")
+		v, err := strconv.Atoi(strings.TrimSuffix(serial, "%"))
+		if err != nil || v <= 0 || (pct && v > 100) {
+			log.Errorf("Invalid serial %q, expect a number or a percentage of nodes", serial)
+			return nil, eputils.GetError("errExecSerial")
+		}
+		if pct {
+			v = int(math.Ceil(float64(len(sorted)) * float64(v) / 100))
+		}
+		if v < size {
+			size = v
+		}
+	}
+
+	batches := [][]*nodeInfo{}
+	for len(sorted) > 0 {
+		if size > len(sorted) {
+			size = len(sorted)
+		}
+		batches = append(batches, sorted[:size])
+		sorted = sorted[size:]
+	}
+	return batches, nil
+}
+
+// runStep runs the commands of a step on the nodes batch by batch. A node
+// which fails a command is skipped by the next commands of the step. The
+// step fails once the failed nodes exceed its max_fail_percentage, which is
+// 0 by default, unless it is set to continue on error.
+func (e *Executor) runStep(ctx context.Context, step *api.ExecspecSpecStepsItems0, nodes map[string]*nodeInfo) error {
+	batches, err := splitBatches(nodes, step.Serial)
+	if err != nil {
+		return err
+	}
+	maxFail := int64(0)
+	if step.MaxFailPercentage != nil {
+		maxFail = *step.MaxFailPercentage
+	}
+
+	failed := map[string]error{}
+	// fail records the failed nodes of a command, and returns an error if
+	// the step fails.
+	fail := func(err error, cnodes map[string]*nodeInfo) error {
+		for ip, nerr := range nodeResults(err, cnodes) {
+			log.Errorf("Step %v failed on node %s: %v", step.Name, ip, nerr)
+			failed[ip] = nerr
+			if n, has := cnodes[ip]; has {
+				n.failed++
+			}
+		}
+		if step.ContinueOnError {
+			return nil
+		}
+		if int64(len(failed))*100 > maxFail*int64(len(nodes)) {
+			if maxFail > 0 {
+				log.Errorf("Step %v failed on %d of %d nodes", step.Name, len(failed), len(nodes))
+				return eputils.GetError("errExecMaxFail")
+			}
+			return err
+		}
+		return nil
+	}
+
+	for k, batch := range batches {
+		if len(batches) > 1 {
+			log.Infof("Step %v: batch %d/%d, %d nodes", step.Name, k+1, len(batches), len(batch))
+		}
+		for _, command := range step.Commands {
+			cnodes, err := e.commandNodes(command, batch, failed)
+			if err != nil {
+				return err
+			}
+			guarded, err := e.checkGuards(ctx, command, cnodes)
+			if err != nil {
+				var ne *nodeErrors
+				if !errors.As(err, &ne) {
+					return err
+				}
+				if err := fail(err, cnodes); err != nil {
+					return err
+				}
+			}
+			for ip, n := range cnodes {
+				if _, has := failed[ip]; has {
+					delete(cnodes, ip)
+				} else if guarded[ip] {
+					n.skipped++
+					delete(cnodes, ip)
+				}
+			}
+			if command.Register != "" {
+				for _, n := range batch {
+					if _, has := failed[n.ip]; !has && cnodes[n.ip] == nil {
+						n.register(command.Register, &commandResult{Skipped: true})
+					}
+				}
+			}
+			if len(cnodes) == 0 {
+				log.Debugf("nodes are empty, ignore cmd [%v]", command)
+				continue
+			}
+
+			for _, n := range cnodes {
+				n.beginCommand(command.Type, command.Cmd)
+			}
+			err = e.runCommand(ctx, command, cnodes)
+			if err == eputils.GetError("errUnknownCmdType") {
+				return err
+			}
+			results := map[string]error{}
+			if err != nil {
+				results = nodeResults(err, cnodes)
+			}
+			for ip, n := range cnodes {
+				n.endCommand(command.Type, results[ip])
+				if _, has := results[ip]; !has {
+					n.changed++
+				}
+			}
+			if err != nil {
+				if err := fail(err, cnodes); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		log.Warningf("Step %v failed on %d of %d nodes, continue", step.Name, len(failed), len(nodes))
+	}
+	return nil
+}
+
+// forEachNode runs f on the nodes in parallel, and collects the errors by
+// node.
+func forEachNode(nodes map[string]*nodeInfo, f func(n *nodeInfo) error) error {
+	errs := newNodeErrors()
+	wg := sync.WaitGroup{}
+	wg.Add(len(nodes))
+	for _, n := range nodes {
+		n := n
+		go func() {
+			defer wg.Done()
+			if err := f(n); err != nil {
+				errs.set(n, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errs.err()
+}
+
+// runOnNode runs cmd on a node with the given stdin and stdout.
+func runOnNode(ctx context.Context, n *nodeInfo, cmd []string, stdin io.Reader, stdout io.Writer) error {
+	if err := n.client.Connect(); err != nil {
+		return err
+	}
+	return n.client.CmdWithAttachIO(ctx, cmd, stdin, stdout, n.stderr(), false)
+}
+
+// shellQuote quotes s as a single word of a shell command line.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
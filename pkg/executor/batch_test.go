@@ -0,0 +1,194 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/intel/edge-conductor/pkg/api/ep"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/stretchr/testify/require"
+)
+
+var errNodeCmd = errors.New("node cmd err")
+
+// fakeClient records the commands run on the nodes and fails on the nodes
+// in fail.
+type fakeClient struct {
+	ip   string
+	fail map[string]bool
+	mu   *sync.Mutex
+	runs *[]string
+}
+
+func (c *fakeClient) Connect() error {
+	return nil
+}
+
+func (c *fakeClient) CmdWithAttachIO(ctx context.Context, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	c.mu.Lock()
+	*c.runs = append(*c.runs, fmt.Sprintf("%s %s", c.ip, cmd[0]))
+	c.mu.Unlock()
+	if c.fail[c.ip] {
+		return errNodeCmd
+	}
+	return nil
+}
+
+func (c *fakeClient) Disconnect() error {
+	return nil
+}
+
+func fakeNodes(count int, fail map[string]bool, mu *sync.Mutex, runs *[]string) map[string]*nodeInfo {
+	nodes := map[string]*nodeInfo{}
+	for i := 1; i <= count; i++ {
+		ip := fmt.Sprintf("10.0.0.%d", i)
+		nodes[ip] = &nodeInfo{
+			name:   fmt.Sprintf("node%d", i),
+			ip:     ip,
+			client: &fakeClient{ip: ip, fail: fail, mu: mu, runs: runs},
+		}
+	}
+	return nodes
+}
+
+func TestSplitBatches(t *testing.T) {
+	cases := []struct {
+		name        string
+		nodes       int
+		serial      string
+		wantSizes   []int
+		expectError error
+	}{
+		{name: "no serial", nodes: 5, wantSizes: []int{5}},
+		{name: "one by one", nodes: 3, serial: "1", wantSizes: []int{1, 1, 1}},
+		{name: "batch size", nodes: 5, serial: "2", wantSizes: []int{2, 2, 1}},
+		{name: "larger than nodes", nodes: 2, serial: "10", wantSizes: []int{2}},
+		{name: "percentage", nodes: 5, serial: "30%", wantSizes: []int{2, 2, 1}},
+		{name: "small percentage", nodes: 3, serial: "10%", wantSizes: []int{1, 1, 1}},
+		{name: "no nodes", nodes: 0, serial: "1", wantSizes: []int{}},
+		{name: "zero", nodes: 3, serial: "0", expectError: eputils.GetError("errExecSerial")},
+		{name: "invalid", nodes: 3, serial: "half", expectError: eputils.GetError("errExecSerial")},
+		{name: "over 100%", nodes: 3, serial: "150%", expectError: eputils.GetError("errExecSerial")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			batches, err := splitBatches(fakeNodes(tc.nodes, nil, nil, nil), tc.serial)
+			require.Equal(t, tc.expectError, err)
+			if err != nil {
+				return
+			}
+			sizes := []int{}
+			for _, b := range batches {
+				sizes = append(sizes, len(b))
+			}
+			require.Equal(t, tc.wantSizes, sizes)
+		})
+	}
+}
+
+func TestRunStep(t *testing.T) {
+	maxFail := func(v int64) *int64 { return &v }
+	cases := []struct {
+		name        string
+		step        ep.ExecspecSpecStepsItems0
+		fail        map[string]bool
+		wantRuns    []string
+		expectError error
+	}{
+		{
+			name: "serial",
+			step: ep.ExecspecSpecStepsItems0{Serial: "2"},
+			wantRuns: []string{
+				"10.0.0.1 a", "10.0.0.2 a", "10.0.0.1 b", "10.0.0.2 b",
+				"10.0.0.3 a", "10.0.0.4 a", "10.0.0.3 b", "10.0.0.4 b",
+			},
+		},
+		{
+			name:        "fail fast",
+			step:        ep.ExecspecSpecStepsItems0{Serial: "2"},
+			fail:        map[string]bool{"10.0.0.2": true},
+			wantRuns:    []string{"10.0.0.1 a", "10.0.0.2 a"},
+			expectError: errNodeCmd,
+		},
+		{
+			name: "max fail percentage",
+			step: ep.ExecspecSpecStepsItems0{Serial: "2", MaxFailPercentage: maxFail(25)},
+			fail: map[string]bool{"10.0.0.2": true},
+			wantRuns: []string{
+				"10.0.0.1 a", "10.0.0.2 a", "10.0.0.1 b",
+				"10.0.0.3 a", "10.0.0.4 a", "10.0.0.3 b", "10.0.0.4 b",
+			},
+		},
+		{
+			name:        "max fail percentage exceeded",
+			step:        ep.ExecspecSpecStepsItems0{Serial: "2", MaxFailPercentage: maxFail(25)},
+			fail:        map[string]bool{"10.0.0.2": true, "10.0.0.3": true},
+			wantRuns:    []string{"10.0.0.1 a", "10.0.0.2 a", "10.0.0.1 b", "10.0.0.3 a", "10.0.0.4 a"},
+			expectError: eputils.GetError("errExecMaxFail"),
+		},
+		{
+			name: "continue on error",
+			step: ep.ExecspecSpecStepsItems0{Serial: "1", ContinueOnError: true},
+			fail: map[string]bool{"10.0.0.1": true, "10.0.0.2": true, "10.0.0.3": true},
+			wantRuns: []string{
+				"10.0.0.1 a", "10.0.0.2 a", "10.0.0.3 a", "10.0.0.4 a", "10.0.0.4 b",
+			},
+		},
+		{
+			name:        "invalid serial",
+			step:        ep.ExecspecSpecStepsItems0{Serial: "-1"},
+			wantRuns:    []string{},
+			expectError: eputils.GetError("errExecSerial"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu := &sync.Mutex{}
+			runs := []string{}
+			step := tc.step
+			step.Name = tc.name
+			step.Commands = []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "shell", Cmd: []string{"a"}},
+				{Type: "shell", Cmd: []string{"b"}},
+			}
+			err := New().runStep(context.TODO(), &step, fakeNodes(4, tc.fail, mu, &runs))
+			if tc.expectError == errNodeCmd {
+				var ne *nodeErrors
+				require.True(t, errors.As(err, &ne))
+				require.Equal(t, map[string]error{"10.0.0.2": errNodeCmd}, ne.errs)
+			} else {
+				require.Equal(t, tc.expectError, err)
+			}
+			// The nodes of a batch run in parallel.
+			require.ElementsMatch(t, tc.wantRuns, runs)
+			requireBatchOrder(t, tc.wantRuns, runs)
+		})
+	}
+}
+
+// requireBatchOrder checks that each command on a node runs after the
+// previous commands of the same batch, as listed in want.
+func requireBatchOrder(t *testing.T, want, runs []string) {
+	index := map[string]int{}
+	for i, r := range runs {
+		index[r] = i
+	}
+	for i := 1; i < len(want); i++ {
+		if want[i][len(want[i])-1] != want[i-1][len(want[i-1])-1] {
+			require.Less(t, index[want[i-1]], index[want[i]], "%v should run before %v", want[i-1], want[i])
+		}
+	}
+}
@@ -0,0 +1,151 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/stretchr/testify/require"
+)
+
+func newDay0Executor(t *testing.T) (*Executor, map[string]*nodeInfo) {
+	e := New()
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{}}
+	e.tempParams.Workspace = "/workspace"
+	day0 := &nodeInfo{name: "day-0", ip: "127.0.0.1", client: &day0Client{}}
+	return e, map[string]*nodeInfo{day0.ip: day0}
+}
+
+// requireNodeError checks the error of a command on the day-0 node.
+func requireNodeError(t *testing.T, want, err error) {
+	var ne *nodeErrors
+	require.True(t, errors.As(err, &ne), "%v", err)
+	require.Equal(t, want, ne.errs["127.0.0.1"])
+}
+
+func TestHelperTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "template")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src.conf")
+	require.NoError(t, ioutil.WriteFile(src, []byte("ip={{ .Node.IP }}\nworkspace={{ .Workspace }}\n"), 0600))
+
+	e, nodes := newDay0Executor(t)
+	dest := filepath.Join(dir, "dest.conf")
+	require.NoError(t, e.helperTemplate(context.TODO(), nodes, []string{src, dest, "0600"}))
+	b, err := ioutil.ReadFile(dest)
+	require.NoError(t, err)
+	require.Equal(t, "ip=127.0.0.1\nworkspace=/workspace\n", string(b))
+	info, err := os.Stat(dest)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// The destination is quoted for the nodes running the command line with
+	// a shell.
+	knownHosts := eputils.KnownHostsFile
+	eputils.KnownHostsFile = filepath.Join(dir, "known_hosts")
+	defer func() { eputils.KnownHostsFile = knownHosts }()
+	server, port := shellServer(t)
+	defer server.Close()
+	node := &nodeInfo{name: "node-1", ip: "127.0.0.1", client: &sshClient{host: "127.0.0.1", port: port, user: "ec", password: "123456"}}
+	defer node.client.Disconnect()
+	spaced := filepath.Join(dir, "dest dir", "dest.conf")
+	require.NoError(t, os.Mkdir(filepath.Dir(spaced), 0700))
+	require.NoError(t, e.helperTemplate(context.TODO(), map[string]*nodeInfo{node.ip: node}, []string{src, spaced}))
+	b, err = ioutil.ReadFile(spaced)
+	require.NoError(t, err)
+	require.Equal(t, "ip=127.0.0.1\nworkspace=/workspace\n", string(b))
+
+	require.Equal(t, eputils.GetError("errExecCmdArgs"), e.helperTemplate(context.TODO(), nodes, []string{src}))
+	require.Error(t, e.helperTemplate(context.TODO(), nodes, []string{filepath.Join(dir, "missing"), dest}))
+	require.Error(t, e.helperTemplate(context.TODO(), nodes, []string{src, filepath.Join(dir, "missing", "dest")}))
+}
+
+func TestHelperWaitFor(t *testing.T) {
+	defer func(d time.Duration) { waitForInterval = d }(waitForInterval)
+	waitForInterval = 10 * time.Millisecond
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed.Close()
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	cases := []struct {
+		name        string
+		cmd         []string
+		expectError error
+		nodeError   bool
+	}{
+		{name: "tcp", cmd: []string{"tcp", listener.Addr().String()}},
+		{name: "tcp timeout", cmd: []string{"tcp", closed.Addr().String(), "50ms"}, expectError: eputils.GetError("errWaitForTimeout"), nodeError: true},
+		{name: "http", cmd: []string{"http", ok.URL}},
+		{name: "http not found", cmd: []string{"http", notFound.URL, "50ms"}, expectError: eputils.GetError("errWaitForTimeout"), nodeError: true},
+		{name: "shell", cmd: []string{"shell", "test -n \\{\\{ .Node.IP \\}\\}", "1s"}},
+		{name: "shell timeout", cmd: []string{"shell", "exit 1", "50ms"}, expectError: eputils.GetError("errWaitForTimeout"), nodeError: true},
+		{name: "unknown kind", cmd: []string{"udp", "127.0.0.1:53"}, expectError: eputils.GetError("errExecCmdArgs")},
+		{name: "invalid timeout", cmd: []string{"tcp", "127.0.0.1:53", "soon"}, expectError: eputils.GetError("errExecCmdArgs")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, nodes := newDay0Executor(t)
+			err := e.helperWaitFor(context.TODO(), nodes, tc.cmd)
+			if tc.nodeError {
+				requireNodeError(t, tc.expectError, err)
+			} else {
+				require.Equal(t, tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestParseFacts(t *testing.T) {
+	out := []byte("os=Ubuntu 20.04.4 LTS\nos_id=ubuntu\nos_version=20.04\nkernel=5.15.0-rt\narch=x86_64\n" +
+		"cpus=8\nmemory_kb=16318412\nnics=eno1 docker0 \nrt_kernel=true\nnoise\n")
+	require.Equal(t, &nodeFacts{
+		OS:        "Ubuntu 20.04.4 LTS",
+		OSID:      "ubuntu",
+		OSVersion: "20.04",
+		Kernel:    "5.15.0-rt",
+		Arch:      "x86_64",
+		CPUs:      8,
+		MemoryKB:  16318412,
+		NICs:      []string{"eno1", "docker0"},
+		RTKernel:  true,
+	}, parseFacts(out))
+}
+
+func TestHelperFetchFacts(t *testing.T) {
+	e, nodes := newDay0Executor(t)
+	require.NoError(t, e.helperFetchFacts(context.TODO(), nodes, nil))
+	facts := nodes["127.0.0.1"].facts
+	require.NotNil(t, facts)
+	require.NotEmpty(t, facts.Kernel)
+	require.Greater(t, facts.CPUs, 0)
+
+	kernel, err := e.StringOverrideWithNode(`\{\{ .Facts.Kernel \}\}`, nodes["127.0.0.1"])
+	require.NoError(t, err)
+	require.Equal(t, facts.Kernel, kernel)
+}
@@ -0,0 +1,144 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	log "github.com/sirupsen/logrus"
+)
+
+// containerClient runs the commands of a node with docker exec in a local
+// container, which stands in for the node.
+type containerClient struct {
+	container string
+}
+
+func (c *containerClient) Connect() error {
+	ctn, err := docker.GetContainerByName(c.container)
+	if err != nil {
+		return err
+	}
+	if ctn == nil || ctn.State != "running" {
+		log.Errorf("Container %s of the node is not running", c.container)
+		return eputils.GetError("errContainerNotRunning")
+	}
+	return nil
+}
+
+func (c *containerClient) Disconnect() error {
+	return nil
+}
+
+// CmdWithAttachIO runs cmd with a shell in the container, like the shell of
+// the user on an SSH node.
+func (c *containerClient) CmdWithAttachIO(ctx context.Context, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	cmdStr := strings.Join(cmd, " ")
+	log.Debugf("CmdWithAttachIO: container: %v, cmd: %v", c.container, cmdStr)
+	return docker.ExecContainer(ctx, c.container, []string{"sh", "-c", cmdStr}, stdin, stdout, stderr, tty)
+}
+
+// ContainerNodes are local containers which stand in for the nodes of a kit
+// config, to test executor specs end to end on a single Linux host. The
+// executors attached to them run the commands of these nodes in their
+// containers instead of over SSH.
+type ContainerNodes struct {
+	Nodes      []*pluginapi.Node
+	network    string
+	containers []string
+	// The containers which stand in for the nodes, by node IP.
+	byIP map[string]string
+}
+
+// StartContainerNodes creates the docker network, and starts a privileged
+// container from image on it for each node, e.g.
+// {Name: "node-1", Role: []string{"controlplane", "etcd"}}. The network must
+// not exist yet, and is removed with the containers. The IP of each node is
+// set to the IP of its container, and the user to root if it is not set.
+// The nodes can then be added to the kit config of an attached executor.
+func StartContainerNodes(image, network string, nodes []*pluginapi.Node) (*ContainerNodes, error) {
+	if err := docker.CreateNetwork(network); err != nil {
+		return nil, err
+	}
+	c := &ContainerNodes{network: network, byIP: map[string]string{}}
+	for _, n := range nodes {
+		name := fmt.Sprintf("ec-node-%s", n.Name)
+		if _, err := docker.CreateContainer(
+			image, name, n.Name, network, []string{network}, "",
+			true, false, true, false,
+			[]string{"tail"}, []string{"-f", "/dev/null"}, nil, nil, nil,
+			nil, nil, nil, nil, "no"); err != nil {
+			_ = c.Remove()
+			return nil, err
+		}
+		c.containers = append(c.containers, name)
+		if err := docker.StartContainer("", name, true); err != nil {
+			_ = c.Remove()
+			return nil, err
+		}
+		ctn, err := docker.GetContainerByName(name)
+		if err != nil {
+			_ = c.Remove()
+			return nil, err
+		}
+		if ctn == nil || ctn.NetworkSettings == nil || ctn.NetworkSettings.Networks[network] == nil {
+			log.Errorf("Container %s is not on network %s", name, network)
+			_ = c.Remove()
+			return nil, eputils.GetError("errContainerNotRunning")
+		}
+
+		node := *n
+		node.IP = ctn.NetworkSettings.Networks[network].IPAddress
+		if node.User == "" {
+			node.User = "root"
+		}
+		c.byIP[node.IP] = name
+		c.Nodes = append(c.Nodes, &node)
+		log.Infof("Node %s runs in container %s, IP: %s", node.Name, name, node.IP)
+	}
+	return c, nil
+}
+
+// Attach makes the executor run the commands of the nodes in their
+// containers. It must be called before the nodes are added to the executor,
+// e.g. by SetECParams.
+func (c *ContainerNodes) Attach(e *Executor) {
+	e.newClient = c.client
+}
+
+func (c *ContainerNodes) client(n *pluginapi.Node) client {
+	if name, has := c.byIP[n.IP]; has {
+		return &containerClient{container: name}
+	}
+	return nil
+}
+
+// Remove removes the containers of the nodes and their network.
+func (c *ContainerNodes) Remove() error {
+	var removeErr error
+	for _, name := range c.containers {
+		if err := docker.RemoveContainer(name); err != nil {
+			removeErr = err
+		}
+	}
+	c.containers = nil
+	c.byIP = map[string]string{}
+	if c.network != "" {
+		if err := docker.RemoveNetwork(c.network); err != nil {
+			removeErr = err
+		}
+		c.network = ""
+	}
+	return removeErr
+}
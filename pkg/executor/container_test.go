@@ -0,0 +1,383 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	"github.com/stretchr/testify/require"
+	"github.com/undefinedlabs/go-mpatch"
+)
+
+// fakeDocker stands in for the docker daemon: the containers are only
+// names, and the commands run on the host. With record set, the commands
+// are only recorded: the probes of the guards fail, and the other commands
+// succeed.
+type fakeDocker struct {
+	mu         sync.Mutex
+	networks   map[string]bool
+	containers map[string]string
+	execs      map[string][]string
+	failStart  bool
+	record     bool
+}
+
+func (d *fakeDocker) patch(t *testing.T) []*mpatch.Patch {
+	d.networks = map[string]bool{}
+	d.containers = map[string]string{}
+	d.execs = map[string][]string{}
+	p1, err := mpatch.PatchMethod(docker.CreateContainer, func(
+		imageName, containerName, hostName, networkMode string, networkNames []string,
+		userInContainer string, privileged, needimagepull, runInBackground, readOnlyRootfs bool,
+		entrypoint, args, binds []string, mounts []mount.Mount,
+		volumes map[string]struct{},
+		ports, env, capadd, securityOpt []string, restart string) (string, error) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.containers[containerName] = ""
+		return containerName, nil
+	})
+	require.NoError(t, err)
+	p2, err := mpatch.PatchMethod(docker.StartContainer, func(containerID, containerName string, runInBackground bool) error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.failStart {
+			return eputils.GetError("errAbnormalExit")
+		}
+		d.containers[containerName] = "running"
+		return nil
+	})
+	require.NoError(t, err)
+	p3, err := mpatch.PatchMethod(docker.GetContainerByName, func(containerName string) (*types.Container, error) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		state, has := d.containers[containerName]
+		if !has {
+			return nil, nil
+		}
+		return &types.Container{
+			Names: []string{"/" + containerName},
+			State: state,
+			NetworkSettings: &types.SummaryNetworkSettings{
+				Networks: map[string]*network.EndpointSettings{
+					"ec-test": {IPAddress: "172.30.0." + strings.TrimPrefix(containerName, "ec-node-node-")},
+				},
+			},
+		}, nil
+	})
+	require.NoError(t, err)
+	p4, err := mpatch.PatchMethod(docker.RemoveContainer, func(containerName string) error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.containers, containerName)
+		return nil
+	})
+	require.NoError(t, err)
+	p5, err := mpatch.PatchMethod(docker.ExecContainer, func(ctx context.Context, containerName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+		d.mu.Lock()
+		d.execs[containerName] = append(d.execs[containerName], strings.Join(cmd, " "))
+		d.mu.Unlock()
+		if d.record {
+			if stdin != nil {
+				_, _ = io.Copy(ioutil.Discard, stdin)
+			}
+			if strings.Join(cmd, " ") == "sh -c sh" {
+				return &docker.ExecExitError{ExitCode: 1}
+			}
+			return nil
+		}
+		err := (&day0Client{}).CmdWithAttachIO(ctx, cmd, stdin, stdout, stderr, tty)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &docker.ExecExitError{ExitCode: exitErr.ExitCode()}
+		}
+		return err
+	})
+	require.NoError(t, err)
+	p6, err := mpatch.PatchMethod(docker.CreateNetwork, func(networkName string) error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.networks[networkName] {
+			return eputils.GetError("errAbnormalExit")
+		}
+		d.networks[networkName] = true
+		return nil
+	})
+	require.NoError(t, err)
+	p7, err := mpatch.PatchMethod(docker.RemoveNetwork, func(networkName string) error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.networks, networkName)
+		return nil
+	})
+	require.NoError(t, err)
+	return []*mpatch.Patch{p1, p2, p3, p4, p5, p6, p7}
+}
+
+func TestContainerNodes(t *testing.T) {
+	d := &fakeDocker{}
+	defer unpatchAll(t, d.patch(t))
+
+	cn, err := StartContainerNodes("ubuntu:22.04", "ec-test", []*pluginapi.Node{
+		{Name: "node-1", Role: []string{"controlplane", "etcd"}},
+		{Name: "node-2", Role: []string{"worker"}, User: "ec"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*pluginapi.Node{
+		{Name: "node-1", Role: []string{"controlplane", "etcd"}, IP: "172.30.0.1", User: "root"},
+		{Name: "node-2", Role: []string{"worker"}, IP: "172.30.0.2", User: "ec"},
+	}, cn.Nodes)
+	require.True(t, d.networks["ec-test"])
+
+	e := New()
+	cn.Attach(e)
+	params := &pluginapi.KitconfigParameters{Nodes: cn.Nodes}
+	require.NoError(t, e.NodeListUpdate(params))
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: params}
+	require.Equal(t, &containerClient{container: "ec-node-node-2"}, e.nodesByRole["worker"][0].client)
+
+	e.Spec = &ep.ExecspecSpec{Steps: []*ep.ExecspecSpecStepsItems0{
+		{
+			Name:  "hostname",
+			Nodes: &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"controlplane", "worker"}},
+			Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "shell", Cmd: []string{"echo", `\{\{ .Node.Name \}\}`}, Register: "name"},
+				{Type: "shell", Cmd: []string{"exit", "2"}, Register: "status", IgnoreErrors: true},
+			},
+		},
+	}}
+	var stdout bytes.Buffer
+	require.NoError(t, e.RunWithAttachIO(context.TODO(), nil, &stdout, &stdout))
+	for _, n := range e.allNodes()[1:] {
+		require.Equal(t, n.name, n.registers["name"].Stdout)
+		require.Equal(t, 2, n.registers["status"].ExitCode)
+	}
+	require.Equal(t, []string{"sh -c echo node-2", "sh -c exit 2"}, d.execs["ec-node-node-2"])
+
+	// An executor which is not attached reaches the same nodes over SSH.
+	other := New()
+	require.NoError(t, other.NodeListUpdate(&pluginapi.KitconfigParameters{Nodes: []*pluginapi.Node{
+		{Name: "node-1", IP: "172.30.0.1", User: "root", SSHPasswd: "123456"},
+	}}))
+	require.IsType(t, &sshClient{}, other.nodesByIP["172.30.0.1"].client)
+
+	require.NoError(t, cn.Remove())
+	require.Empty(t, d.containers)
+	require.Empty(t, d.networks)
+	require.Nil(t, cn.client(cn.Nodes[0]))
+	require.Equal(t, eputils.GetError("errContainerNotRunning"), (&containerClient{container: "ec-node-node-1"}).Connect())
+
+	// The network is not shared with other tests.
+	d.networks["ec-test"] = true
+	_, err = StartContainerNodes("ubuntu:22.04", "ec-test", []*pluginapi.Node{{Name: "node-1"}})
+	require.Equal(t, eputils.GetError("errAbnormalExit"), err)
+	require.Empty(t, d.containers)
+	delete(d.networks, "ec-test")
+
+	// The created containers and the network are removed if a node fails
+	// to start.
+	d.failStart = true
+	_, err = StartContainerNodes("ubuntu:22.04", "ec-test", []*pluginapi.Node{{Name: "node-1"}})
+	require.Equal(t, eputils.GetError("errAbnormalExit"), err)
+	require.Empty(t, d.containers)
+	require.Empty(t, d.networks)
+}
+
+// specWorkspace returns a workspace with the day-0 files which the executor
+// specs of the cluster providers copy to the nodes.
+func specWorkspace(t *testing.T) string {
+	ws, err := ioutil.TempDir("", "specs")
+	require.NoError(t, err)
+	for _, f := range []string{
+		"cert/pki/ca.pem",
+		"cert/pki/registry/registry.pem",
+		"runtime/m_kubeconfig",
+		"runtime/capi-byoh/oras_0.13.0_linux_amd64.tar.gz",
+		"runtime/clusterapi/oras/oras/v0.13.0/oras.tar.gz",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(ws, filepath.Dir(f)), 0700))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(ws, f), []byte(f), 0600))
+	}
+	return ws
+}
+
+// runContainerSpec runs an executor spec of configs/executor on the
+// container nodes, like the plugins run it on the nodes of a kit config.
+func runContainerSpec(t *testing.T, cn *ContainerNodes, ws, spec string, value interface{}) *Executor {
+	e := New()
+	cn.Attach(e)
+	require.NoError(t, e.SetECParams(&pluginapi.EpParams{
+		Workspace: ws,
+		Kitconfig: &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{
+			Nodes:          cn.Nodes,
+			GlobalSettings: &pluginapi.KitconfigParametersGlobalSettings{ProviderIP: "10.0.0.1", RegistryPort: "9000"},
+			Customconfig: &pluginapi.Customconfig{Registry: &pluginapi.CustomconfigRegistry{
+				User: "admin", Password: "password",
+			}},
+		}},
+	}))
+	require.NoError(t, e.SetTempValue(value))
+	require.NoError(t, e.LoadSpecFromFile(filepath.Join("..", "..", "configs", "executor", spec)))
+	require.NoError(t, e.RunWithAttachIO(context.TODO(), nil, ioutil.Discard, ioutil.Discard))
+	return e
+}
+
+// The values of the plugins which run the specs.
+var (
+	nodeJoinValue = map[string]interface{}{
+		"Provider":     "byoh",
+		"CRI":          map[string]interface{}{"Name": "containerd"},
+		"RegistryAuth": "YWRtaW46cGFzc3dvcmQ=",
+	}
+	byohValue = map[string]interface{}{
+		"CRI": map[string]interface{}{"Name": "containerd"},
+		"Binaries": []map[string]interface{}{
+			{"Name": "oras", "Version": "v0.13.0", "Revision": "oras.tar.gz"},
+			{"Name": "containerd", "Revision": "containerd.tar.gz"},
+		},
+	}
+)
+
+func TestContainerNodesSpecs(t *testing.T) {
+	d := &fakeDocker{record: true}
+	defer unpatchAll(t, d.patch(t))
+	ws := specWorkspace(t)
+	defer os.RemoveAll(ws)
+
+	cases := []struct {
+		name   string
+		spec   string
+		value  interface{}
+		expect map[string][]string
+	}{
+		{
+			name: "rke preflight",
+			spec: "rke_preflight.yml",
+			expect: map[string][]string{
+				"ec-node-node-1": {"tar -x -C /tmp/", "sh -c sh", "/etc/docker/certs.d/10.0.0.1:9000/ca.crt", "sh -c sh"},
+				"ec-node-node-2": {"tar -x -C /tmp/", "sh -c sh", "/etc/docker/certs.d/10.0.0.1:9000/ca.crt", "sh -c sh"},
+			},
+		},
+		{
+			name:  "node join prepare",
+			spec:  "node-join-prepare.yml",
+			value: nodeJoinValue,
+			expect: map[string][]string{
+				"ec-node-node-2": {"tar -x -C /tmp/", "tar -x -C /tmp/", "tar -x -C /tmp/", "tar -x -C /tmp/",
+					"oras_0.13.0_linux_amd64.tar.gz", "/tmp/kubelet.service", "oras pull 10.0.0.1:9000:/library/capi/kubectl",
+					"swapoff -a", "NO_PROXY=10.0.0.1", "registry.configs.", "modules-load-containerd", "modprobe overlay",
+					"net.ipv4.ip_forward=1", "cri-containerd-cni-1.6.6", "10-kubeadm.conf", "systemctl restart containerd"},
+			},
+		},
+		{
+			name:  "byoh preflight",
+			spec:  "byoh-preflight.yml",
+			value: byohValue,
+			expect: map[string][]string{
+				"ec-node-node-1": {"tar -x -C /tmp/", "tar -x -C /tmp/", "tar -x -C /tmp/", "tar -x -C /tmp/",
+					"tar -xvf /tmp/oras.tar.gz", "/tmp/kubelet.service", "byoh-hostagent-linux-amd64", "modules-load-containerd",
+					"modprobe overlay", "net.ipv4.ip_forward=1", "containerd/containerd.tar.gz", "swapoff -a",
+					"--label type=controlplane"},
+				"ec-node-node-2": {"tar -x -C /tmp/", "tar -x -C /tmp/", "tar -x -C /tmp/", "tar -x -C /tmp/",
+					"tar -xvf /tmp/oras.tar.gz", "/tmp/kubelet.service", "byoh-hostagent-linux-amd64", "modules-load-containerd",
+					"modprobe overlay", "net.ipv4.ip_forward=1", "containerd/containerd.tar.gz", "swapoff -a",
+					"--label type=worker"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cn, err := StartContainerNodes("ubuntu:22.04", "ec-test", []*pluginapi.Node{
+				{Name: "node-1", Role: []string{"controlplane", "etcd"}},
+				{Name: "node-2", Role: []string{"worker"}},
+			})
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, cn.Remove())
+			}()
+			d.execs = map[string][]string{}
+
+			runContainerSpec(t, cn, ws, tc.spec, tc.value)
+			require.Equal(t, len(tc.expect), len(d.execs))
+			for ctn, expect := range tc.expect {
+				execs := d.execs[ctn]
+				require.Equal(t, len(expect), len(execs), "%s: %v", ctn, execs)
+				for k, cmd := range execs {
+					require.True(t, strings.HasPrefix(cmd, "sh -c "), cmd)
+					require.Contains(t, cmd, expect[k])
+				}
+			}
+		})
+	}
+}
+
+// TestContainerNodesDocker runs specs on containers of the image set by
+// EC_TEST_NODE_IMAGE, e.g. in CI on a host with docker. The image needs
+// sudo and the tools which the specs use.
+func TestContainerNodesDocker(t *testing.T) {
+	image := os.Getenv("EC_TEST_NODE_IMAGE")
+	if image == "" {
+		t.Skip("EC_TEST_NODE_IMAGE is not set")
+	}
+	cn, err := StartContainerNodes(image, "ec-test", []*pluginapi.Node{
+		{Name: "node-1", Role: []string{"controlplane", "etcd"}},
+		{Name: "node-2", Role: []string{"worker"}},
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, cn.Remove())
+	}()
+
+	e := New()
+	cn.Attach(e)
+	params := &pluginapi.KitconfigParameters{Nodes: cn.Nodes}
+	require.NoError(t, e.NodeListUpdate(params))
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: params}
+	e.Spec = &ep.ExecspecSpec{Steps: []*ep.ExecspecSpecStepsItems0{
+		{
+			Name:  "hostname",
+			Nodes: &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"controlplane", "worker"}},
+			Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "shell", Cmd: []string{"hostname"}, Register: "hostname"},
+			},
+		},
+	}}
+	require.NoError(t, e.RunWithAttachIO(context.TODO(), nil, nil, nil))
+	for _, n := range e.allNodes()[1:] {
+		require.Equal(t, n.name, n.registers["hostname"].Stdout)
+	}
+
+	// The certificate is only installed by the first run of rke_preflight.
+	ws := specWorkspace(t)
+	defer os.RemoveAll(ws)
+	e = runContainerSpec(t, cn, ws, "rke_preflight.yml", nil)
+	for _, n := range e.allNodes()[1:] {
+		require.Equal(t, 0, n.failed, n.name)
+	}
+	e = runContainerSpec(t, cn, ws, "rke_preflight.yml", nil)
+	for _, n := range e.allNodes()[1:] {
+		require.Equal(t, 2, n.skipped, n.name)
+	}
+}
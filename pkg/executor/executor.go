@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/user"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -31,15 +32,25 @@ type client interface {
 }
 
 type nodeInfo struct {
-	name   string
-	ip     string
-	client client
+	name      string
+	ip        string
+	client    client
+	registers map[string]*commandResult
+	facts     *nodeFacts
+	log       *nodeLog
+	// Commands which ran, were skipped by their guards, or failed on the
+	// node in the current run.
+	changed int
+	skipped int
+	failed  int
 }
 
 type tempParameter struct {
 	pluginapi.EpParams
-	Value interface{}
-	Node  interface{}
+	Value    interface{}
+	Node     interface{}
+	Register map[string]*commandResult
+	Facts    *nodeFacts
 }
 
 type Executor struct {
@@ -47,6 +58,8 @@ type Executor struct {
 	tempParams  tempParameter
 	nodesByIP   map[string]*nodeInfo
 	nodesByRole map[string][]*nodeInfo
+	// newClient returns the client of a node, or nil for an SSH client.
+	newClient func(n *pluginapi.Node) client
 }
 
 func New() *Executor {
@@ -56,25 +69,22 @@ func New() *Executor {
 	}
 }
 
-func (e *Executor) StringOverrideWithNode(s string, ni *nodeInfo) (string, error) {
+// nodeParams returns the template parameters of a node.
+func (e *Executor) nodeParams(ni *nodeInfo) (tempParameter, error) {
 	p := e.tempParams
-	if !strings.Contains(s, `\{\{`) {
-		return s, nil
-	}
-	c := s
-	c = strings.ReplaceAll(c, `\{\{`, `{{`)
-	c = strings.ReplaceAll(c, `\}\}`, `}}`)
-
 	user, err := user.Current()
 	if err != nil {
-		return "", err
+		return p, err
 	}
 	for _, n := range e.tempParams.Kitconfig.Parameters.Nodes {
 		if n.IP == ni.ip {
-			if n.User == "" {
-				n.User = user.Username
+			// The user is defaulted on a copy, since the nodes of the kit
+			// config are shared by the commands running in parallel.
+			node := *n
+			if node.User == "" {
+				node.User = user.Username
 			}
-			p.Node = n
+			p.Node = &node
 			break
 		}
 	}
@@ -84,6 +94,23 @@ func (e *Executor) StringOverrideWithNode(s string, ni *nodeInfo) (string, error
 			IP:   "127.0.0.1",
 		}
 	}
+	p.Register = ni.registers
+	p.Facts = ni.facts
+	return p, nil
+}
+
+func (e *Executor) StringOverrideWithNode(s string, ni *nodeInfo) (string, error) {
+	if !strings.Contains(s, `\{\{`) {
+		return s, nil
+	}
+	c := s
+	c = strings.ReplaceAll(c, `\{\{`, `{{`)
+	c = strings.ReplaceAll(c, `\}\}`, `}}`)
+
+	p, err := e.nodeParams(ni)
+	if err != nil {
+		return "", err
+	}
 	c, err = eputils.StringTemplateConvertWithParams(c, p)
 	if err != nil {
 		log.Warningf("StringOverrideWithNode error, node: %v, s: %v, err: %v", ni.ip, s, err)
@@ -112,6 +139,12 @@ func (e *Executor) NodeListUpdate(params *pluginapi.KitconfigParameters) error {
 		}
 		if _, has := e.nodesByIP[n.IP]; !has {
 			log.Debugf("Add new node [%v], IP: %v\n", n.Name, n.IP)
+			if e.newClient != nil {
+				if c := e.newClient(n); c != nil {
+					e.addNode(n, c)
+					continue
+				}
+			}
 			key := n.SSHKey
 			if key == "" {
 				homeDir, err := os.UserHomeDir()
@@ -135,32 +168,37 @@ func (e *Executor) NodeListUpdate(params *pluginapi.KitconfigParameters) error {
 			if port == 0 {
 				port = 22
 			}
-			node := &nodeInfo{
-				name: n.Name,
-				ip:   n.IP,
-				client: &sshClient{
-					host:     n.IP,
-					port:     port,
-					user:     n.User,
-					password: n.SSHPasswd,
-					key:      key,
-				},
-			}
-			e.nodesByIP[n.IP] = node
-			if len(n.Role) == 0 {
-				e.nodesByRole["unknownRole"] = append(e.nodesByRole["unknownRole"], node)
-			}
-			for _, r := range n.Role {
-				if _, hasR := e.nodesByRole[r]; !hasR {
-					e.nodesByRole[r] = []*nodeInfo{}
-				}
-				e.nodesByRole[r] = append(e.nodesByRole[r], node)
-			}
+			e.addNode(n, &sshClient{
+				host:        n.IP,
+				port:        port,
+				user:        n.User,
+				password:    n.SSHPasswd,
+				key:         key,
+				fingerprint: n.SSHHostKeyFingerprint,
+			})
 		}
 	}
 	return nil
 }
 
+func (e *Executor) addNode(n *pluginapi.Node, c client) {
+	node := &nodeInfo{
+		name:   n.Name,
+		ip:     n.IP,
+		client: c,
+	}
+	e.nodesByIP[n.IP] = node
+	if len(n.Role) == 0 {
+		e.nodesByRole["unknownRole"] = append(e.nodesByRole["unknownRole"], node)
+	}
+	for _, r := range n.Role {
+		if _, hasR := e.nodesByRole[r]; !hasR {
+			e.nodesByRole[r] = []*nodeInfo{}
+		}
+		e.nodesByRole[r] = append(e.nodesByRole[r], node)
+	}
+}
+
 func (e *Executor) SetECParams(epparams *pluginapi.EpParams) error {
 	err := eputils.ConvertSchemaStruct(epparams, &e.tempParams)
 	if err != nil {
@@ -208,96 +246,156 @@ func (e *Executor) LoadSpecFromFile(specFile string) error {
 	return e.LoadSpecFromString(string(specByte))
 }
 
-func (e *Executor) RunWithAttachIO(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
-	for _, step := range e.Spec.Steps {
-		log.Debugf("step: %v\n", step.Name)
-		nodes := map[string]*nodeInfo{}
-		for _, r := range step.Nodes.AnyOf {
-			if nn, has := e.nodesByRole[r]; has {
-				nodes[nn[0].ip] = nn[0]
-				break
+func (e *Executor) selectNodes(step *api.ExecspecSpecStepsItems0) map[string]*nodeInfo {
+	nodes := map[string]*nodeInfo{}
+	for _, r := range step.Nodes.AnyOf {
+		if nn, has := e.nodesByRole[r]; has {
+			nodes[nn[0].ip] = nn[0]
+			break
+		}
+	}
+	for _, r := range step.Nodes.AllOf {
+		if nn, has := e.nodesByRole[r]; has {
+			for _, nnn := range nn {
+				nodes[nnn.ip] = nnn
 			}
 		}
-		for _, r := range step.Nodes.AllOf {
+	}
+	if len(step.Nodes.NoneOf) != 0 {
+		for r := range e.nodesByRole {
+			found := false
+			for _, selector := range step.Nodes.NoneOf {
+				if r == selector {
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
 			if nn, has := e.nodesByRole[r]; has {
 				for _, nnn := range nn {
 					nodes[nnn.ip] = nnn
 				}
 			}
 		}
-		if len(step.Nodes.NoneOf) != 0 {
-			for r := range e.nodesByRole {
-				found := false
-				for _, selector := range step.Nodes.NoneOf {
-					if r == selector {
-						found = true
-						break
-					}
-				}
-				if found {
-					continue
-				}
-				if nn, has := e.nodesByRole[r]; has {
-					for _, nnn := range nn {
-						nodes[nnn.ip] = nnn
-					}
-				}
-			}
-		}
-		for _, command := range step.Commands {
-			err := error(nil)
-			cnodes := map[string]*nodeInfo{}
+	}
+	return nodes
+}
 
-			for k, n := range nodes {
-				whenString, err := e.StringOverrideWithNode(command.When, n)
-				if err != nil {
-					log.Warningf("Ignore override error, %v, err: %v\n", command.When, err)
-					return eputils.GetError("errIgnoreOverride")
-				}
-				if whenString == "" {
-					whenString = "true"
-				}
-				when, err := strconv.ParseBool(whenString)
-				if err != nil {
-					log.Warningf("Ignore format error, %v, err: %v\n", command.When, err)
-					return eputils.GetError("errIgnoreFormat")
-				}
-				if when {
-					cnodes[k] = n
-				}
-			}
+func (e *Executor) commandNodes(command *api.ExecspecSpecStepsItems0CommandsItems0, nodes []*nodeInfo, failed map[string]error) (map[string]*nodeInfo, error) {
+	cnodes := map[string]*nodeInfo{}
+	for _, n := range nodes {
+		if _, has := failed[n.ip]; has {
+			continue
+		}
+		whenString, err := e.StringOverrideWithNode(command.When, n)
+		if err != nil {
+			log.Warningf("Ignore override error, %v, err: %v\n", command.When, err)
+			return nil, eputils.GetError("errIgnoreOverride")
+		}
+		if whenString == "" {
+			whenString = "true"
+		}
+		when, err := strconv.ParseBool(whenString)
+		if err != nil {
+			log.Warningf("Ignore format error, %v, err: %v\n", command.When, err)
+			return nil, eputils.GetError("errIgnoreFormat")
+		}
+		if when {
+			cnodes[n.ip] = n
+		}
+	}
+	return cnodes, nil
+}
 
-			if len(cnodes) == 0 {
-				log.Debugf("nodes are empty, ignore cmd [%v]", command)
-				continue
-			}
+func (e *Executor) runCommand(ctx context.Context, command *api.ExecspecSpecStepsItems0CommandsItems0, cnodes map[string]*nodeInfo) error {
+	if command.Register != "" && command.Type != "shell" {
+		log.Warningf("Ignore register %v, only shell commands are registered", command.Register)
+	}
+	if command.IgnoreErrors && command.Type != "shell" {
+		log.Warningf("Ignore ignore_errors, only errors of shell commands are ignored")
+	}
+	if command.Type == "shell" && (command.Register != "" || command.IgnoreErrors) {
+		return e.helperShellRegister(ctx, cnodes, command.Cmd, command.Register, command.IgnoreErrors)
+	} else if command.Type == "shell" {
+		return e.helperShell(ctx, cnodes, command.Cmd)
+	} else if command.Type == "copyFromDay0" {
+		return e.helperCopyFromDay0(ctx, cnodes, command.Cmd)
+	} else if command.Type == "copyToDay0" {
+		return e.helperCopyToDay0(ctx, cnodes, command.Cmd)
+	} else if command.Type == "pushImage" {
+		return e.helperPushImage(ctx, cnodes, command.Cmd)
+	} else if command.Type == "pushFile" {
+		return e.helperPushFile(ctx, cnodes, command.Cmd)
+	} else if command.Type == "pullFile" {
+		return e.helperPullFile(ctx, cnodes, command.Cmd)
+	} else if command.Type == "createHarborProject" {
+		return e.helperCreateProjectOnHarbor(ctx, cnodes, command.Cmd)
+	} else if command.Type == "template" {
+		return e.helperTemplate(ctx, cnodes, command.Cmd)
+	} else if command.Type == "waitFor" {
+		return e.helperWaitFor(ctx, cnodes, command.Cmd)
+	} else if command.Type == "fetchFacts" {
+		return e.helperFetchFacts(ctx, cnodes, command.Cmd)
+	}
+	log.Errorf("Unknown command type: %v\n", command.Type)
+	return eputils.GetError("errUnknownCmdType")
+}
 
-			if command.Type == "shell" {
-				err = e.helperShell(ctx, cnodes, command.Cmd)
-			} else if command.Type == "copyFromDay0" {
-				err = e.helperCopyFromDay0(ctx, cnodes, command.Cmd)
-			} else if command.Type == "copyToDay0" {
-				err = e.helperCopyToDay0(ctx, cnodes, command.Cmd)
-			} else if command.Type == "pushImage" {
-				err = e.helperPushImage(ctx, cnodes, command.Cmd)
-			} else if command.Type == "pushFile" {
-				err = e.helperPushFile(ctx, cnodes, command.Cmd)
-			} else if command.Type == "pullFile" {
-				err = e.helperPullFile(ctx, cnodes, command.Cmd)
-			} else if command.Type == "createHarborProject" {
-				err = e.helperCreateProjectOnHarbor(ctx, cnodes, command.Cmd)
-			} else {
-				log.Errorf("Unknown command type: %v\n", command.Type)
-				err = eputils.GetError("errUnknownCmdType")
-			}
-			if err != nil {
-				return err
-			}
+func (e *Executor) RunWithAttachIO(ctx context.Context, stdin io.Reader, stdout, stderr io.Writer) error {
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+	for _, n := range e.allNodes() {
+		n.changed, n.skipped, n.failed = 0, 0, 0
+	}
+	dir := e.openNodeLogs(stdout, stderr)
+	defer func() {
+		e.closeNodeLogs()
+		e.reportNodes(stdout, dir)
+	}()
+	for _, step := range e.Spec.Steps {
+		log.Debugf("step: %v\n", step.Name)
+		if err := e.runStep(ctx, step, e.selectNodes(step)); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// allNodes returns the nodes of all the roles, sorted by IP.
+func (e *Executor) allNodes() []*nodeInfo {
+	nodes := map[string]*nodeInfo{}
+	for _, nn := range e.nodesByRole {
+		for _, n := range nn {
+			nodes[n.ip] = n
+		}
+	}
+	sorted := make([]*nodeInfo, 0, len(nodes))
+	for _, n := range nodes {
+		sorted = append(sorted, n)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ip < sorted[j].ip })
+	return sorted
+}
+
+// Close disconnects from the nodes. The connections to the nodes are kept
+// by the executor between the commands and the runs until it is closed.
+func (e *Executor) Close() error {
+	var closeErr error
+	for _, n := range e.allNodes() {
+		if err := n.client.Disconnect(); err != nil {
+			log.Warningf("Failed to disconnect from node %v: %v", n.ip, err)
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
 func (e *Executor) Run(ctx context.Context) error {
 	if ctx == nil {
 		ctx = context.Background()
@@ -132,6 +132,28 @@ func TestStringOverrideWithNode(t *testing.T) {
 	}
 }
 
+func TestNodeParams(t *testing.T) {
+	node := &pluginapi.Node{IP: "192.168.1.1"}
+	e := New()
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{Nodes: []*pluginapi.Node{node}}}
+	current, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := e.nodeParams(&nodeInfo{ip: "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(p.Node, &pluginapi.Node{IP: "192.168.1.1", User: current.Username}) {
+		t.Errorf("Unexpected node: %v", p.Node)
+	}
+	// The node of the kit config is not changed.
+	if node.User != "" {
+		t.Errorf("Node of the kit config is changed: %v", node)
+	}
+}
+
 func TestNew(t *testing.T) {
 	cases := []struct {
 		name           string
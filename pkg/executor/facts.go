@@ -0,0 +1,100 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// nodeFacts are the facts of a node collected by a fetchFacts command. The
+// templates of the later commands refer to them as .Facts, e.g.
+// .Facts.Kernel.
+type nodeFacts struct {
+	// PRETTY_NAME, ID and VERSION_ID of /etc/os-release.
+	OS        string
+	OSID      string
+	OSVersion string
+	Kernel    string
+	Arch      string
+	CPUs      int
+	MemoryKB  int64
+	// Network interfaces other than the loopback.
+	NICs []string
+	// RTKernel is true if the kernel is a real-time (PREEMPT_RT) kernel.
+	RTKernel bool
+}
+
+const factsScript = `. /etc/os-release 2>/dev/null
+echo "os=$PRETTY_NAME"
+echo "os_id=$ID"
+echo "os_version=$VERSION_ID"
+echo "kernel=$(uname -r)"
+echo "arch=$(uname -m)"
+echo "cpus=$(nproc)"
+echo "memory_kb=$(awk '/^MemTotal:/ {print $2}' /proc/meminfo)"
+echo "nics=$(ls /sys/class/net | grep -v '^lo$' | tr '\n' ' ')"
+if [ "$(cat /sys/kernel/realtime 2>/dev/null)" = "1" ] || uname -v | grep -q PREEMPT_RT; then
+  echo "rt_kernel=true"
+else
+  echo "rt_kernel=false"
+fi
+`
+
+func parseFacts(out []byte) *nodeFacts {
+	facts := &nodeFacts{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		v := strings.TrimSpace(kv[1])
+		switch kv[0] {
+		case "os":
+			facts.OS = v
+		case "os_id":
+			facts.OSID = v
+		case "os_version":
+			facts.OSVersion = v
+		case "kernel":
+			facts.Kernel = v
+		case "arch":
+			facts.Arch = v
+		case "cpus":
+			facts.CPUs, _ = strconv.Atoi(v)
+		case "memory_kb":
+			facts.MemoryKB, _ = strconv.ParseInt(v, 10, 64)
+		case "nics":
+			facts.NICs = strings.Fields(v)
+		case "rt_kernel":
+			facts.RTKernel = v == "true"
+		}
+	}
+	return facts
+}
+
+// helperFetchFacts collects the OS, kernel, CPU, memory, network interfaces
+// and real-time kernel status of the nodes into their template parameters.
+func (e *Executor) helperFetchFacts(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
+	return forEachNode(nodes, func(n *nodeInfo) error {
+		var out bytes.Buffer
+		if err := runOnNode(ctx, n, []string{"sh"}, strings.NewReader(factsScript), &out); err != nil {
+			return err
+		}
+		n.facts = parseFacts(out.Bytes())
+		log.Infof("Node %v: %v, kernel %v, %d CPUs, %d kB memory, RT kernel %v",
+			n.ip, n.facts.OS, n.facts.Kernel, n.facts.CPUs, n.facts.MemoryKB, n.facts.RTKernel)
+		return nil
+	})
+}
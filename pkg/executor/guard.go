@@ -0,0 +1,107 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	api "github.com/intel/edge-conductor/pkg/api/ep"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// guard is a probe script run on a node before a command. The command is
+// skipped on the node if the probe succeeds, or if it fails when
+// skipOnFailure is set. Like the targets of waitFor, the script is passed
+// to sh on stdin, since the SSH and container clients join the arguments of
+// a command with spaces.
+type guard struct {
+	name          string
+	script        string
+	skipOnFailure bool
+}
+
+func (e *Executor) nodeGuards(command *api.ExecspecSpecStepsItems0CommandsItems0, n *nodeInfo) ([]guard, error) {
+	guards := []guard{}
+	if command.Creates != "" {
+		path, err := e.StringOverrideWithNode(command.Creates, n)
+		if err != nil {
+			return nil, err
+		}
+		guards = append(guards, guard{name: "creates", script: "test -e " + shellQuote(path)})
+	}
+	if command.Unless != "" {
+		cmd, err := e.StringOverrideWithNode(command.Unless, n)
+		if err != nil {
+			return nil, err
+		}
+		guards = append(guards, guard{name: "unless", script: cmd})
+	}
+	if command.OnlyIf != "" {
+		cmd, err := e.StringOverrideWithNode(command.OnlyIf, n)
+		if err != nil {
+			return nil, err
+		}
+		guards = append(guards, guard{name: "onlyIf", script: cmd, skipOnFailure: true})
+	}
+	return guards, nil
+}
+
+// checkGuards runs the creates, unless and onlyIf probes of a command on the
+// nodes, and returns the nodes on which the command is to be skipped. A
+// probe which cannot run fails the node.
+func (e *Executor) checkGuards(ctx context.Context, command *api.ExecspecSpecStepsItems0CommandsItems0, nodes map[string]*nodeInfo) (map[string]bool, error) {
+	if command.Creates == "" && command.Unless == "" && command.OnlyIf == "" {
+		return nil, nil
+	}
+	nodeGuards := map[string][]guard{}
+	for ip, n := range nodes {
+		guards, err := e.nodeGuards(command, n)
+		if err != nil {
+			return nil, err
+		}
+		nodeGuards[ip] = guards
+	}
+
+	skip := map[string]bool{}
+	mu := sync.Mutex{}
+	errs := newNodeErrors()
+	wg := sync.WaitGroup{}
+	wg.Add(len(nodes))
+	for ip, n := range nodes {
+		n := n
+		guards := nodeGuards[ip]
+		go func() {
+			defer wg.Done()
+			if err := n.client.Connect(); err != nil {
+				errs.set(n, err)
+				return
+			}
+			for _, g := range guards {
+				err := n.client.CmdWithAttachIO(ctx, []string{"sh"}, strings.NewReader(g.script), nil, nil, false)
+				if err != nil {
+					if _, exited := exitCode(err); !exited {
+						errs.set(n, err)
+						return
+					}
+				}
+				if (err == nil) != g.skipOnFailure {
+					log.Infof("Skip %v on node %v by %v", command.Cmd, n.ip, g.name)
+					mu.Lock()
+					skip[n.ip] = true
+					mu.Unlock()
+					break
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return skip, errs.err()
+}
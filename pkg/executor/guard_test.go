@@ -0,0 +1,204 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	sshd "github.com/gliderlabs/ssh"
+	"github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuards(t *testing.T) {
+	dir, err := ioutil.TempDir("", "guard")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exists := filepath.Join(dir, "exists")
+	require.NoError(t, ioutil.WriteFile(exists, nil, 0600))
+
+	touch := func(name string) []string {
+		return []string{"touch", filepath.Join(dir, name)}
+	}
+	cases := []struct {
+		name    string
+		command ep.ExecspecSpecStepsItems0CommandsItems0
+		run     bool
+	}{
+		{
+			name:    "no guard",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{},
+			run:     true,
+		},
+		{
+			name:    "creates existing",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Creates: exists},
+		},
+		{
+			name:    "creates missing",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Creates: filepath.Join(dir, "missing")},
+			run:     true,
+		},
+		{
+			name:    "unless succeeds",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Unless: "test -e " + exists},
+		},
+		{
+			name:    "unless fails",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Unless: "false"},
+			run:     true,
+		},
+		{
+			name:    "onlyIf succeeds",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{OnlyIf: "true"},
+			run:     true,
+		},
+		{
+			name:    "onlyIf fails",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{OnlyIf: "exit 1"},
+		},
+		{
+			name:    "creates before onlyIf",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Creates: exists, OnlyIf: "true"},
+		},
+	}
+
+	e := New()
+	require.NoError(t, e.NodeListUpdate(&pluginapi.KitconfigParameters{}))
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{}}
+	step := &ep.ExecspecSpecStepsItems0{
+		Name:  "guards",
+		Nodes: &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"day-0"}},
+	}
+	for k := range cases {
+		command := cases[k].command
+		command.Type = "shell"
+		command.Cmd = touch(cases[k].name)
+		step.Commands = append(step.Commands, &command)
+	}
+	e.Spec = &ep.ExecspecSpec{Steps: []*ep.ExecspecSpecStepsItems0{step}}
+
+	require.NoError(t, e.Run(context.TODO()))
+	changed := 0
+	for _, tc := range cases {
+		_, err := os.Stat(filepath.Join(dir, tc.name))
+		require.Equal(t, tc.run, err == nil, tc.name)
+		if tc.run {
+			changed++
+		}
+	}
+	day0 := e.nodesByRole["day-0"][0]
+	require.Equal(t, changed, day0.changed)
+	require.Equal(t, len(cases)-changed, day0.skipped)
+	require.Equal(t, 0, day0.failed)
+}
+
+// shellServer stands in for the sshd of a node: like the login shell of
+// the user, it runs the command line with sh.
+func shellServer(t *testing.T) (*sshd.Server, int) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &sshd.Server{
+		Handler: func(s sshd.Session) {
+			cmd := exec.Command("sh", "-c", s.RawCommand())
+			cmd.Stdin = s
+			cmd.Stdout = s
+			cmd.Stderr = s.Stderr()
+			err := cmd.Run()
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				_ = s.Exit(exitErr.ExitCode())
+				return
+			}
+			if err != nil {
+				_ = s.Exit(255)
+				return
+			}
+			_ = s.Exit(0)
+		},
+	}
+	go func() {
+		_ = server.Serve(l)
+	}()
+	return server, l.Addr().(*net.TCPAddr).Port
+}
+
+func TestGuardsClients(t *testing.T) {
+	dir, err := ioutil.TempDir("", "guard dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	knownHosts := eputils.KnownHostsFile
+	eputils.KnownHostsFile = filepath.Join(dir, "known_hosts")
+	defer func() { eputils.KnownHostsFile = knownHosts }()
+	exists := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(exists, []byte("ca"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ca.crt"), []byte("ca"), 0600))
+
+	server, port := shellServer(t)
+	defer server.Close()
+	d := &fakeDocker{}
+	defer unpatchAll(t, d.patch(t))
+	d.containers["ec-node-node-1"] = "running"
+
+	clients := map[string]client{
+		"ssh":       &sshClient{host: "127.0.0.1", port: port, user: "ec", password: "123456"},
+		"container": &containerClient{container: "ec-node-node-1"},
+	}
+	cases := []struct {
+		name    string
+		command ep.ExecspecSpecStepsItems0CommandsItems0
+		skip    bool
+	}{
+		{
+			name:    "creates path with space",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Creates: exists},
+			skip:    true,
+		},
+		{
+			name:    "unless with arguments succeeds",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Unless: "cmp -s '" + exists + "' '" + filepath.Join(dir, "ca.crt") + "'"},
+			skip:    true,
+		},
+		{
+			name:    "unless with arguments fails",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{Unless: "cmp -s '" + exists + "' /dev/null"},
+		},
+		{
+			name:    "onlyIf with pipe fails",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{OnlyIf: "grep -q swap '" + exists + "' || echo | grep -q ."},
+			skip:    true,
+		},
+		{
+			name:    "onlyIf with pipe succeeds",
+			command: ep.ExecspecSpecStepsItems0CommandsItems0{OnlyIf: "grep -q swap '" + exists + "' || echo ca | grep -q ."},
+		},
+	}
+
+	e := New()
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{}}
+	for name, client := range clients {
+		n := &nodeInfo{name: "node-1", ip: "127.0.0.1", client: client}
+		for _, tc := range cases {
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				skip, err := e.checkGuards(context.TODO(), &tc.command, map[string]*nodeInfo{n.ip: n})
+				require.NoError(t, err)
+				require.Equal(t, tc.skip, skip[n.ip])
+			})
+		}
+		require.NoError(t, client.Disconnect())
+	}
+}
@@ -10,13 +10,13 @@ package executor
 import (
 	"context"
 	"fmt"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	"github.com/intel/edge-conductor/pkg/eputils"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
 	"github.com/intel/edge-conductor/pkg/eputils/orasutils"
+	registryutils "github.com/intel/edge-conductor/pkg/eputils/registryutils"
 	repoutils "github.com/intel/edge-conductor/pkg/eputils/repoutils"
-	restfulcli "github.com/intel/edge-conductor/pkg/eputils/restfulcli"
 	"io"
-	"os"
 	"path"
 	"strings"
 	"sync"
@@ -43,7 +43,7 @@ func (e *Executor) CmdOverrideWithNode(cmd []string, ni *nodeInfo) ([]string, er
 }
 
 func (e *Executor) runPipeFrom(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, from *nodeInfo, fromCmd []string) error {
-	finalErr := error(nil)
+	errs := newNodeErrors()
 	wg := sync.WaitGroup{}
 	wg.Add(len(nodes))
 	for _, n := range nodes {
@@ -55,7 +55,7 @@ func (e *Executor) runPipeFrom(ctx context.Context, nodes map[string]*nodeInfo,
 		go func() {
 			defer wg.Done()
 			if err := n.client.Connect(); err != nil {
-				finalErr = err
+				errs.set(n, err)
 				return
 			}
 			r, w := io.Pipe()
@@ -63,30 +63,25 @@ func (e *Executor) runPipeFrom(ctx context.Context, nodes map[string]*nodeInfo,
 			go func() {
 				defer wg.Done()
 				if err := from.client.CmdWithAttachIO(ctx, fromCmd,
-					nil, w, os.Stderr, false); err != nil {
-					finalErr = err
+					nil, w, from.stderr(), false); err != nil {
+					errs.set(n, err)
 				}
 				if err := w.Close(); err != nil {
-					finalErr = err
+					errs.set(n, err)
 				}
 			}()
 			if err := n.client.CmdWithAttachIO(ctx, cmd,
-				r, os.Stdout, os.Stderr, false); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.Disconnect(); err != nil {
-				finalErr = err
-				return
+				r, n.stdout(), n.stderr(), false); err != nil {
+				errs.set(n, err)
 			}
 		}()
 	}
 	wg.Wait()
-	return finalErr
+	return errs.err()
 }
 
 func (e *Executor) runPipeTo(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, to *nodeInfo, toCmd []string) error {
-	finalErr := error(nil)
+	errs := newNodeErrors()
 	wg := sync.WaitGroup{}
 	wg.Add(len(nodes))
 	for _, n := range nodes {
@@ -98,7 +93,7 @@ func (e *Executor) runPipeTo(ctx context.Context, nodes map[string]*nodeInfo, cm
 		go func() {
 			defer wg.Done()
 			if err := n.client.Connect(); err != nil {
-				finalErr = err
+				errs.set(n, err)
 				return
 			}
 			r, w := io.Pipe()
@@ -111,28 +106,23 @@ func (e *Executor) runPipeTo(ctx context.Context, nodes map[string]*nodeInfo, cm
 			go func() {
 				defer wg.Done()
 				if err := to.client.CmdWithAttachIO(ctx, toCmd,
-					r, os.Stdout, os.Stderr, false); err != nil {
-					finalErr = err
+					r, to.stdout(), to.stderr(), false); err != nil {
+					errs.set(n, err)
 				}
 			}()
 			if err := n.client.CmdWithAttachIO(ctx, cmd,
-				nil, w, os.Stderr, false); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.Disconnect(); err != nil {
-				finalErr = err
-				return
+				nil, w, n.stderr(), false); err != nil {
+				errs.set(n, err)
 			}
 		}()
 	}
 	wg.Wait()
-	return finalErr
+	return errs.err()
 }
 
 func (e *Executor) helperShell(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
 	log.Debugf("cmd: %v", strings.Join(cmd, "@"))
-	finalErr := error(nil)
+	errs := newNodeErrors()
 	wg := sync.WaitGroup{}
 	wg.Add(len(nodes))
 	for _, n := range nodes {
@@ -144,21 +134,16 @@ func (e *Executor) helperShell(ctx context.Context, nodes map[string]*nodeInfo,
 		go func() {
 			defer wg.Done()
 			if err := n.client.Connect(); err != nil {
-				finalErr = err
-				return
-			}
-			if err := n.client.CmdWithAttachIO(ctx, cmd, nil, os.Stdout, os.Stderr, true); err != nil {
-				finalErr = err
+				errs.set(n, err)
 				return
 			}
-			if err := n.client.Disconnect(); err != nil {
-				finalErr = err
-				return
+			if err := n.client.CmdWithAttachIO(ctx, cmd, nil, n.stdout(), n.stderr(), true); err != nil {
+				errs.set(n, err)
 			}
 		}()
 	}
 	wg.Wait()
-	return finalErr
+	return errs.err()
 }
 
 func (e *Executor) helperCopyFromDay0(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
@@ -203,25 +188,66 @@ func (e *Executor) helperPushImage(ctx context.Context, nodes map[string]*nodeIn
 	if err != nil {
 		return err
 	}
-	input_ep_params := e.tempParams
-	auth, err := docker.GetAuthConf(input_ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP,
-		input_ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.User,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.Password)
+	reg, err := registryutils.NewRegistry(&e.tempParams.EpParams)
 	if err != nil {
 		return err
 	}
-	var newImages []string
-	if newImages, err = restfulcli.MapImageURLCreateHarborProject(input_ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP,
-		input_ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.User,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.Password, cmd); err != nil {
-		if err != nil {
-			return err
+	// Verified images are pushed by the digests they were verified with.
+	var digests []string
+	if conf := e.tempParams.EpParams.Kitconfig.Parameters.Customconfig.ImageVerification; conf != nil {
+		for _, image := range cmd {
+			log.Infof("Verify image %s", image)
+			result, err := docker.ImageVerify(image, conf)
+			recordVerification(d0, image, result, err)
+			if err != nil {
+				return err
+			}
+			digests = append(digests, result.Digest)
+		}
+	}
+	auth := reg.AuthConf()
+	newImages, err := reg.MapImages(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Without the docker daemon, the images are copied from their
+	// registries to the day-0 registry directly.
+	if !docker.DaemonAvailable() {
+		if len(newImages) != len(cmd) {
+			return eputils.GetError("errHarborResponse")
+		}
+		for i, url := range newImages {
+			src := cmd[i]
+			if digests != nil {
+				if src, err = docker.ImagePinned(cmd[i], digests[i]); err != nil {
+					return err
+				}
+			}
+			newTag := docker.GetImageNewTag(url, auth.ServerAddress)
+			log.Infof("Copy %s to %s", src, newTag)
+			if _, err := docker.ImageCopy(src, newTag, auth, reg.CACert()); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
 
-	for _, url := range newImages {
+	if digests != nil && len(newImages) != len(cmd) {
+		return eputils.GetError("errHarborResponse")
+	}
+	for i, url := range newImages {
+		// The local image must be the content verified on its registry.
+		if digests != nil {
+			localDigest, err := docker.ImageDigest(cmd[i])
+			if err != nil {
+				return err
+			}
+			if localDigest != digests[i] {
+				log.Errorf("Local image %s (%s) is not the image verified (%s)", cmd[i], localDigest, digests[i])
+				return eputils.GetError("errImageNotVerified")
+			}
+		}
 		newTag, err := docker.TagImageToLocal(url, auth.ServerAddress)
 		if err != nil {
 			return err
@@ -235,19 +261,30 @@ func (e *Executor) helperPushImage(ctx context.Context, nodes map[string]*nodeIn
 	return nil
 }
 
+// recordVerification writes the verification result of an image to the log
+// of the day-0 node.
+func recordVerification(d0 *nodeInfo, image string, result *pluginapi.ImagesItems0Verification, err error) {
+	if result == nil {
+		result = &pluginapi.ImagesItems0Verification{Result: pluginapi.ImagesItems0VerificationResultFailed}
+	}
+	if err != nil {
+		log.Errorf("Image %s is not verified: %v", image, err)
+		d0.log.printf("=== verify %s: %s, error: %v", image, result.Result, err)
+		return
+	}
+	log.Infof("Image %s is %s, digest: %s", image, result.Result, result.Digest)
+	d0.log.printf("=== verify %s: %s, digest: %s, signature: %s, key: %s", image, result.Result, result.Digest, result.Signature, result.Key)
+}
+
 func (e *Executor) helperCreateProjectOnHarbor(ctx context.Context, nodes map[string]*nodeInfo, image []string) error {
 	log.Debugf("image list: %v", image)
 	log.Debugf("ctx: %v", ctx)
 	log.Debugf("nodes: %v", nodes)
-	input_ep_params := e.tempParams
-	if input_ep_params.Kitconfig == nil || input_ep_params.Kitconfig.Parameters == nil || input_ep_params.Kitconfig.Parameters.GlobalSettings == nil || input_ep_params.Kitconfig.Parameters.Customconfig == nil {
-		err := eputils.GetError("errKitCfgParameter")
+	reg, err := registryutils.NewRegistry(&e.tempParams.EpParams)
+	if err != nil {
 		return err
 	}
-	if _, err := restfulcli.MapImageURLCreateHarborProject(input_ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP,
-		input_ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.User,
-		input_ep_params.Kitconfig.Parameters.Customconfig.Registry.Password, image); err != nil {
+	if _, err := reg.MapImages(image); err != nil {
 		return err
 	}
 
@@ -312,9 +349,7 @@ func (e *Executor) helperPullFile(ctx context.Context, nodes map[string]*nodeInf
 	if err != nil {
 		return err
 	}
-	input_ep_params := e.tempParams
-	ip := input_ep_params.Kitconfig.Parameters.GlobalSettings.ProviderIP
-	port := input_ep_params.Kitconfig.Parameters.GlobalSettings.RegistryPort
+	registry := eputils.RegistryAddress(e.tempParams.Kitconfig)
 
 	targetFile := cmd[0]
 	subRef := cmd[1]
@@ -324,7 +359,7 @@ func (e *Executor) helperPullFile(ctx context.Context, nodes map[string]*nodeInf
 		rev = "0.0.0"
 	}
 
-	targetUrl := fmt.Sprintf("oci://%s:%s/%s/%s:%s", ip, port, orasutils.RegProject, subRef, rev)
+	targetUrl := fmt.Sprintf("oci://%s/%s/%s:%s", registry, orasutils.RegProject, subRef, rev)
 	log.Debugf("helperPullFile targetUrl: %s\n", targetUrl)
 
 	err = repoutils.PullFileFromRepo(targetFile, targetUrl)
@@ -58,6 +58,20 @@ var helper_executor = &Executor{
 	},
 }
 
+const testImageDigest = "sha256:2d05f2e5ba9e3a0c7bc9ea6e0e4e3de4d8b1c8e6e2b1d9d0e3b2c7a1f0e9d8c7"
+
+// helper_verify_executor is helper_executor with the image verification
+// config.
+var helper_verify_executor = func() *Executor {
+	e := *helper_executor
+	parameters := *e.tempParams.EpParams.Kitconfig.Parameters
+	customconfig := *parameters.Customconfig
+	customconfig.ImageVerification = &pluginapi.CustomconfigImageVerification{Signature: "cosign", Keys: []string{"cosign.pub"}}
+	parameters.Customconfig = &customconfig
+	e.tempParams.EpParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &parameters}
+	return &e
+}()
+
 var day0_nodes = map[string]*nodeInfo{
 	"day-0": {
 		name: "",
@@ -165,6 +179,141 @@ func TestHelperPushImage(t *testing.T) {
 				patch5 := patchTagImageToLocal(t, false)
 				patch6 := patchImagePush(t, false)
 				patch7 := patchMapImageURLCreateHarborProject(t, false)
+				patch8 := patchDaemonAvailable(t, true)
+				return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5, patch6, patch7, patch8}
+			},
+		},
+		{
+			"daemonless copy",
+			helper_executor,
+			context.TODO(),
+			map[string]*nodeInfo{
+				"192.168.1.1": {
+					ip: "192.168.1.1",
+				},
+			},
+			[]string{"docker.com"},
+			&nodeInfo{},
+			[]string{"bash"},
+			false,
+			func() []*mpatch.Patch {
+				patch1 := patchGetAuthConf(t, false)
+				patch2 := patchMapImageURLCreateHarborProject(t, false)
+				patch3 := patchDaemonAvailable(t, false)
+				patch4 := patchImageCopy(t, "docker.com", false)
+				return []*mpatch.Patch{patch1, patch2, patch3, patch4}
+			},
+		},
+		{
+			"daemonless copy fail",
+			helper_executor,
+			context.TODO(),
+			map[string]*nodeInfo{
+				"192.168.1.1": {
+					ip: "192.168.1.1",
+				},
+			},
+			[]string{"docker.com"},
+			&nodeInfo{},
+			[]string{"bash"},
+			true,
+			func() []*mpatch.Patch {
+				patch1 := patchGetAuthConf(t, false)
+				patch2 := patchMapImageURLCreateHarborProject(t, false)
+				patch3 := patchDaemonAvailable(t, false)
+				patch4 := patchImageCopy(t, "docker.com", true)
+				return []*mpatch.Patch{patch1, patch2, patch3, patch4}
+			},
+		},
+		{
+			"verified",
+			helper_verify_executor,
+			context.TODO(),
+			map[string]*nodeInfo{
+				"192.168.1.1": {
+					ip: "192.168.1.1",
+				},
+			},
+			[]string{"docker.com"},
+			&nodeInfo{},
+			[]string{"bash"},
+			false,
+			func() []*mpatch.Patch {
+				patch1 := patchGetAuthConf(t, false)
+				patch2 := patchMapImageURLCreateHarborProject(t, false)
+				patch3 := patchDaemonAvailable(t, false)
+				patch4 := patchImageCopy(t, "docker.io/library/docker.com@"+testImageDigest, false)
+				patch5 := patchImageVerify(t, false)
+				return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5}
+			},
+		},
+		{
+			"verification fail",
+			helper_verify_executor,
+			context.TODO(),
+			map[string]*nodeInfo{
+				"192.168.1.1": {
+					ip: "192.168.1.1",
+				},
+			},
+			[]string{"docker.com"},
+			&nodeInfo{},
+			[]string{"bash"},
+			true,
+			func() []*mpatch.Patch {
+				patch1 := patchGetAuthConf(t, false)
+				patch2 := patchMapImageURLCreateHarborProject(t, false)
+				patch3 := patchDaemonAvailable(t, false)
+				patch4 := patchImageCopy(t, "docker.io/library/docker.com@"+testImageDigest, false)
+				patch5 := patchImageVerify(t, true)
+				return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5}
+			},
+		},
+		{
+			"verified local image",
+			helper_verify_executor,
+			context.TODO(),
+			map[string]*nodeInfo{
+				"192.168.1.1": {
+					ip: "192.168.1.1",
+				},
+			},
+			[]string{"docker.com"},
+			&nodeInfo{},
+			[]string{"bash"},
+			false,
+			func() []*mpatch.Patch {
+				patch1 := patchGetAuthConf(t, false)
+				patch2 := patchMapImageURLCreateHarborProject(t, false)
+				patch3 := patchDaemonAvailable(t, true)
+				patch4 := patchImageVerify(t, false)
+				patch5 := patchImageDigest(t, testImageDigest)
+				patch6 := patchTagImageToLocal(t, false)
+				patch7 := patchImagePush(t, false)
+				return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5, patch6, patch7}
+			},
+		},
+		{
+			"local image not verified",
+			helper_verify_executor,
+			context.TODO(),
+			map[string]*nodeInfo{
+				"192.168.1.1": {
+					ip: "192.168.1.1",
+				},
+			},
+			[]string{"docker.com"},
+			&nodeInfo{},
+			[]string{"bash"},
+			true,
+			func() []*mpatch.Patch {
+				patch1 := patchGetAuthConf(t, false)
+				patch2 := patchMapImageURLCreateHarborProject(t, false)
+				patch3 := patchDaemonAvailable(t, true)
+				patch4 := patchImageVerify(t, false)
+				patch5 := patchImageDigest(t, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+				patch6 := patchTagImageToLocal(t, false)
+				patch7 := patchImagePush(t, false)
 				return []*mpatch.Patch{patch1, patch2, patch3, patch4, patch5, patch6, patch7}
 			},
 		},
@@ -189,6 +338,65 @@ func TestHelperPushImage(t *testing.T) {
 	}
 }
 
+func patchDaemonAvailable(t *testing.T, available bool) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(docker.DaemonAvailable, func() bool {
+		return available
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchImageVerify(t *testing.T, fail bool) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(docker.ImageVerify, func(imageRef string, conf *pluginapi.CustomconfigImageVerification) (*pluginapi.ImagesItems0Verification, error) {
+		if imageRef != "docker.com" || conf.Signature != "cosign" {
+			t.Errorf("Unexpected verification of %s with %v", imageRef, conf)
+		}
+		if fail {
+			return &pluginapi.ImagesItems0Verification{Result: "failed"}, errEmpty
+		}
+		return &pluginapi.ImagesItems0Verification{Result: "verified", Digest: testImageDigest}, nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchImageCopy(t *testing.T, src string, fail bool) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(docker.ImageCopy, func(srcRef, dstRef string, authConf *types.AuthConfig, cacert string) (bool, error) {
+		if srcRef != src || dstRef != docker.GetImageNewTag("busybox", "") {
+			t.Errorf("Unexpected copy from %s to %s", srcRef, dstRef)
+		}
+		if fail {
+			return false, errEmpty
+		}
+		return true, nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
+func patchImageDigest(t *testing.T, dgst string) *mpatch.Patch {
+	patch, patchErr := mpatch.PatchMethod(docker.ImageDigest, func(imageRef string) (string, error) {
+		if imageRef != "docker.com" {
+			t.Errorf("Unexpected digest of %s", imageRef)
+		}
+		return dgst, nil
+	})
+	if patchErr != nil {
+		t.Errorf("patch error: %v", patchErr)
+		return nil
+	}
+	return patch
+}
+
 func patchImagePush(t *testing.T, fail bool) *mpatch.Patch {
 	patch, patchErr := mpatch.PatchMethod(docker.ImagePush, func(imageRef string, authConf *types.AuthConfig) error {
 		if fail {
@@ -725,11 +933,11 @@ func TestHelperhelperCopyToDay0(t *testing.T) {
 			},
 		},
 		{
-			"disconnect failed",
+			"connection kept after copy",
 			context.TODO(),
 			day0_nodes,
 			[]string{"/tmp/something", "/tmp/"},
-			true,
+			false,
 			Executor{
 				Execspec:   ep.Execspec{},
 				tempParams: tempParameter{},
@@ -881,11 +1089,11 @@ func TestHelperCopyFromDay0(t *testing.T) {
 			},
 		},
 		{
-			"disconnect failed",
+			"connection kept after copy",
 			context.TODO(),
 			day0_nodes,
 			[]string{"/tmp/something", "/tmp/"},
-			true,
+			false,
 			Executor{
 				Execspec:   ep.Execspec{},
 				tempParams: tempParameter{},
@@ -0,0 +1,213 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// lineWriter writes the output of a node line by line to the console, with
+// the node name and IP as prefix, and to the log file of the node.
+type lineWriter struct {
+	mu      sync.Mutex
+	console io.Writer
+	// consoleMu serializes the lines of all the nodes on the console.
+	consoleMu *sync.Mutex
+	prefix    string
+	log       *nodeLog
+	buf       []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(string(bytes.TrimRight(w.buf[:i], "\r")))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes the last line if it is not terminated.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.writeLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *lineWriter) writeLine(line string) {
+	w.consoleMu.Lock()
+	fmt.Fprintf(w.console, "%s %s\n", w.prefix, line)
+	w.consoleMu.Unlock()
+	w.log.printf("%s", line)
+}
+
+// nodeLog is the log file of a node in an executor run, under
+// runtime/logs/<run>/<node>.log.
+type nodeLog struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	stdout *lineWriter
+	stderr *lineWriter
+}
+
+// printf writes a timestamped line to the log file, which is created on the
+// first line. The log file is optional, e.g. without a runtime folder.
+func (l *nodeLog) printf(format string, args ...interface{}) {
+	if l == nil || l.path == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			log.Warningf("Failed to open node log %s: %v", l.path, err)
+			l.path = ""
+			return
+		}
+		l.file = f
+	}
+	fmt.Fprintf(l.file, "%s %s\n", time.Now().UTC().Format(time.RFC3339), fmt.Sprintf(format, args...))
+}
+
+func (l *nodeLog) close() {
+	l.stdout.flush()
+	l.stderr.flush()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+func (ni *nodeInfo) logName() string {
+	if ni.name == "" {
+		return ni.ip + ".log"
+	}
+	return ni.name + ".log"
+}
+
+func (ni *nodeInfo) stdout() io.Writer {
+	if ni.log == nil {
+		return os.Stdout
+	}
+	return ni.log.stdout
+}
+
+func (ni *nodeInfo) stderr() io.Writer {
+	if ni.log == nil {
+		return os.Stderr
+	}
+	return ni.log.stderr
+}
+
+// beginCommand and endCommand mark the boundaries of a command in the log
+// file of the node.
+func (ni *nodeInfo) beginCommand(cmdType string, cmd []string) {
+	ni.log.printf("=== %s: %s", cmdType, strings.Join(cmd, " "))
+}
+
+func (ni *nodeInfo) endCommand(cmdType string, err error) {
+	if ni.log != nil {
+		ni.log.stdout.flush()
+		ni.log.stderr.flush()
+	}
+	if err != nil {
+		ni.log.printf("=== %s failed: %v", cmdType, err)
+	} else {
+		ni.log.printf("=== %s done", cmdType)
+	}
+}
+
+// openNodeLogs sets the output of the nodes for a run. The log files are
+// under <runtime>/logs/<run>, where the run is named after the spec and
+// the start time, and the console lines are prefixed with the node name and
+// IP. It returns the log folder, or an empty string without a runtime
+// folder.
+func (e *Executor) openNodeLogs(stdout, stderr io.Writer) string {
+	dir := ""
+	if e.tempParams.Runtimedir != "" {
+		name := "executor"
+		if e.Metadata != nil && e.Metadata.Name != "" {
+			name = e.Metadata.Name
+		}
+		run := fmt.Sprintf("%s-%s", name, time.Now().UTC().Format("20060102-150405.000"))
+		dir = filepath.Join(e.tempParams.Runtimedir, "logs", run)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			log.Warningf("Failed to create node log folder %s: %v", dir, err)
+			dir = ""
+		}
+	}
+	consoleMu := &sync.Mutex{}
+	for _, n := range e.allNodes() {
+		l := &nodeLog{}
+		if dir != "" {
+			l.path = filepath.Join(dir, n.logName())
+		}
+		prefix := fmt.Sprintf("[%s %s]", n.name, n.ip)
+		l.stdout = &lineWriter{console: stdout, consoleMu: consoleMu, prefix: prefix, log: l}
+		l.stderr = &lineWriter{console: stderr, consoleMu: consoleMu, prefix: prefix, log: l}
+		n.log = l
+	}
+	return dir
+}
+
+func (e *Executor) closeNodeLogs() {
+	for _, n := range e.allNodes() {
+		if n.log != nil {
+			n.log.close()
+			n.log = nil
+		}
+	}
+}
+
+// reportNodes writes a table of how many commands changed, were skipped on,
+// or failed on each node.
+func (e *Executor) reportNodes(w io.Writer, dir string) {
+	rows := []string{}
+	for _, n := range e.allNodes() {
+		if n.changed+n.skipped+n.failed == 0 {
+			continue
+		}
+		logFile := "-"
+		if dir != "" {
+			logFile = filepath.Join(dir, n.logName())
+		}
+		rows = append(rows, fmt.Sprintf("%s\t%s\t%d\t%d\t%d\t%s", n.name, n.ip, n.changed, n.skipped, n.failed, logFile))
+	}
+	if len(rows) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tIP\tCHANGED\tSKIPPED\tFAILED\tLOG")
+	for _, row := range rows {
+		fmt.Fprintln(tw, row)
+	}
+	tw.Flush()
+}
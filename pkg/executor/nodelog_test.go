@@ -0,0 +1,78 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineWriter(t *testing.T) {
+	var console bytes.Buffer
+	l := &nodeLog{}
+	w := &lineWriter{console: &console, consoleMu: &sync.Mutex{}, prefix: "[node-1 10.0.0.1]", log: l}
+	_, err := w.Write([]byte("first\r\nsec"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ond\nlast"))
+	require.NoError(t, err)
+	require.Equal(t, "[node-1 10.0.0.1] first\n[node-1 10.0.0.1] second\n", console.String())
+	w.flush()
+	require.Equal(t, "[node-1 10.0.0.1] first\n[node-1 10.0.0.1] second\n[node-1 10.0.0.1] last\n", console.String())
+}
+
+func TestNodeLogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	e := New()
+	require.NoError(t, e.NodeListUpdate(&pluginapi.KitconfigParameters{}))
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{}}
+	e.tempParams.Runtimedir = dir
+	e.Metadata = &ep.ExecspecMetadata{Name: "test"}
+	e.Spec = &ep.ExecspecSpec{Steps: []*ep.ExecspecSpecStepsItems0{
+		{
+			Name:            "logs",
+			ContinueOnError: true,
+			Nodes:           &ep.ExecspecSpecStepsItems0Nodes{AllOf: []string{"day-0"}},
+			Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+				{Type: "shell", Cmd: []string{"echo", "hello"}},
+				{Type: "shell", Cmd: []string{"sh", "-c", "echo oops >&2; exit 1"}, Register: "oops", IgnoreErrors: true},
+				{Type: "shell", Cmd: []string{"false"}},
+			},
+		},
+	}}
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, e.RunWithAttachIO(context.TODO(), nil, &stdout, &stderr))
+	require.Contains(t, stdout.String(), "[day-0 127.0.0.1] hello\n")
+	require.Contains(t, stderr.String(), "[day-0 127.0.0.1] oops\n")
+
+	runs, err := filepath.Glob(filepath.Join(dir, "logs", "test-*", "day-0.log"))
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	b, err := ioutil.ReadFile(runs[0])
+	require.NoError(t, err)
+	for _, line := range []string{
+		"=== shell: echo hello\n", " hello\n", "=== shell done\n",
+		" oops\n", "=== shell: false\n", "=== shell failed: exit status 1\n",
+	} {
+		require.Contains(t, string(b), line)
+	}
+	require.Regexp(t, `NODE\s+IP\s+CHANGED\s+SKIPPED\s+FAILED\s+LOG\nday-0\s+127.0.0.1\s+2\s+0\s+1\s+`+regexp.QuoteMeta(runs[0]), stdout.String())
+}
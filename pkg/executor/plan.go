@@ -0,0 +1,143 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	api "github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+)
+
+// unknownBeforeRun describes a template which cannot be rendered before the
+// spec runs.
+func unknownBeforeRun(s string) string {
+	return fmt.Sprintf("%s (unknown before run)", s)
+}
+
+// renderBeforeRun renders a template for a node, unless it refers to the
+// registered results or the facts of the node, which are only known when
+// the spec runs.
+func (e *Executor) renderBeforeRun(s string, n *nodeInfo) (string, bool) {
+	if strings.Contains(s, ".Register") || strings.Contains(s, ".Facts") {
+		return "", false
+	}
+	r, err := e.StringOverrideWithNode(s, n)
+	if err != nil {
+		return "", false
+	}
+	return r, true
+}
+
+func (e *Executor) explainCommand(w io.Writer, k int, command *api.ExecspecSpecStepsItems0CommandsItems0, batch []*nodeInfo) {
+	fmt.Fprintf(w, "    Command %d: %s\n", k+1, command.Type)
+	if command.When != "" {
+		fmt.Fprintf(w, "      when: %s\n", command.When)
+	}
+	if command.Register != "" {
+		fmt.Fprintf(w, "      register: %s\n", command.Register)
+	}
+	for _, n := range batch {
+		note := ""
+		whenString, known := e.renderBeforeRun(command.When, n)
+		if !known {
+			note = ", " + unknownBeforeRun("when")
+		} else if whenString != "" {
+			when, err := strconv.ParseBool(whenString)
+			if err != nil {
+				fmt.Fprintf(w, "      %s (%s): invalid when %q\n", n.name, n.ip, whenString)
+				continue
+			}
+			if !when {
+				fmt.Fprintf(w, "      %s (%s): skipped, when is false\n", n.name, n.ip)
+				continue
+			}
+		}
+		cmd := strings.Join(command.Cmd, " ")
+		if rendered, known := e.renderBeforeRun(cmd, n); known {
+			cmd = rendered
+		} else {
+			cmd = unknownBeforeRun(cmd)
+		}
+		fmt.Fprintf(w, "      %s (%s)%s: %s\n", n.name, n.ip, note, cmd)
+		for _, g := range []struct{ name, value string }{
+			{"creates", command.Creates}, {"unless", command.Unless}, {"onlyIf", command.OnlyIf},
+		} {
+			if g.value == "" {
+				continue
+			}
+			v, known := e.renderBeforeRun(g.value, n)
+			if !known {
+				v = unknownBeforeRun(g.value)
+			}
+			fmt.Fprintf(w, "        %s: %s\n", g.name, v)
+		}
+	}
+}
+
+// Explain writes the plan of the spec: the nodes selected by each step, in
+// batches, and each command rendered for each node. It does not connect to
+// the nodes.
+func (e *Executor) Explain(w io.Writer) error {
+	if e.Spec == nil {
+		return nil
+	}
+	for _, step := range e.Spec.Steps {
+		fmt.Fprintf(w, "Step %s\n", step.Name)
+		if step.Serial != "" {
+			fmt.Fprintf(w, "  serial: %s\n", step.Serial)
+		}
+		if step.MaxFailPercentage != nil {
+			fmt.Fprintf(w, "  max_fail_percentage: %d\n", *step.MaxFailPercentage)
+		}
+		if step.ContinueOnError {
+			fmt.Fprintf(w, "  continue_on_error: true\n")
+		}
+		batches, err := splitBatches(e.selectNodes(step), step.Serial)
+		if err != nil {
+			return err
+		}
+		if len(batches) == 0 {
+			fmt.Fprintf(w, "  No nodes are selected\n")
+		}
+		for b, batch := range batches {
+			names := []string{}
+			for _, n := range batch {
+				names = append(names, fmt.Sprintf("%s (%s)", n.name, n.ip))
+			}
+			fmt.Fprintf(w, "  Batch %d: %s\n", b+1, strings.Join(names, ", "))
+			for k, command := range step.Commands {
+				e.explainCommand(w, k, command, batch)
+			}
+		}
+	}
+	return nil
+}
+
+// Plan loads a spec like Run, and returns its plan without running it.
+func Plan(specFile string, epparams *pluginapi.EpParams, value interface{}) (string, error) {
+	e := New()
+	err := e.SetECParams(epparams)
+	if err != nil {
+		return "", err
+	}
+	err = e.SetTempValue(value)
+	if err != nil {
+		return "", err
+	}
+	err = e.LoadSpecFromFile(specFile)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	err = e.Explain(&b)
+	return b.String(), err
+}
@@ -0,0 +1,95 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+const planSpec = `
+apiVersion: conductor/v1
+kind: Executor
+metadata:
+  name: plan
+spec:
+  steps:
+  - name: rolling
+    serial: "1"
+    nodes:
+      allOf:
+      - controlplane
+    commands:
+    - type: shell
+      register: version
+      cmd:
+      - containerd
+      - --version
+    - type: shell
+      when: '\{\{ eq .Node.Name "cp-1" \}\}'
+      onlyIf: 'test -e /home/\{\{ .Node.User \}\}'
+      cmd:
+      - echo
+      - \{\{ .Node.IP \}\}
+    - type: shell
+      when: '\{\{ contains "1.6" .Register.version.Stdout \}\}'
+      cmd:
+      - echo
+      - \{\{ .Register.version.Stdout \}\}
+  - name: none
+    nodes:
+      allOf:
+      - worker
+`
+
+func TestExplain(t *testing.T) {
+	e := New()
+	require.NoError(t, e.SetECParams(&pluginapi.EpParams{
+		Kitconfig: &pluginapi.Kitconfig{
+			Parameters: &pluginapi.KitconfigParameters{
+				Nodes: []*pluginapi.Node{
+					{Name: "cp-1", IP: "10.0.0.1", User: "ec", SSHPasswd: "pw", Role: []string{"controlplane"}},
+					{Name: "cp-2", IP: "10.0.0.2", User: "ec", SSHPasswd: "pw", Role: []string{"controlplane"}},
+				},
+			},
+		},
+	}))
+	require.NoError(t, e.LoadSpecFromString(planSpec))
+
+	var b strings.Builder
+	require.NoError(t, e.Explain(&b))
+	require.Equal(t, `Step rolling
+  serial: 1
+  Batch 1: cp-1 (10.0.0.1)
+    Command 1: shell
+      register: version
+      cp-1 (10.0.0.1): containerd --version
+    Command 2: shell
+      when: \{\{ eq .Node.Name "cp-1" \}\}
+      cp-1 (10.0.0.1): echo 10.0.0.1
+        onlyIf: test -e /home/ec
+    Command 3: shell
+      when: \{\{ contains "1.6" .Register.version.Stdout \}\}
+      cp-1 (10.0.0.1), when (unknown before run): echo \{\{ .Register.version.Stdout \}\} (unknown before run)
+  Batch 2: cp-2 (10.0.0.2)
+    Command 1: shell
+      register: version
+      cp-2 (10.0.0.2): containerd --version
+    Command 2: shell
+      when: \{\{ eq .Node.Name "cp-1" \}\}
+      cp-2 (10.0.0.2): skipped, when is false
+    Command 3: shell
+      when: \{\{ contains "1.6" .Register.version.Stdout \}\}
+      cp-2 (10.0.0.2), when (unknown before run): echo \{\{ .Register.version.Stdout \}\} (unknown before run)
+Step none
+  No nodes are selected
+`, b.String())
+}
@@ -0,0 +1,109 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+
+	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+)
+
+// commandResult is the result of a command on a node, registered with the
+// register option of the command. The templates of the later commands refer
+// to it as .Register.<name>, e.g. .Register.<name>.Stdout.
+type commandResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	// Skipped is true if the when clause of the command skipped the node.
+	Skipped bool
+}
+
+func (ni *nodeInfo) register(name string, result *commandResult) {
+	if ni.registers == nil {
+		ni.registers = map[string]*commandResult{}
+	}
+	ni.registers[name] = result
+}
+
+// exitCode returns the exit status of a command which ran and failed.
+func exitCode(err error) (int, bool) {
+	var sshErr *ssh.ExitError
+	if errors.As(err, &sshErr) {
+		return sshErr.ExitStatus(), true
+	}
+	var execErr *exec.ExitError
+	if errors.As(err, &execErr) {
+		return execErr.ExitCode(), true
+	}
+	var dockerErr *docker.ExecExitError
+	if errors.As(err, &dockerErr) {
+		return dockerErr.ExitCode, true
+	}
+	return 0, false
+}
+
+// helperShellRegister runs a shell command like helperShell, and registers
+// its output and exit status on each node with the name, if any. Like in
+// helperShell, a non-zero exit status fails the command, unless
+// ignoreErrors is set. The result is registered in both cases, while
+// connection errors still fail the command without a result. The command
+// runs with a tty like in helperShell, so the ssh nodes report the error
+// output of the command in Stdout.
+func (e *Executor) helperShellRegister(ctx context.Context, nodes map[string]*nodeInfo, cmd []string, name string, ignoreErrors bool) error {
+	log.Debugf("cmd: %v, register: %v", strings.Join(cmd, "@"), name)
+	errs := newNodeErrors()
+	wg := sync.WaitGroup{}
+	for _, n := range nodes {
+		n := n
+		cmd, err := e.CmdOverrideWithNode(cmd, n)
+		if err != nil {
+			wg.Wait()
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := n.client.Connect(); err != nil {
+				errs.set(n, err)
+				return
+			}
+			var stdout, stderr bytes.Buffer
+			err := n.client.CmdWithAttachIO(ctx, cmd, nil,
+				io.MultiWriter(n.stdout(), &stdout), io.MultiWriter(n.stderr(), &stderr), true)
+			result := &commandResult{
+				Stdout: strings.TrimRight(stdout.String(), "\r\n"),
+				Stderr: strings.TrimRight(stderr.String(), "\r\n"),
+			}
+			if err != nil {
+				code, exited := exitCode(err)
+				if !exited {
+					errs.set(n, err)
+					return
+				}
+				result.ExitCode = code
+				if !ignoreErrors {
+					errs.set(n, err)
+				}
+			}
+			if name != "" {
+				n.register(name, result)
+			}
+		}()
+	}
+	wg.Wait()
+	return errs.err()
+}
@@ -0,0 +1,77 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/intel/edge-conductor/pkg/api/ep"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	dir, err := ioutil.TempDir("", "register")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	e := New()
+	e.tempParams.Kitconfig = &pluginapi.Kitconfig{Parameters: &pluginapi.KitconfigParameters{}}
+	day0 := &nodeInfo{name: "day-0", ip: "127.0.0.1", client: &day0Client{}}
+	step := &ep.ExecspecSpecStepsItems0{
+		Name: "register",
+		Commands: []*ep.ExecspecSpecStepsItems0CommandsItems0{
+			{
+				Type:         "shell",
+				Cmd:          []string{"sh", "-c", "echo v1.6.4; echo warning >&2; exit 3"},
+				Register:     "version",
+				IgnoreErrors: true,
+			},
+			{
+				Type:     "shell",
+				When:     `\{\{ eq .Register.version.ExitCode 0 \}\}`,
+				Cmd:      []string{"true"},
+				Register: "skipped",
+			},
+			{
+				Type: "shell",
+				When: `\{\{ contains "v1.6" .Register.version.Stdout \}\}`,
+				Cmd:  []string{"sh", "-c", `echo -n "\{\{ .Register.version.Stderr \}\}" > ` + out},
+			},
+		},
+	}
+
+	require.NoError(t, e.runStep(context.TODO(), step, map[string]*nodeInfo{day0.ip: day0}))
+	require.Equal(t, &commandResult{Stdout: "v1.6.4", Stderr: "warning", ExitCode: 3}, day0.registers["version"])
+	require.Equal(t, &commandResult{Skipped: true}, day0.registers["skipped"])
+	b, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, "warning", string(b))
+
+	// A non-zero exit status fails the command, unless errors are ignored.
+	step.Commands = []*ep.ExecspecSpecStepsItems0CommandsItems0{
+		{Type: "shell", Cmd: []string{"sh", "-c", "echo failed; exit 2"}, Register: "failed"},
+	}
+	require.Error(t, e.runStep(context.TODO(), step, map[string]*nodeInfo{day0.ip: day0}))
+	require.Equal(t, &commandResult{Stdout: "failed", ExitCode: 2}, day0.registers["failed"])
+	step.Commands[0].Register = ""
+	step.Commands[0].IgnoreErrors = true
+	require.NoError(t, e.runStep(context.TODO(), step, map[string]*nodeInfo{day0.ip: day0}))
+
+	// A command which cannot run still fails, even if errors are ignored.
+	step.Commands = []*ep.ExecspecSpecStepsItems0CommandsItems0{
+		{Type: "shell", Cmd: []string{filepath.Join(dir, "missing")}, Register: "missing", IgnoreErrors: true},
+	}
+	require.Error(t, e.runStep(context.TODO(), step, map[string]*nodeInfo{day0.ip: day0}))
+	require.NotContains(t, day0.registers, "missing")
+}
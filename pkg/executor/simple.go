@@ -21,6 +21,7 @@ type ExecutorWrapper interface {
 
 func SimpleShell(s *pluginapi.ExecSimpleShell, epparams *pluginapi.EpParams) error {
 	e := New()
+	defer e.Close()
 	err := e.SetECParams(epparams)
 	if err != nil {
 		return err
@@ -39,6 +40,7 @@ func SimpleShell(s *pluginapi.ExecSimpleShell, epparams *pluginapi.EpParams) err
 
 func Run(specFile string, epparams *pluginapi.EpParams, value interface{}) error {
 	e := New()
+	defer e.Close()
 	err := e.SetECParams(epparams)
 	if err != nil {
 		return err
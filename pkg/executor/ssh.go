@@ -9,25 +9,50 @@ package executor
 import (
 	"context"
 	"fmt"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 )
 
+var (
+	// sshKeepAliveInterval is the interval of the keepalive requests on the
+	// connection to a node. A connection which does not answer within the
+	// interval is closed, and reconnected by the next command.
+	sshKeepAliveInterval = 30 * time.Second
+)
+
+// sshClient keeps one connection to a node, which is shared by the commands
+// on the node until Disconnect. Each command opens its own session.
 type sshClient struct {
-	host     string
-	user     string
-	password string
-	key      string
-	port     int
-	config   *ssh.ClientConfig
-	client   *ssh.Client
+	host        string
+	user        string
+	password    string
+	key         string
+	port        int
+	fingerprint string
+	config      *ssh.ClientConfig
+	client      *ssh.Client
+
+	mu   sync.Mutex
+	stop chan struct{}
 }
 
+// Connect connects to the node, unless it is already connected.
 func (c *sshClient) Connect() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil {
+		return nil
+	}
+	return c.dial()
+}
+
+func (c *sshClient) dial() error {
 	c.config = &ssh.ClientConfig{
 		Config: ssh.Config{
 			Ciphers: []string{
@@ -35,10 +60,9 @@ func (c *sshClient) Connect() error {
 				"chacha20-poly1305@openssh.com",
 				"aes256-ctr", "aes256-cbc"},
 		},
-		Timeout: time.Second * 5,
-		User:    c.user,
-		// #nosec G106
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         time.Second * 5,
+		User:            c.user,
+		HostKeyCallback: eputils.HostKeyCallback(c.fingerprint),
 		Auth:            []ssh.AuthMethod{ssh.Password(c.password)},
 	}
 
@@ -50,19 +74,97 @@ func (c *sshClient) Connect() error {
 	}
 
 	addr := fmt.Sprintf("%s:%d", c.host, c.port)
-	client, err := ssh.Dial("tcp", addr, c.config)
+	client, err := eputils.DialSSH(addr, c.config)
 	c.client = client
+	if client != nil {
+		c.stop = make(chan struct{})
+		go c.keepAlive(client, c.stop, sshKeepAliveInterval)
+	}
+	return err
+}
+
+// keepAlive sends keepalive requests on the connection until it is stopped,
+// and closes the connection if the node does not answer.
+func (c *sshClient) keepAlive(client *ssh.Client, stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		replied := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			replied <- err
+		}()
+		var err error
+		select {
+		case <-stop:
+			return
+		case err = <-replied:
+		case <-time.After(interval):
+			err = io.ErrNoProgress
+		}
+		if err != nil {
+			log.Warningf("Connection to %s:%d is lost: %v", c.host, c.port, err)
+			c.mu.Lock()
+			if c.client == client {
+				c.close()
+			}
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (c *sshClient) close() error {
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	if c.client == nil {
+		return nil
+	}
+	err := c.client.Close()
+	c.client = nil
 	return err
 }
 
+// Disconnect closes the connection to the node.
 func (c *sshClient) Disconnect() error {
-	return c.client.Close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.close()
+}
+
+// newSession opens a session on the connection to the node. The node is
+// reconnected once if the connection is dropped.
+func (c *sshClient) newSession() (*ssh.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil {
+		if err := c.dial(); err != nil {
+			return nil, err
+		}
+	}
+	session, err := c.client.NewSession()
+	if err == nil {
+		return session, nil
+	}
+	log.Warningf("Failed to open session on %s:%d, reconnect: %v", c.host, c.port, err)
+	_ = c.close()
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c.client.NewSession()
 }
 
 func (c *sshClient) CmdWithAttachIO(ctx context.Context, cmd []string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
 	cmdStr := strings.Join(cmd, " ")
 	log.Debugf("CmdWithAttachIO: cmd: %v", cmdStr)
-	session, err := c.client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
 		return err
 	}
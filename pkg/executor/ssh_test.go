@@ -7,13 +7,23 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	sshd "github.com/gliderlabs/ssh"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"github.com/stretchr/testify/require"
 	"github.com/undefinedlabs/go-mpatch"
 	"golang.org/x/crypto/ssh"
 )
@@ -346,3 +356,80 @@ func patchNewSession(t *testing.T, fail bool) *mpatch.Patch {
 	}
 	return patch
 }
+
+func TestSSHClientReuse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sshclient")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	knownHosts := eputils.KnownHostsFile
+	eputils.KnownHostsFile = filepath.Join(dir, "known_hosts")
+	defer func() { eputils.KnownHostsFile = knownHosts }()
+	interval := sshKeepAliveInterval
+	sshKeepAliveInterval = 50 * time.Millisecond
+	defer func() { sshKeepAliveInterval = interval }()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	mu := sync.Mutex{}
+	conns := []net.Conn{}
+	server := &sshd.Server{
+		Handler: func(s sshd.Session) {
+			_, _ = io.WriteString(s, strings.Join(s.Command(), " ")+"\n")
+		},
+		ConnCallback: func(ctx sshd.Context, conn net.Conn) net.Conn {
+			mu.Lock()
+			defer mu.Unlock()
+			conns = append(conns, conn)
+			return conn
+		},
+	}
+	go func() {
+		_ = server.Serve(l)
+	}()
+	defer server.Close()
+	numConns := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(conns)
+	}
+
+	c := &sshClient{
+		host:     "127.0.0.1",
+		port:     l.Addr().(*net.TCPAddr).Port,
+		user:     "ec",
+		password: "123456",
+	}
+	run := func() {
+		var out bytes.Buffer
+		require.NoError(t, c.Connect())
+		require.NoError(t, c.CmdWithAttachIO(context.TODO(), []string{"echo", "hello"}, nil, &out, nil, false))
+		require.Equal(t, "echo hello\n", out.String())
+	}
+	for i := 0; i < 3; i++ {
+		run()
+	}
+	require.Equal(t, 1, numConns())
+
+	// Commands after a dropped connection reconnect.
+	mu.Lock()
+	conns[0].Close()
+	mu.Unlock()
+	run()
+	require.Equal(t, 2, numConns())
+
+	// The keepalive closes a dropped connection.
+	mu.Lock()
+	conns[1].Close()
+	mu.Unlock()
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.client == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	run()
+	require.Equal(t, 3, numConns())
+
+	require.NoError(t, c.Disconnect())
+	require.Nil(t, c.client)
+	require.NoError(t, c.Disconnect())
+}
@@ -0,0 +1,89 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultTemplateMode = "0644"
+
+// helperTemplate renders a file of day-0 with the parameters of each node,
+// and uploads it to the node. The command is
+// [<local file>, <remote file>, <mode>, <owner>], where the mode is 0644 by
+// default, and the file is written with sudo if an owner is given.
+func (e *Executor) helperTemplate(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
+	if len(cmd) < 2 || len(cmd) > 4 {
+		log.Errorf("template: expect [<local file>, <remote file>, <mode>, <owner>], got %v", cmd)
+		return eputils.GetError("errExecCmdArgs")
+	}
+	src, err := ioutil.ReadFile(cmd[0])
+	if err != nil {
+		log.Errorf("template: failed to read %s, %v", cmd[0], err)
+		return err
+	}
+	mode := defaultTemplateMode
+	if len(cmd) > 2 && cmd[2] != "" {
+		mode = cmd[2]
+	}
+	owner := ""
+	if len(cmd) > 3 {
+		owner = cmd[3]
+	}
+
+	contents := map[string]string{}
+	for ip, n := range nodes {
+		p, err := e.nodeParams(n)
+		if err != nil {
+			return err
+		}
+		content, err := eputils.StringTemplateConvertWithParams(string(src), p)
+		if err != nil {
+			log.Errorf("template: failed to render %s for node %s, %v", cmd[0], ip, err)
+			return eputils.GetError("errStringOverrideWithNode")
+		}
+		contents[ip] = content
+	}
+
+	return forEachNode(nodes, func(n *nodeInfo) error {
+		dest, err := e.StringOverrideWithNode(cmd[1], n)
+		if err != nil {
+			return err
+		}
+		// The SSH and container clients join the arguments with spaces,
+		// while day-0 runs them as they are.
+		path := dest
+		if _, local := n.client.(*day0Client); !local {
+			path = shellQuote(dest)
+		}
+		sudo := []string{}
+		if owner != "" {
+			sudo = []string{"sudo"}
+		}
+		// The file is created with its mode before the content is written.
+		if err := runOnNode(ctx, n, append(sudo, "install", "-m", mode, "/dev/null", path), nil, nil); err != nil {
+			return err
+		}
+		if err := runOnNode(ctx, n, append(sudo, "tee", path), strings.NewReader(contents[n.ip]), nil); err != nil {
+			return err
+		}
+		if owner != "" {
+			if err := runOnNode(ctx, n, []string{"sudo", "chown", owner, path}, nil, nil); err != nil {
+				return err
+			}
+		}
+		log.Infof("template: %s is written to %s on node %s", cmd[0], dest, n.ip)
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package executor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	WAITFOR_TCP   = "tcp"
+	WAITFOR_HTTP  = "http"
+	WAITFOR_SHELL = "shell"
+)
+
+var (
+	defaultWaitForTimeout = 5 * time.Minute
+	waitForInterval       = 5 * time.Second
+)
+
+// probeOnce checks the condition of a waitFor command once. TCP ports and
+// HTTP URLs are probed from day-0, and shell probes run on the node.
+func probeOnce(ctx context.Context, n *nodeInfo, kind, target string) error {
+	switch kind {
+	case WAITFOR_TCP:
+		conn, err := (&net.Dialer{Timeout: waitForInterval}).DialContext(ctx, "tcp", target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case WAITFOR_HTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := (&http.Client{Timeout: waitForInterval}).Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return eputils.GetError("errWaitForTimeout")
+		}
+		return nil
+	default:
+		return runOnNode(ctx, n, []string{"sh"}, strings.NewReader(target), nil)
+	}
+}
+
+// helperWaitFor polls a TCP port, an HTTP URL or a shell probe for each node
+// until it succeeds or the timeout passes. The command is
+// [tcp|http|shell, <host:port, URL or probe>, <timeout>], where the timeout
+// is a duration, 5m by default.
+func (e *Executor) helperWaitFor(ctx context.Context, nodes map[string]*nodeInfo, cmd []string) error {
+	if len(cmd) < 2 || len(cmd) > 3 ||
+		(cmd[0] != WAITFOR_TCP && cmd[0] != WAITFOR_HTTP && cmd[0] != WAITFOR_SHELL) {
+		log.Errorf("waitFor: expect [tcp|http|shell, <target>, <timeout>], got %v", cmd)
+		return eputils.GetError("errExecCmdArgs")
+	}
+	timeout := defaultWaitForTimeout
+	if len(cmd) == 3 && cmd[2] != "" {
+		d, err := time.ParseDuration(cmd[2])
+		if err != nil || d <= 0 {
+			log.Errorf("waitFor: invalid timeout %s", cmd[2])
+			return eputils.GetError("errExecCmdArgs")
+		}
+		timeout = d
+	}
+
+	return forEachNode(nodes, func(n *nodeInfo) error {
+		target, err := e.StringOverrideWithNode(cmd[1], n)
+		if err != nil {
+			return err
+		}
+		deadline := time.Now().Add(timeout)
+		for {
+			err := probeOnce(ctx, n, cmd[0], target)
+			if err == nil {
+				return nil
+			}
+			if time.Now().Add(waitForInterval).After(deadline) {
+				log.Errorf("waitFor: %s %s is not ready for node %s after %v, %v", cmd[0], target, n.ip, timeout, err)
+				return eputils.GetError("errWaitForTimeout")
+			}
+			log.Debugf("waitFor: %s %s is not ready for node %s, %v", cmd[0], target, n.ip, err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(waitForInterval):
+			}
+		}
+	})
+}
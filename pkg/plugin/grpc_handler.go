@@ -38,15 +38,21 @@ func New(name string, data eputils.SchemaStruct, plugin_data eputils.SchemaStruc
 }
 
 func (p *Plugin) Connect(address string) error {
+	return p.ConnectContext(context.Background(), address)
+}
+
+// ConnectContext connects to the workflow server and waits for a step, until
+// ctx is cancelled.
+func (p *Plugin) ConnectContext(ctx context.Context, address string) error {
 	clientTLSConfig, err := certmgr.GetTLSConfigByName("workflow", "client", "")
 	if err != nil {
 		return err
 	}
 	clientCreds := credentials.NewTLS(clientTLSConfig)
-	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_TIMEOUT*time.Second)
+	dialctx, cancel := context.WithTimeout(ctx, DEFAULT_TIMEOUT*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, address, grpc.WithTransportCredentials(clientCreds))
+	conn, err := grpc.DialContext(dialctx, address, grpc.WithTransportCredentials(clientCreds))
 	if err != nil {
 		log.Warningf("grpc connect error: %v", err)
 		return eputils.GetError("errGrpcConnect")
@@ -54,9 +60,9 @@ func (p *Plugin) Connect(address string) error {
 	p.client = wfapi.NewWorkflowClient(conn)
 	p.conn = conn
 
-	ctx, cancel = context.WithTimeout(context.Background(), CONNECT_TIMEOUT*time.Second)
+	connctx, cancel := context.WithTimeout(ctx, CONNECT_TIMEOUT*time.Second)
 	defer cancel()
-	r, err := p.client.PluginConnect(ctx, &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: p.name}})
+	r, err := p.client.PluginConnect(connctx, &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: p.name}})
 	if err != nil {
 		return err
 	}
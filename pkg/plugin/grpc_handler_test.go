@@ -7,6 +7,7 @@
 package plugin_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -84,10 +85,10 @@ func Test_Start(t *testing.T) {
 
 	epplugins.PluginList = append(epplugins.PluginList, "test")
 
-	err := wf.Start("test", "localhost:100000", "./workflow/workflow.yml")
+	err := wf.Start(context.Background(), "test", "localhost:100000", "./workflow/workflow.yml")
 	t.Log("workflow ret:", err)
 
-	err = wf.Start("test", "localhost:50090", "./workflow/workflow.yml")
+	err = wf.Start(context.Background(), "test", "localhost:50090", "./workflow/workflow.yml")
 	t.Log("workflow ret:", err)
 
 	errWaitPluginFinished := plugin.WaitPluginFinished("file-exporter")
@@ -51,6 +51,79 @@ func (hook *LogHook) Levels() []log.Level {
 	return log.AllLevels
 }
 
+// LogFieldPlugin is the log field with the name of the plugin which logs a
+// line, for the lines logged while several steps run at the same time.
+const LogFieldPlugin = "plugin"
+
+// stepLogs sends the log lines of the plugin process to the workflow server,
+// on the log streams of the steps in flight. All the plugins log with the
+// standard logger, so a single hook is added for the process, and each line
+// is sent once.
+type stepLogs struct {
+	mutex   sync.Mutex
+	once    sync.Once
+	streams map[string]wfapi.Workflow_PluginPutLogClient
+}
+
+var logs = &stepLogs{streams: map[string]wfapi.Workflow_PluginPutLogClient{}}
+
+// add sends the log lines to the stream of a step until remove.
+func (l *stepLogs) add(name string, stream wfapi.Workflow_PluginPutLogClient) {
+	l.once.Do(func() {
+		log.AddHook(l)
+	})
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.streams[name] = stream
+}
+
+func (l *stepLogs) remove(name string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if stream, has := l.streams[name]; has {
+		_ = stream.CloseSend()
+		delete(l.streams, name)
+	}
+}
+
+// Fire sends a line tagged with a plugin to the stream of the plugin. Other
+// lines are tagged with the step in flight, and are not tagged when several
+// steps are in flight, as their plugin is not known.
+func (l *stepLogs) Fire(entry *log.Entry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if len(l.streams) == 0 {
+		return nil
+	}
+	line, err := entry.Bytes()
+	if err != nil {
+		return err
+	}
+
+	key, _ := entry.Data[LogFieldPlugin].(string)
+	_, known := l.streams[key]
+	if !known {
+		key = ""
+		for name := range l.streams {
+			if key == "" || name < key {
+				key = name
+			}
+		}
+	}
+	msg := &wfapi.Log{Log: string(line)}
+	if known || len(l.streams) == 1 {
+		msg.Plugin = &wfapi.Plugin{Name: key}
+	}
+	if err := l.streams[key].Send(msg); err != nil {
+		delete(l.streams, key)
+	}
+	return nil
+}
+
+func (l *stepLogs) Levels() []log.Level {
+	return log.AllLevels
+}
+
 func RegisterPlugin(name string, in *eputils.SchemaMapData, out *eputils.SchemaMapData,
 	mainFunc func(eputils.SchemaMapData, *eputils.SchemaMapData) error) {
 	m := &PluginMainFuncs{
@@ -66,15 +139,23 @@ func RegisterPlugin(name string, in *eputils.SchemaMapData, out *eputils.SchemaM
 	mains = append(mains, m)
 }
 
-func run(m *PluginMainFuncs) error {
-	logctx, logcancel := context.WithCancel(context.Background())
+func run(ctx context.Context, m *PluginMainFuncs) error {
+	logctx, logcancel := context.WithCancel(ctx)
 	defer logcancel()
 	log.Infof("Start Plugin %v\n", m.name)
 	p := New(m.name, m.data, m.plugin_data)
 
 	for {
+		if ctx.Err() != nil {
+			log.Infof("Plugin %v is cancelled\n", m.name)
+			return eputils.GetError("errWorkflowCancelled")
+		}
 		log.Infof("Connecting Plugin %v\n", m.name)
-		if err := p.Connect(Address); err != nil {
+		if err := p.ConnectContext(ctx, Address); err != nil {
+			if ctx.Err() != nil {
+				log.Infof("Plugin %v is cancelled\n", m.name)
+				return eputils.GetError("errWorkflowCancelled")
+			}
 			log.Warningf("Plugin connection error: %v\n", err)
 			return eputils.GetError("errPluginConnect")
 		}
@@ -90,22 +171,14 @@ func run(m *PluginMainFuncs) error {
 				log.Warningf("Get log stream error, %v", err)
 				return eputils.GetError("errGetLogStream")
 			}
-			logexit := false
-			log.AddHook(&LogHook{
-				writer: func(s string) {
-					if logexit {
-						return
-					}
-					if err := logstream.Send(&wfapi.Log{Log: s}); err != nil {
-						logexit = true
-					}
-				},
-			})
+			logs.add(m.name, logstream)
 		}
-		log.Infof("Exec Plugin %v\n", m.name)
+		plog := log.WithField(LogFieldPlugin, m.name)
+		plog.Infof("Exec Plugin %v\n", m.name)
 		for k := range *m.in {
 			if _, has := (*m.plugin_data)[k]; !has {
 				log.Warningf("Cannot find input schema: %s\n", k)
+				logs.remove(m.name)
 				return eputils.GetError("errInputSchema")
 			}
 			(*m.in)[k] = (*m.plugin_data)[k]
@@ -119,9 +192,10 @@ func run(m *PluginMainFuncs) error {
 		if err == nil {
 			*m.plugin_data = *m.out
 		} else {
-			log.Errorf("Plugin error: name: %v, err: %v", m.name, err)
+			plog.Errorf("Plugin error: name: %v, err: %v", m.name, err)
 		}
-		log.Infof("Complete Plugin %v\n", m.name)
+		plog.Infof("Complete Plugin %v\n", m.name)
+		logs.remove(m.name)
 		err = p.Complete(err)
 		if err != nil {
 			return err
@@ -129,7 +203,9 @@ func run(m *PluginMainFuncs) error {
 	}
 }
 
-func StartPlugin(name string, errch chan error) error {
+// StartPlugin serves the steps of the plugin until the workflow has no more
+// step for it, or ctx is cancelled.
+func StartPlugin(ctx context.Context, name string, errch chan error) error {
 	for _, m := range mains {
 		if m.name == name {
 			if m.started {
@@ -139,7 +215,7 @@ func StartPlugin(name string, errch chan error) error {
 			m.started = true
 			m.wg.Add(1)
 			go func(m *PluginMainFuncs) {
-				m.err = run(m)
+				m.err = run(ctx, m)
 				if errch != nil {
 					errch <- m.err
 				}
@@ -7,9 +7,13 @@
 package plugin
 
 import (
+	"io"
 	"testing"
 
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func Test_Fire(t *testing.T) {
@@ -21,3 +25,80 @@ func Test_Fire(t *testing.T) {
 	log.Infof("%s", hook.Levels())
 	t.Log("Done")
 }
+
+type fakeLogStream struct {
+	grpc.ClientStream
+	logs   []*wfapi.Log
+	closed bool
+}
+
+func (f *fakeLogStream) Send(l *wfapi.Log) error {
+	if f.closed {
+		return io.EOF
+	}
+	f.logs = append(f.logs, l)
+	return nil
+}
+
+func (f *fakeLogStream) CloseSend() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeLogStream) CloseAndRecv() (*wfapi.Result, error) {
+	return nil, nil
+}
+
+func pluginNames(logs []*wfapi.Log) []string {
+	var names []string
+	for _, l := range logs {
+		if l.Plugin == nil {
+			names = append(names, "")
+		} else {
+			names = append(names, l.Plugin.Name)
+		}
+	}
+	return names
+}
+
+func TestStepLogs(t *testing.T) {
+	l := &stepLogs{streams: map[string]wfapi.Workflow_PluginPutLogClient{}}
+	hooks := len(log.StandardLogger().Hooks[log.InfoLevel])
+	a, b := &fakeLogStream{}, &fakeLogStream{}
+	logger := log.New()
+	fire := func(plugin string) {
+		entry := log.NewEntry(logger)
+		if plugin != "" {
+			entry = entry.WithField(LogFieldPlugin, plugin)
+		}
+		entry.Message = "line"
+		require.NoError(t, l.Fire(entry))
+	}
+
+	// Without step in flight, the lines are not sent.
+	fire("")
+
+	// The lines of a single step are tagged with its plugin.
+	l.add("a", a)
+	fire("")
+	fire("a")
+
+	// With several steps, the lines are sent once, and the lines which
+	// are not tagged with a plugin are sent without plugin.
+	l.add("b", b)
+	fire("")
+	fire("b")
+	fire("a")
+	fire("c")
+
+	l.remove("a")
+	l.remove("b")
+	fire("a")
+
+	require.Equal(t, hooks+1, len(log.StandardLogger().Hooks[log.InfoLevel]), "hook is not added once")
+	require.Equal(t, []string{"a", "a", "", "a", ""}, pluginNames(a.logs))
+	require.Equal(t, []string{"b"}, pluginNames(b.logs))
+	require.True(t, a.closed)
+	require.True(t, b.closed)
+	require.Empty(t, l.streams)
+}
@@ -0,0 +1,247 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+// Package pluginsdk helps to write Edge Conductor plugins as standalone
+// executables, outside of the Edge Conductor repository.
+//
+// An executable plugin is declared in the workflow with a command:
+//
+//	plugins:
+//	- name: my-plugin
+//	  command: path/to/my-plugin
+//
+// The workflow server starts the command, and passes the server address and
+// the mTLS client certificate in the environment. The plugin then serves the
+// steps of the workflow which use it:
+//
+//	func main() {
+//		err := pluginsdk.Run(func(step *pluginsdk.Step) error {
+//			files := MyFiles{}
+//			if err := step.Input.Get("files", &files); err != nil {
+//				return err
+//			}
+//			return step.Output.Set("images", MyImages{})
+//		})
+//		if err != nil {
+//			os.Exit(1)
+//		}
+//	}
+//
+// The package only depends on the Workflow gRPC API, so that plugins do not
+// pull in the dependencies of the conductor itself.
+package pluginsdk
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	ENV_PLUGIN_NAME      = "EC_PLUGIN_NAME"
+	ENV_WORKFLOW_ADDRESS = "EC_WORKFLOW_ADDRESS"
+	ENV_WORKFLOW_CA      = "EC_WORKFLOW_CA"
+	ENV_WORKFLOW_CERT    = "EC_WORKFLOW_CERT"
+	ENV_WORKFLOW_KEY     = "EC_WORKFLOW_KEY"
+
+	CONNECT_TIMEOUT = 3600
+	DEFAULT_TIMEOUT = 5
+)
+
+var (
+	ErrConfig   = errors.New("plugin environment is not set by the workflow server")
+	ErrConnect  = errors.New("cannot connect to the workflow server")
+	ErrProtocol = errors.New("unexpected response from the workflow server")
+	ErrSchema   = errors.New("schema is not found in plugin data")
+)
+
+// Data holds the inputs or the outputs of a step, keyed by the schema names
+// declared for the plugin in the workflow.
+type Data map[string]json.RawMessage
+
+// Get decodes the data of the schema into v.
+func (d Data) Get(schema string, v interface{}) error {
+	buf, has := d[schema]
+	if !has {
+		return fmt.Errorf("%w: %s", ErrSchema, schema)
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// Set encodes v as the data of the schema.
+func (d Data) Set(schema string, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	d[schema] = buf
+	return nil
+}
+
+// Step is one step of the workflow served by the plugin.
+type Step struct {
+	// Params is the JSON encoded ep-params of the workflow.
+	Params json.RawMessage
+	Input  Data
+	Output Data
+}
+
+// Func executes one step. An error fails the step.
+type Func func(step *Step) error
+
+// Config is how the plugin connects to the workflow server.
+type Config struct {
+	Name     string
+	Address  string
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// ConfigFromEnv returns the configuration passed by the workflow server.
+func ConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		Name:     os.Getenv(ENV_PLUGIN_NAME),
+		Address:  os.Getenv(ENV_WORKFLOW_ADDRESS),
+		CAFile:   os.Getenv(ENV_WORKFLOW_CA),
+		CertFile: os.Getenv(ENV_WORKFLOW_CERT),
+		KeyFile:  os.Getenv(ENV_WORKFLOW_KEY),
+	}
+	if cfg.Name == "" || cfg.Address == "" || cfg.CAFile == "" || cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, ErrConfig
+	}
+	return cfg, nil
+}
+
+// Run serves the steps of the plugin with f until the workflow server has
+// no more step for it.
+func Run(f Func) error {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	return RunWithConfig(cfg, f)
+}
+
+// RunWithConfig is Run with an explicit configuration.
+func RunWithConfig(cfg *Config, f Func) error {
+	tlsConfig, err := clientTLSConfig(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DEFAULT_TIMEOUT*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, cfg.Address, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		log.Warningf("grpc connect error: %v", err)
+		return ErrConnect
+	}
+	defer conn.Close()
+	return serve(wfapi.NewWorkflowClient(conn), cfg.Name, f)
+}
+
+func clientTLSConfig(cfg *Config) (*tls.Config, error) {
+	rootPEM, err := ioutil.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		return nil, fmt.Errorf("failed to parse root certificate %s", cfg.CAFile)
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// serve connects the plugin for each step, and completes the step with the
+// result of f.
+func serve(client wfapi.WorkflowClient, name string, f Func) error {
+	plugin := &wfapi.Plugin{Name: name}
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), CONNECT_TIMEOUT*time.Second)
+		r, err := client.PluginConnect(ctx, &wfapi.PluginConnectRequest{Plugin: plugin})
+		cancel()
+		if err != nil {
+			return err
+		}
+		if r.Result.Return == wfapi.ConnectResult_Completed {
+			log.Infof("Plugin %s is finished", name)
+			return nil
+		}
+		if r.Result.Return != wfapi.ConnectResult_Connected || r.WorkflowData == nil {
+			return ErrProtocol
+		}
+
+		req := &wfapi.PluginCompleteRequest{
+			Plugin: plugin,
+			Result: &wfapi.Result{Return: wfapi.Result_Success},
+		}
+		step := &Step{Params: r.WorkflowData.Data, Output: Data{}}
+		step.Input, err = decodeData(name, r.WorkflowData.PluginData)
+		if err == nil {
+			err = f(step)
+		}
+		if err == nil {
+			req.WorkflowData = &wfapi.WorkflowData{}
+			req.WorkflowData.PluginData, err = encodeData(name, step.Output)
+		}
+		if err != nil {
+			log.Errorf("Plugin error: name: %v, err: %v", name, err)
+			req.Result.Return = wfapi.Result_Error
+			req.WorkflowData = &wfapi.WorkflowData{}
+		}
+
+		ctx, cancel = context.WithTimeout(context.Background(), DEFAULT_TIMEOUT*time.Second)
+		_, err = client.PluginComplete(ctx, req)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// The workflow server prefixes the schema names with the plugin name.
+func decodeData(name string, pluginData []byte) (Data, error) {
+	raw := map[string]json.RawMessage{}
+	if len(pluginData) > 0 {
+		if err := json.Unmarshal(pluginData, &raw); err != nil {
+			return nil, err
+		}
+	}
+	d := Data{}
+	for k, v := range raw {
+		d[strings.TrimPrefix(k, name+".")] = v
+	}
+	return d, nil
+}
+
+func encodeData(name string, d Data) ([]byte, error) {
+	raw := map[string]json.RawMessage{}
+	for k, v := range d {
+		raw[name+"."+k] = v
+	}
+	return json.Marshal(raw)
+}
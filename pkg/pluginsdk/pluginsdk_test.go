@@ -0,0 +1,132 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+
+package pluginsdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type content struct {
+	Content string `json:"content"`
+}
+
+// fakeClient serves the steps in pluginData, one for each PluginConnect.
+type fakeClient struct {
+	wfapi.WorkflowClient
+	pluginData [][]byte
+	completed  []*wfapi.PluginCompleteRequest
+}
+
+func (c *fakeClient) PluginConnect(ctx context.Context, in *wfapi.PluginConnectRequest, opts ...grpc.CallOption) (*wfapi.PluginConnectResponse, error) {
+	if len(c.pluginData) == 0 {
+		return &wfapi.PluginConnectResponse{Result: &wfapi.ConnectResult{Return: wfapi.ConnectResult_Completed}}, nil
+	}
+	pd := c.pluginData[0]
+	c.pluginData = c.pluginData[1:]
+	return &wfapi.PluginConnectResponse{
+		Result:       &wfapi.ConnectResult{Return: wfapi.ConnectResult_Connected},
+		WorkflowData: &wfapi.WorkflowData{Data: []byte("{}"), PluginData: pd},
+	}, nil
+}
+
+func (c *fakeClient) PluginComplete(ctx context.Context, in *wfapi.PluginCompleteRequest, opts ...grpc.CallOption) (*wfapi.Result, error) {
+	c.completed = append(c.completed, in)
+	return &wfapi.Result{Return: wfapi.Result_Success}, nil
+}
+
+func echo(step *Step) error {
+	in := content{}
+	if err := step.Input.Get("in", &in); err != nil {
+		return err
+	}
+	return step.Output.Set("out", content{Content: in.Content + "-out"})
+}
+
+func TestServe(t *testing.T) {
+	cases := []struct {
+		name         string
+		pluginData   [][]byte
+		expectResult []wfapi.Result_Return
+		expectOutput []string
+	}{
+		{
+			name:         "no step",
+			pluginData:   [][]byte{},
+			expectResult: []wfapi.Result_Return{},
+		},
+		{
+			name: "two steps",
+			pluginData: [][]byte{
+				[]byte(`{"sdk-a.in": {"content": "one"}}`),
+				[]byte(`{"sdk-a.in": {"content": "two"}}`),
+			},
+			expectResult: []wfapi.Result_Return{wfapi.Result_Success, wfapi.Result_Success},
+			expectOutput: []string{"one-out", "two-out"},
+		},
+		{
+			name: "missing input",
+			pluginData: [][]byte{
+				[]byte(`{"sdk-a.other": {}}`),
+			},
+			expectResult: []wfapi.Result_Return{wfapi.Result_Error},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &fakeClient{pluginData: tc.pluginData}
+			require.NoError(t, serve(c, "sdk-a", echo))
+			require.Equal(t, len(tc.expectResult), len(c.completed))
+			for k, req := range c.completed {
+				require.Equal(t, "sdk-a", req.Plugin.Name)
+				require.Equal(t, tc.expectResult[k], req.Result.Return)
+				if req.Result.Return != wfapi.Result_Success {
+					continue
+				}
+				out := map[string]content{}
+				require.NoError(t, json.Unmarshal(req.WorkflowData.PluginData, &out))
+				require.Equal(t, tc.expectOutput[k], out["sdk-a.out"].Content)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	envs := map[string]string{
+		ENV_PLUGIN_NAME:      "sdk-a",
+		ENV_WORKFLOW_ADDRESS: "127.0.0.1:50088",
+		ENV_WORKFLOW_CA:      "ca.pem",
+		ENV_WORKFLOW_CERT:    "client.pem",
+		ENV_WORKFLOW_KEY:     "client-key.pem",
+	}
+	for k, v := range envs {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	require.Equal(t, &Config{
+		Name:     "sdk-a",
+		Address:  "127.0.0.1:50088",
+		CAFile:   "ca.pem",
+		CertFile: "client.pem",
+		KeyFile:  "client-key.pem",
+	}, cfg)
+
+	os.Unsetenv(ENV_WORKFLOW_KEY)
+	_, err = ConfigFromEnv()
+	require.True(t, errors.Is(err, ErrConfig))
+}
@@ -0,0 +1,111 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	EVENT_BUFFER_SIZE = 256
+)
+
+// LogOutput is where the log lines of the plugins are printed.
+var LogOutput = os.Stdout
+
+// emit updates the run report with the event and sends it to every watcher.
+// A watcher which is too slow to keep up misses the event, so that it never
+// blocks the workflow.
+func (s *server) emit(ev *wfapi.Event) {
+	ev.Workflow = s.name
+	ev.Timestamp = time.Now().UnixNano()
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+	if s.report != nil {
+		s.report.update(ev)
+	}
+	for ch := range s.watchers {
+		select {
+		case ch <- ev:
+		default:
+			log.Debugf("Drop event %v for a slow watcher", ev.Type)
+		}
+	}
+}
+
+func (s *server) emitStep(t wfapi.Event_Type, k int, attempt int, err error) {
+	ev := &wfapi.Event{
+		Type:    t,
+		Step:    int32(k),
+		Plugin:  &wfapi.Plugin{Name: s.steps[k].plugin},
+		Attempt: int32(attempt),
+	}
+	if err != nil {
+		ev.ErrorCode = eputils.GetErrorCode(err)
+		ev.Message = err.Error()
+	}
+	s.emit(ev)
+}
+
+func (s *server) reportResumed(k int) {
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+	if s.report != nil {
+		s.report.Steps[k].Status = STATUS_RESUMED
+	}
+}
+
+// saveReport completes the run report with the result of the run and
+// saves it.
+func (s *server) saveReport(err error) {
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+	if s.report != nil {
+		finishReport(s.report, err)
+	}
+}
+
+// closeEvents ends the event streams of all watchers.
+func (s *server) closeEvents() {
+	s.eventMutex.Lock()
+	defer s.eventMutex.Unlock()
+	for ch := range s.watchers {
+		close(ch)
+		delete(s.watchers, ch)
+	}
+}
+
+func (s *server) WatchEvents(req *wfapi.WatchRequest, stream wfapi.Workflow_WatchEventsServer) error {
+	ch := make(chan *wfapi.Event, EVENT_BUFFER_SIZE)
+	s.eventMutex.Lock()
+	s.watchers[ch] = true
+	s.eventMutex.Unlock()
+	defer func() {
+		s.eventMutex.Lock()
+		delete(s.watchers, ch)
+		s.eventMutex.Unlock()
+	}()
+
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"fmt"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	GRAPH_DOT     = "dot"
+	GRAPH_MERMAID = "mermaid"
+)
+
+type graphNode struct {
+	id        string
+	label     string
+	container bool
+	data      bool
+}
+
+type graphEdge struct {
+	from         string
+	to           string
+	label        string
+	confidential bool
+}
+
+type graph struct {
+	name  string
+	nodes []graphNode
+	edges []graphEdge
+}
+
+// graph links every input of a step to the previous step producing it, or
+// to the init data of the workflow.
+func (s *server) graph() *graph {
+	g := &graph{name: s.name}
+	produced := map[string]string{}
+	dataNodes := map[string]string{}
+
+	for k, st := range s.steps {
+		id := fmt.Sprintf("step%d", k)
+		n := graphNode{id: id, label: st.plugin}
+		if len(st.container) > 0 {
+			n.container = true
+			n.label += "\\ncontainer: " + st.container
+		} else if len(st.command) > 0 {
+			n.label += "\\ncommand: " + st.command
+		}
+		g.nodes = append(g.nodes, n)
+
+		for _, in := range st.inputs {
+			from, has := produced[in.name]
+			if !has {
+				if _, has := s.plugin_dataattrs[in.name]; !has {
+					continue
+				}
+				if from, has = dataNodes[in.name]; !has {
+					from = fmt.Sprintf("data%d", len(dataNodes))
+					dataNodes[in.name] = from
+					g.nodes = append(g.nodes, graphNode{id: from, label: in.name, data: true})
+				}
+			}
+			g.edges = append(g.edges, graphEdge{
+				from:         from,
+				to:           id,
+				label:        in.name,
+				confidential: s.IsConfidentialData(in.name),
+			})
+		}
+		for _, out := range st.outputs {
+			produced[out.name] = id
+		}
+	}
+	return g
+}
+
+func (e graphEdge) text() string {
+	if e.confidential {
+		return e.label + " (confidential)"
+	}
+	return e.label
+}
+
+func (g *graph) dot() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.name)
+	fmt.Fprintf(&b, "  rankdir=TB;\n")
+	for _, n := range g.nodes {
+		shape := "box"
+		if n.data {
+			shape = "note"
+		} else if n.container {
+			shape = "box3d"
+		}
+		fmt.Fprintf(&b, "  %s [label=\"%s\", shape=%s];\n", n.id, n.label, shape)
+	}
+	for _, e := range g.edges {
+		style := "solid"
+		if e.confidential {
+			style = "dashed"
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%q, style=%s];\n", e.from, e.to, e.text(), style)
+	}
+	fmt.Fprintf(&b, "}\n")
+	return b.String()
+}
+
+func (g *graph) mermaid() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "flowchart TD\n")
+	for _, n := range g.nodes {
+		label := strings.ReplaceAll(n.label, "\\n", "<br/>")
+		if n.data {
+			fmt.Fprintf(&b, "  %s[/\"%s\"/]\n", n.id, label)
+		} else if n.container {
+			fmt.Fprintf(&b, "  %s[[\"%s\"]]\n", n.id, label)
+		} else {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", n.id, label)
+		}
+	}
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.confidential {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", e.from, arrow, e.text(), e.to)
+	}
+	return b.String()
+}
+
+// Graph renders the data flow between the steps of a workflow in the DOT or
+// Mermaid format. Steps running in containers are drawn as 3D boxes in DOT
+// and as subroutines in Mermaid, and confidential data as dashed edges.
+func Graph(name string, configFile string, format string) (string, error) {
+	if format != GRAPH_DOT && format != GRAPH_MERMAID {
+		log.Errorf("Unsupported graph format %s", format)
+		return "", eputils.GetError("errOutputFormat")
+	}
+	s, err := newServer(name, configFile)
+	if err != nil {
+		return "", err
+	}
+	s.loadPluginConfig()
+	if err := s.loadPluginData(); err != nil {
+		return "", err
+	}
+	g := s.graph()
+	if format == GRAPH_MERMAID {
+		return g.mermaid(), nil
+	}
+	return g.dot(), nil
+}
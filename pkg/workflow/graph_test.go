@@ -0,0 +1,72 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func Test_graph(t *testing.T) {
+	s := &server{
+		name: "graph-test",
+		steps: []step{
+			{plugin: "graph-a", inputs: []io{{"ep-params", "graph-a.ep-params"}}, outputs: []io{{"manifest", "graph-a.manifest"}}},
+			{plugin: "graph-b", container: "graph-ctn", inputs: []io{{"manifest", "graph-b.manifest"}, {"registry-auth", "graph-b.auth"}}},
+		},
+		plugin_dataattrs: map[string]dataAttr{
+			"ep-params":     {name: "ep-params"},
+			"registry-auth": {name: "registry-auth", confidential: true},
+		},
+	}
+	g := s.graph()
+
+	cases := []struct {
+		format string
+		expect []string
+	}{
+		{
+			format: GRAPH_DOT,
+			expect: []string{
+				`digraph "graph-test" {`,
+				`step1 [label="graph-b\ncontainer: graph-ctn", shape=box3d];`,
+				`data0 [label="ep-params", shape=note];`,
+				`data0 -> step0 [label="ep-params", style=solid];`,
+				`step0 -> step1 [label="manifest", style=solid];`,
+				`data1 -> step1 [label="registry-auth (confidential)", style=dashed];`,
+			},
+		},
+		{
+			format: GRAPH_MERMAID,
+			expect: []string{
+				`flowchart TD`,
+				`step1[["graph-b<br/>container: graph-ctn"]]`,
+				`data0[/"ep-params"/]`,
+				`step0 -->|"manifest"| step1`,
+				`data1 -.->|"registry-auth (confidential)"| step1`,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format, func(t *testing.T) {
+			out := g.dot()
+			if tc.format == GRAPH_MERMAID {
+				out = g.mermaid()
+			}
+			for _, line := range tc.expect {
+				require.True(t, strings.Contains(out, line), "%s not found in:\n%s", line, out)
+			}
+		})
+	}
+
+	_, err := Graph("graph-test", "workflow.yml", "svg")
+	require.Equal(t, eputils.GetError("errOutputFormat"), err)
+}
@@ -32,10 +32,10 @@ func (s *server) serve(address string) error {
 	}
 	serverCreds := credentials.NewTLS(serverTLSConfig)
 
-	grpcServer := grpc.NewServer(grpc.Creds(serverCreds))
-	wfapi.RegisterWorkflowServer(grpcServer, s)
+	s.grpcServer = grpc.NewServer(grpc.Creds(serverCreds))
+	wfapi.RegisterWorkflowServer(s.grpcServer, s)
 	go func() {
-		if err := grpcServer.Serve(lis); err != nil {
+		if err := s.grpcServer.Serve(lis); err != nil {
 			log.Errorf("failed to serve: %v", err)
 			s.errch <- err
 			s.finished <- true
@@ -52,24 +52,34 @@ func (s *server) PluginConnect(ctx context.Context, req *wfapi.PluginConnectRequ
 		res.WorkflowData = s.data
 		return res, nil
 	}
+	s.mutex.Lock()
 	st := s.getPendingStep(req.Plugin.Name)
+	s.mutex.Unlock()
 	if st == nil {
 		log.Infof("PluginConnect: no pending step needs this plugin\n")
 		res.Result.Return = wfapi.ConnectResult_Completed
 		return res, nil
 	}
-	log.Infof("PluginConnect: wait\n")
-	<-st.started
-	s.current.pending = false
+	for res.WorkflowData == nil {
+		log.Infof("PluginConnect: wait\n")
+		select {
+		case st = <-s.pluginChannel(req.Plugin.Name):
+		case <-s.done:
+			log.Infof("PluginConnect: workflow is finished\n")
+			res.Result.Return = wfapi.ConnectResult_Completed
+			return res, nil
+		}
+		res.WorkflowData = s.takeStep(req.Plugin.Name, st)
+	}
 	log.Infof("PluginConnect: plugin %v is connected", req.Plugin.Name)
-	res.WorkflowData = s.data
 	return res, nil
 }
 
 func (s *server) PluginPutLog(logstream wfapi.Workflow_PluginPutLogServer) error {
 	for {
 		if l, err := logstream.Recv(); err == nil {
-			fmt.Printf("%s", l.Log)
+			fmt.Fprintf(LogOutput, "%s", l.Log)
+			s.emit(&wfapi.Event{Type: wfapi.Event_LogLine, Step: -1, Plugin: l.Plugin, Message: l.Log})
 		} else {
 			//cancelled ?
 			log.Debugf("logstream, err :%v\n", err)
@@ -81,13 +91,35 @@ func (s *server) PluginPutLog(logstream wfapi.Workflow_PluginPutLogServer) error
 
 func (s *server) PluginComplete(ctx context.Context, req *wfapi.PluginCompleteRequest) (*wfapi.Result, error) {
 	log.Infof("PluginComplete: plugin %v, res %v", req.Plugin.Name, req.Result.Return)
+	s.mutex.Lock()
+	st, has := s.inflight[req.Plugin.Name]
+	delete(s.inflight, req.Plugin.Name)
+	if has && req.Result.Return != wfapi.Result_Success {
+		// Keep the step pending with its input data, so the plugin
+		// picks it up again if the step is retried.
+		st.pending = true
+	}
+	s.mutex.Unlock()
+	if !has {
+		log.Errorf("PluginComplete: plugin %v has no step in flight", req.Plugin.Name)
+		return &wfapi.Result{Return: wfapi.Result_Error}, nil
+	}
 	if req.Result.Return != wfapi.Result_Success {
 		log.Errorf("PluginComplete error: plugin %v, res %v", req.Plugin.Name, req.Result.Return)
-		s.errch <- eputils.GetError("errPluginComplete")
-		s.finished <- true
+		st.finished <- eputils.GetError("errPluginComplete")
+		return &wfapi.Result{Return: wfapi.Result_Success}, nil
 	}
-	s.data.PluginData = req.WorkflowData.PluginData
+	st.pluginData = req.WorkflowData.PluginData
 	r := &wfapi.Result{Return: wfapi.Result_Success}
-	s.current.finished <- true
+	st.finished <- nil
 	return r, nil
 }
+
+func (s *server) Cancel(ctx context.Context, req *wfapi.CancelRequest) (*wfapi.Result, error) {
+	log.Warningf("Cancel: workflow %v, reason: %v", s.name, req.Reason)
+	if s.cancel == nil {
+		return &wfapi.Result{Return: wfapi.Result_Error}, nil
+	}
+	s.cancel()
+	return &wfapi.Result{Return: wfapi.Result_Success}, nil
+}
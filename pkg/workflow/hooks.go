@@ -0,0 +1,165 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"fmt"
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	executor "github.com/intel/edge-conductor/pkg/executor"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	HOOK_PRE        = "pre"
+	HOOK_POST       = "post"
+	HOOK_ON_FAILURE = "on-failure"
+)
+
+// HookContext is the value passed to the executor spec of a hook, e.g.
+// {{ .Value.Plugin }}. Step is -1 for the hooks of the workflow.
+type HookContext struct {
+	Workflow string
+	Step     int
+	Plugin   string
+	Error    string
+}
+
+// hook runs either an executor spec or a plugin. The plugin of a hook is
+// served like a step, which is not part of the steps of the workflow.
+type hook struct {
+	executor string
+	step     *step
+}
+
+type hooks struct {
+	pre       []hook
+	post      []hook
+	onFailure []hook
+}
+
+func (s *server) loadHookList(hl []*wfapi.Hook) ([]hook, error) {
+	list := []hook{}
+	for _, h := range hl {
+		if (len(h.Executor) > 0) == (len(h.Plugin) > 0) {
+			log.Errorf("Hook %v must have either an executor or a plugin", h)
+			return nil, eputils.GetError("errHookSpec")
+		}
+		if len(h.Executor) > 0 {
+			list = append(list, hook{executor: h.Executor})
+			continue
+		}
+		inputs := []io{}
+		for _, in := range h.Input {
+			inputs = append(inputs, io{
+				name:       in.Name,
+				schemaName: h.Plugin + "." + in.Schema,
+			})
+		}
+		st := &step{
+			plugin:   h.Plugin,
+			inputs:   inputs,
+			pending:  true,
+			finished: make(chan error),
+		}
+		s.hookSteps = append(s.hookSteps, st)
+		list = append(list, hook{step: st})
+	}
+	return list, nil
+}
+
+func (s *server) loadHooks(h *wfapi.Hooks) (hooks, error) {
+	var err error
+	hs := hooks{}
+	if h == nil {
+		return hs, nil
+	}
+	if hs.pre, err = s.loadHookList(h.Pre); err != nil {
+		return hs, err
+	}
+	if hs.post, err = s.loadHookList(h.Post); err != nil {
+		return hs, err
+	}
+	if hs.onFailure, err = s.loadHookList(h.OnFailure); err != nil {
+		return hs, err
+	}
+	return hs, nil
+}
+
+func (hc *HookContext) String() string {
+	if hc.Step < 0 {
+		return "workflow " + hc.Workflow
+	}
+	return fmt.Sprintf("step %d: %s", hc.Step, hc.Plugin)
+}
+
+func (s *server) hookContext(k int) *HookContext {
+	hc := &HookContext{Workflow: s.name, Step: k}
+	if k >= 0 {
+		hc.Plugin = s.steps[k].plugin
+	}
+	return hc
+}
+
+func (s *server) runHook(h hook, hc *HookContext) error {
+	if h.step == nil {
+		log.Infof("run hook executor: %s", h.executor)
+		epparams := &pluginapi.EpParams{}
+		if err := epparams.UnmarshalBinary(s.data.Data); err != nil {
+			return eputils.GetError("errUnmarshalData")
+		}
+		return executor.Run(h.executor, epparams, hc)
+	}
+
+	s.mutex.Lock()
+	pdata, err := s.prepareInputs(h.step)
+	if err == nil {
+		h.step.pluginData, err = pdata.MarshalBinary()
+	}
+	s.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	log.Infof("run hook plugin: %s", h.step.plugin)
+	err = s.dispatchStep(h.step)
+	s.mutex.Lock()
+	// A hook runs only once, so the plugin does not wait for it again.
+	h.step.pending = false
+	s.mutex.Unlock()
+	return err
+}
+
+// runHooks runs the hooks in order, and stops at the first failure.
+func (s *server) runHooks(kind string, hl []hook, hc *HookContext) error {
+	for _, h := range hl {
+		if err := s.runHook(h, hc); err != nil {
+			log.Errorf("%s hook of %v failed: %v", kind, hc, err)
+			return eputils.GetError("errHookFailed")
+		}
+	}
+	return nil
+}
+
+// runFailureHooks runs all the on-failure hooks. Their own failures are
+// only logged, so that the original error is returned. The hooks do not run
+// when the workflow is cancelled.
+func (s *server) runFailureHooks(hl []hook, hc *HookContext, err error) {
+	if s.isCancelled() {
+		if len(hl) > 0 {
+			log.Warningf("Skip %s hooks of %v, the workflow is cancelled", HOOK_ON_FAILURE, hc)
+		}
+		return
+	}
+	hc.Error = err.Error()
+	for _, h := range hl {
+		if herr := s.runHook(h, hc); herr != nil {
+			log.Warningf("%s hook of %v failed: %v", HOOK_ON_FAILURE, hc, herr)
+		}
+	}
+}
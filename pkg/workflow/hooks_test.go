@@ -0,0 +1,210 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	executor "github.com/intel/edge-conductor/pkg/executor"
+
+	mpatch "github.com/undefinedlabs/go-mpatch"
+)
+
+func Test_loadHooks(t *testing.T) {
+	cases := []struct {
+		name        string
+		hooks       *wfapi.Hooks
+		expectHooks int
+		expectSteps int
+		expectError error
+	}{
+		{
+			name:  "no hooks",
+			hooks: nil,
+		},
+		{
+			name: "executor and plugin hooks",
+			hooks: &wfapi.Hooks{
+				Pre:       []*wfapi.Hook{{Executor: "pre.yml"}},
+				Post:      []*wfapi.Hook{{Plugin: "hook-a"}},
+				OnFailure: []*wfapi.Hook{{Executor: "diag.yml"}, {Plugin: "hook-b", Input: []*wfapi.HookInputItems0{{Name: "d", Schema: "s"}}}},
+			},
+			expectHooks: 4,
+			expectSteps: 2,
+		},
+		{
+			name: "both executor and plugin",
+			hooks: &wfapi.Hooks{
+				Pre: []*wfapi.Hook{{Executor: "pre.yml", Plugin: "hook-a"}},
+			},
+			expectError: eputils.GetError("errHookSpec"),
+		},
+		{
+			name: "neither executor nor plugin",
+			hooks: &wfapi.Hooks{
+				Post: []*wfapi.Hook{{}},
+			},
+			expectError: eputils.GetError("errHookSpec"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{}
+			hs, err := s.loadHooks(tc.hooks)
+			require.Equal(t, tc.expectError, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tc.expectHooks, len(hs.pre)+len(hs.post)+len(hs.onFailure))
+			require.Equal(t, tc.expectSteps, len(s.hookSteps))
+			for _, st := range s.hookSteps {
+				require.True(t, st.pending)
+				if st.plugin == "hook-b" {
+					require.Equal(t, []io{{name: "d", schemaName: "hook-b.s"}}, st.inputs)
+				}
+			}
+		})
+	}
+}
+
+func Test_runHooks(t *testing.T) {
+	j, err := loadJournal("test-hooks")
+	require.NoError(t, err)
+	defer os.RemoveAll(journalFilePath("test-hooks"))
+
+	cases := []struct {
+		name          string
+		stepHooks     *wfapi.Hooks
+		workflowHooks *wfapi.Hooks
+		failExecutor  string
+		stepResults   []string
+		hookResults   []string
+		expectCalls   []string
+		expectError   error
+	}{
+		{
+			name: "pre and post hooks",
+			stepHooks: &wfapi.Hooks{
+				Pre:  []*wfapi.Hook{{Executor: "step-pre.yml"}},
+				Post: []*wfapi.Hook{{Executor: "step-post.yml"}},
+			},
+			workflowHooks: &wfapi.Hooks{
+				Pre:       []*wfapi.Hook{{Executor: "wf-pre.yml"}},
+				Post:      []*wfapi.Hook{{Executor: "wf-post.yml"}},
+				OnFailure: []*wfapi.Hook{{Executor: "wf-failure.yml"}},
+			},
+			stepResults: []string{"ok"},
+			expectCalls: []string{"wf-pre.yml", "step-pre.yml", "step-post.yml", "wf-post.yml"},
+		},
+		{
+			name: "pre hook fails",
+			stepHooks: &wfapi.Hooks{
+				Pre:       []*wfapi.Hook{{Executor: "step-pre.yml"}},
+				OnFailure: []*wfapi.Hook{{Executor: "step-failure.yml"}},
+			},
+			workflowHooks: &wfapi.Hooks{
+				OnFailure: []*wfapi.Hook{{Executor: "wf-failure.yml"}},
+			},
+			failExecutor: "step-pre.yml",
+			stepResults:  []string{},
+			expectCalls:  []string{"step-pre.yml", "step-failure.yml: E001.059", "wf-failure.yml: E001.059"},
+			expectError:  eputils.GetError("errHookFailed"),
+		},
+		{
+			name: "step fails",
+			stepHooks: &wfapi.Hooks{
+				Post:      []*wfapi.Hook{{Executor: "step-post.yml"}},
+				OnFailure: []*wfapi.Hook{{Executor: "step-failure.yml"}, {Plugin: "hook-plugin"}},
+			},
+			stepResults: []string{"fail"},
+			hookResults: []string{"ok"},
+			expectCalls: []string{"step-failure.yml: E001.024"},
+			expectError: eputils.GetError("errPluginComplete"),
+		},
+		{
+			name: "failure hook fails",
+			stepHooks: &wfapi.Hooks{
+				OnFailure: []*wfapi.Hook{{Plugin: "hook-plugin"}, {Executor: "step-failure.yml"}},
+			},
+			stepResults: []string{"fail"},
+			hookResults: []string{"fail"},
+			expectCalls: []string{"step-failure.yml: E001.024"},
+			expectError: eputils.GetError("errPluginComplete"),
+		},
+		{
+			name: "post plugin hook",
+			stepHooks: &wfapi.Hooks{
+				Post: []*wfapi.Hook{{Plugin: "hook-plugin"}},
+			},
+			stepResults: []string{"ok"},
+			hookResults: []string{"ok"},
+			expectCalls: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mutex sync.Mutex
+			calls := []string{}
+			patch, err := mpatch.PatchMethod(executor.Run, func(specFile string, epparams *pluginapi.EpParams, value interface{}) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				hc := value.(*HookContext)
+				require.Equal(t, "test-hooks", hc.Workflow)
+				if len(hc.Error) > 0 {
+					calls = append(calls, specFile+": "+strings.SplitN(hc.Error, ":", 2)[0])
+				} else {
+					calls = append(calls, specFile)
+				}
+				if specFile == tc.failExecutor {
+					return errTest
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			defer unpatch(t, patch)
+
+			s := &server{
+				name:             "test-hooks",
+				steps:            []step{{plugin: "hooks-a", pending: true, finished: make(chan error)}},
+				inflight:         map[string]*step{},
+				plugin_data:      eputils.SchemaMapData{},
+				plugin_dataattrs: map[string]dataAttr{},
+				data:             &wfapi.WorkflowData{Data: []byte("{}")},
+				journal:          j,
+			}
+			s.steps[0].hooks, err = s.loadHooks(tc.stepHooks)
+			require.NoError(t, err)
+			s.hooks, err = s.loadHooks(tc.workflowHooks)
+			require.NoError(t, err)
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				simulatePlugin(t, s, "hooks-a", 0, tc.stepResults)
+			}()
+			go func() {
+				defer wg.Done()
+				simulatePlugin(t, s, "hook-plugin", 0, tc.hookResults)
+			}()
+			err = s.run()
+			wg.Wait()
+			require.Equal(t, tc.expectError, err)
+			require.Equal(t, tc.expectCalls, calls)
+		})
+	}
+}
@@ -0,0 +1,271 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"io/ioutil"
+	"os"
+	fpath "path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RUNTIME_JOURNAL_DIR = "runtime/journal"
+)
+
+// Resume makes Start skip the steps which were completed by the previous
+// run of the same workflow, as long as their inputs have not changed.
+var Resume = false
+
+type journalStep struct {
+	Index        int                        `json:"index"`
+	Plugin       string                     `json:"plugin"`
+	InputDigest  string                     `json:"inputDigest"`
+	OutputDigest string                     `json:"outputDigest"`
+	Outputs      map[string]json.RawMessage `json:"outputs,omitempty"`
+	Completed    time.Time                  `json:"completed"`
+}
+
+type journal struct {
+	Workflow string        `json:"workflow"`
+	Steps    []journalStep `json:"steps"`
+	filepath string
+}
+
+func journalFilePath(name string) string {
+	return fpath.Join(RUNTIME_JOURNAL_DIR, name+".json")
+}
+
+func loadJournal(name string) (*journal, error) {
+	j := &journal{
+		Workflow: name,
+		Steps:    []journalStep{},
+		filepath: journalFilePath(name),
+	}
+	if !eputils.FileExists(j.filepath) {
+		return j, nil
+	}
+	buf, err := ioutil.ReadFile(j.filepath)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(buf, j); err != nil {
+		log.Warningf("Journal %s is corrupted, ignore it: %v", j.filepath, err)
+		j.Steps = []journalStep{}
+	}
+	j.Workflow = name
+	return j, nil
+}
+
+func (j *journal) save() error {
+	if err := os.MkdirAll(RUNTIME_JOURNAL_DIR, os.FileMode(0700)); err != nil {
+		return err
+	}
+	buf, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return eputils.GetError("errMarshal")
+	}
+	return ioutil.WriteFile(j.filepath, buf, os.FileMode(0600))
+}
+
+// completedStep returns the journal record of step index k if the step was
+// completed by the plugin with the same inputs.
+func (j *journal) completedStep(k int, plugin string, inputDigest string) *journalStep {
+	for i := range j.Steps {
+		js := &j.Steps[i]
+		if js.Index == k {
+			if js.Plugin != plugin || js.InputDigest != inputDigest {
+				return nil
+			}
+			return js
+		}
+	}
+	return nil
+}
+
+// record replaces the record of the same step index, or appends it. Steps
+// of a parallel workflow may complete out of order.
+func (j *journal) record(js journalStep) error {
+	for i := range j.Steps {
+		if j.Steps[i].Index == js.Index {
+			j.Steps[i] = js
+			return j.save()
+		}
+	}
+	j.Steps = append(j.Steps, js)
+	return j.save()
+}
+
+// truncate removes the records of step index k and all later steps.
+func (j *journal) truncate(k int) error {
+	steps := []journalStep{}
+	for _, js := range j.Steps {
+		if js.Index < k {
+			steps = append(steps, js)
+		}
+	}
+	j.Steps = steps
+	return j.save()
+}
+
+// remove removes the records of the given step indexes.
+func (j *journal) remove(stale map[int]bool) error {
+	steps := []journalStep{}
+	for _, js := range j.Steps {
+		if !stale[js.Index] {
+			steps = append(steps, js)
+		}
+	}
+	j.Steps = steps
+	return j.save()
+}
+
+func digest(data ...[]byte) string {
+	h := sha256.New()
+	for _, d := range data {
+		h.Write(d)
+		// Separator to avoid ambiguous concatenation.
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+func (s *server) inputDigest(pdata eputils.SchemaMapData) (string, error) {
+	buf, err := pdata.MarshalBinary()
+	if err != nil {
+		log.Errorf("pdata marshal error: %v", err)
+		return "", eputils.GetError("errMarshalPdata")
+	}
+	return digest(s.data.Data, buf), nil
+}
+
+func (s *server) journalRecord(k int, st *step, inputDigest string, pdata eputils.SchemaMapData) (journalStep, error) {
+	js := journalStep{
+		Index:       k,
+		Plugin:      st.plugin,
+		InputDigest: inputDigest,
+		Outputs:     map[string]json.RawMessage{},
+		Completed:   time.Now(),
+	}
+	outdata := eputils.SchemaMapData{}
+	for _, out := range st.outputs {
+		v, has := pdata[out.schemaName]
+		if !has {
+			continue
+		}
+		outdata[out.schemaName] = v
+		if s.IsConfidentialData(out.name) {
+			continue
+		}
+		buf, err := v.MarshalBinary()
+		if err != nil {
+			return js, eputils.GetError("errMarshal")
+		}
+		js.Outputs[out.name] = buf
+	}
+	buf, err := outdata.MarshalBinary()
+	if err != nil {
+		return js, eputils.GetError("errMarshalPdata")
+	}
+	js.OutputDigest = digest(buf)
+	return js, nil
+}
+
+// restoreOutputs rebuilds the output data of a completed step from the
+// journal. Confidential outputs are never written to the journal, so the
+// steps producing them cannot be restored.
+func (s *server) restoreOutputs(st *step, js *journalStep) (eputils.SchemaMapData, bool) {
+	pdata := eputils.SchemaMapData{}
+	for _, out := range st.outputs {
+		buf, has := js.Outputs[out.name]
+		if !has {
+			return nil, false
+		}
+		v := eputils.SchemaStructNew(out.schemaName)
+		if err := v.UnmarshalBinary(buf); err != nil {
+			log.Warningf("Restore output %s of %s error: %v", out.name, st.plugin, err)
+			return nil, false
+		}
+		pdata[out.schemaName] = v
+	}
+	buf, err := pdata.MarshalBinary()
+	if err != nil || digest(buf) != js.OutputDigest {
+		return nil, false
+	}
+	return pdata, true
+}
+
+// resumeSteps marks the steps completed in the previous run as resumed and
+// restores their outputs. A step is resumed if it was completed with the same
+// inputs and all its dependencies are resumed, so the steps of a parallel
+// workflow completed alongside a failed step are not run again. Steps skipped
+// by their when expression are never journaled, so they do not stop the
+// resume and are skipped again. The records of the steps which run again are
+// removed. It must be called before the plugins are started, so that no
+// plugin waits for a resumed step.
+func (s *server) resumeSteps() error {
+	if !Resume {
+		return s.journal.truncate(0)
+	}
+	// done marks the steps resumed or skipped by their when expression.
+	done := make([]bool, len(s.steps))
+	stale := map[int]bool{}
+	for k := range s.steps {
+		st := &s.steps[k]
+		if !s.isStepReady(k, done) {
+			log.Infof("Step %d: %s depends on a step which runs again, run it again", k, st.plugin)
+			stale[k] = true
+			continue
+		}
+		run, err := s.evalWhen(st.when)
+		if err != nil {
+			return err
+		}
+		if !run {
+			done[k] = true
+			continue
+		}
+		pdata, err := s.prepareInputs(st)
+		if err != nil {
+			return err
+		}
+		inputDigest, err := s.inputDigest(pdata)
+		if err != nil {
+			return err
+		}
+		js := s.journal.completedStep(k, st.plugin, inputDigest)
+		if js == nil {
+			log.Infof("Step %d: %s is not completed with the same inputs, run it again", k, st.plugin)
+			stale[k] = true
+			continue
+		}
+		outdata, ok := s.restoreOutputs(st, js)
+		if !ok {
+			log.Infof("Outputs of step %d: %s cannot be restored, run it again", k, st.plugin)
+			stale[k] = true
+			continue
+		}
+		if err := s.updateOutputs(st, outdata); err != nil {
+			return err
+		}
+		log.Infof("Skip step %d: %s, completed at %v", k, st.plugin, js.Completed)
+		st.pending = false
+		st.resumed = true
+		done[k] = true
+	}
+	if len(stale) == 0 {
+		log.Infof("All steps of workflow %s are completed", s.name)
+		return nil
+	}
+	return s.journal.remove(stale)
+}
@@ -0,0 +1,244 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func newJournalTestServer(t *testing.T) *server {
+	eputils.AddSchemaStruct("journal-a.in", func() eputils.SchemaStruct { return &epapiplugins.Filecontent{} })
+	eputils.AddSchemaStruct("journal-a.out", func() eputils.SchemaStruct { return &epapiplugins.Filecontent{} })
+	eputils.AddSchemaStruct("journal-b.in", func() eputils.SchemaStruct { return &epapiplugins.Filecontent{} })
+	eputils.AddSchemaStruct("journal-b.out", func() eputils.SchemaStruct { return &epapiplugins.Filecontent{} })
+
+	j, err := loadJournal("journal-test")
+	require.NoError(t, err)
+	s := &server{
+		name: "journal-test",
+		steps: []step{
+			{
+				plugin:  "journal-a",
+				pending: true,
+				inputs:  []io{{name: "journal-init", schemaName: "journal-a.in"}},
+				outputs: []io{{name: "journal-mid", schemaName: "journal-a.out"}},
+			},
+			{
+				plugin:  "journal-b",
+				pending: true,
+				inputs:  []io{{name: "journal-mid", schemaName: "journal-b.in"}},
+				outputs: []io{{name: "journal-secret", schemaName: "journal-b.out"}},
+			},
+		},
+		plugin_data: eputils.SchemaMapData{},
+		plugin_dataattrs: map[string]dataAttr{
+			"journal-init":   {name: "journal-init", value: "content: init\n"},
+			"journal-secret": {name: "journal-secret", confidential: true},
+		},
+		data:    &wfapi.WorkflowData{Data: []byte("{}")},
+		journal: j,
+	}
+	require.NoError(t, s.loadDependencies())
+	return s
+}
+
+func completeJournalStep(t *testing.T, s *server, k int, content string) {
+	st := &s.steps[k]
+	pdata, err := s.prepareInputs(st)
+	require.NoError(t, err)
+	inputDigest, err := s.inputDigest(pdata)
+	require.NoError(t, err)
+	pdata[st.outputs[0].schemaName] = &epapiplugins.Filecontent{Content: content}
+	require.NoError(t, s.updateOutputs(st, pdata))
+	js, err := s.journalRecord(k, st, inputDigest, pdata)
+	require.NoError(t, err)
+	require.NoError(t, s.journal.record(js))
+}
+
+func Test_journal(t *testing.T) {
+	defer os.RemoveAll(journalFilePath("journal-test"))
+
+	cases := []struct {
+		name         string
+		resume       bool
+		completed    int
+		changeInit   bool
+		expectResume []bool
+		expectSteps  int
+	}{
+		{
+			name:         "no resume",
+			resume:       false,
+			completed:    1,
+			expectResume: []bool{false, false},
+			expectSteps:  0,
+		},
+		{
+			name:         "resume completed step",
+			resume:       true,
+			completed:    1,
+			expectResume: []bool{true, false},
+			expectSteps:  1,
+		},
+		{
+			name:         "resume with changed input",
+			resume:       true,
+			completed:    1,
+			changeInit:   true,
+			expectResume: []bool{false, false},
+			expectSteps:  0,
+		},
+		{
+			name:         "confidential output cannot be restored",
+			resume:       true,
+			completed:    2,
+			expectResume: []bool{true, false},
+			expectSteps:  1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.RemoveAll(journalFilePath("journal-test"))
+			s := newJournalTestServer(t)
+			for k := 0; k < tc.completed; k++ {
+				completeJournalStep(t, s, k, "output")
+			}
+
+			Resume = tc.resume
+			defer func() { Resume = false }()
+			s = newJournalTestServer(t)
+			require.Equal(t, tc.completed, len(s.journal.Steps))
+			if tc.changeInit {
+				s.plugin_dataattrs["journal-init"] = dataAttr{name: "journal-init", value: "content: changed\n"}
+			}
+			require.NoError(t, s.resumeSteps())
+			for k, st := range s.steps {
+				require.Equal(t, tc.expectResume[k], st.resumed, "step %d", k)
+				require.Equal(t, !tc.expectResume[k], st.pending, "step %d", k)
+			}
+			require.Equal(t, tc.expectSteps, len(s.journal.Steps))
+			if tc.expectResume[0] {
+				out, has := s.plugin_data["journal-mid"]
+				require.True(t, has)
+				require.Equal(t, "output", out.(*epapiplugins.Filecontent).Content)
+			}
+		})
+	}
+}
+
+func Test_journalSkippedStep(t *testing.T) {
+	defer os.RemoveAll(journalFilePath("journal-test"))
+	os.RemoveAll(journalFilePath("journal-test"))
+
+	skipped := step{
+		plugin:  "journal-skip",
+		pending: true,
+		when:    "false",
+		inputs:  []io{{name: "journal-none", schemaName: "journal-a.in"}},
+	}
+	newServer := func() *server {
+		s := newJournalTestServer(t)
+		s.steps = append([]step{skipped}, s.steps...)
+		require.NoError(t, s.loadDependencies())
+		return s
+	}
+	s := newServer()
+	completeJournalStep(t, s, 1, "output")
+
+	Resume = true
+	defer func() { Resume = false }()
+	s = newServer()
+	require.NoError(t, s.resumeSteps())
+	for k, expect := range []bool{false, true, false} {
+		require.Equal(t, expect, s.steps[k].resumed, "step %d", k)
+	}
+	require.Equal(t, 1, len(s.journal.Steps))
+	out, has := s.plugin_data["journal-mid"]
+	require.True(t, has)
+	require.Equal(t, "output", out.(*epapiplugins.Filecontent).Content)
+}
+
+func Test_journalParallel(t *testing.T) {
+	defer os.RemoveAll(journalFilePath("journal-test"))
+	eputils.AddSchemaStruct("journal-c.in", func() eputils.SchemaStruct { return &epapiplugins.Filecontent{} })
+	eputils.AddSchemaStruct("journal-c.out", func() eputils.SchemaStruct { return &epapiplugins.Filecontent{} })
+
+	// journal-c runs in parallel with journal-a and journal-b, which
+	// depends on journal-a.
+	newServer := func() *server {
+		s := newJournalTestServer(t)
+		s.parallel = true
+		s.steps[1].outputs = []io{{name: "journal-end", schemaName: "journal-b.out"}}
+		s.steps = append(s.steps, step{
+			plugin:  "journal-c",
+			pending: true,
+			inputs:  []io{{name: "journal-init", schemaName: "journal-c.in"}},
+			outputs: []io{{name: "journal-other", schemaName: "journal-c.out"}},
+		})
+		require.NoError(t, s.loadDependencies())
+		return s
+	}
+
+	cases := []struct {
+		name         string
+		completed    []int
+		expectResume []bool
+	}{
+		{
+			name:         "step fails alongside a completed step",
+			completed:    []int{2},
+			expectResume: []bool{false, false, true},
+		},
+		{
+			name:         "dependent step fails",
+			completed:    []int{0, 2},
+			expectResume: []bool{true, false, true},
+		},
+		{
+			name:         "parallel step fails",
+			completed:    []int{0, 1},
+			expectResume: []bool{true, true, false},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.RemoveAll(journalFilePath("journal-test"))
+			s := newServer()
+			for _, k := range tc.completed {
+				completeJournalStep(t, s, k, "output")
+			}
+
+			Resume = true
+			defer func() { Resume = false }()
+			s = newServer()
+			require.NoError(t, s.resumeSteps())
+			var resumed []int
+			for k, st := range s.steps {
+				require.Equal(t, tc.expectResume[k], st.resumed, "step %d", k)
+				require.Equal(t, !tc.expectResume[k], st.pending, "step %d", k)
+				if st.resumed {
+					resumed = append(resumed, k)
+				}
+			}
+			// Only the records of the steps which run again are removed.
+			var journaled []int
+			for _, js := range s.journal.Steps {
+				journaled = append(journaled, js.Index)
+			}
+			require.ElementsMatch(t, resumed, journaled)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"fmt"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"os/exec"
+	"reflect"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	PLAN_ERROR   = "ERROR"
+	PLAN_WARNING = "WARNING"
+)
+
+type PlanIssue struct {
+	Step    int
+	Plugin  string
+	Level   string
+	Message string
+}
+
+type PlanData struct {
+	Name       string
+	SchemaName string
+	Source     string
+}
+
+type PlanStep struct {
+	Plugin    string
+	Container string
+	Command   string
+	When      string
+	Inputs    []PlanData
+	Outputs   []PlanData
+}
+
+type PlanResult struct {
+	Workflow string
+	Steps    []PlanStep
+	Issues   []PlanIssue
+}
+
+type producer struct {
+	step       int
+	schemaName string
+	used       bool
+}
+
+func (p *PlanResult) addIssue(k int, plugin, level, format string, args ...interface{}) {
+	p.Issues = append(p.Issues, PlanIssue{
+		Step:    k,
+		Plugin:  plugin,
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (p *PlanResult) HasError() bool {
+	for _, i := range p.Issues {
+		if i.Level == PLAN_ERROR {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *PlanResult) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Workflow: %s\n", p.Workflow)
+	for k, st := range p.Steps {
+		if len(st.Container) > 0 {
+			fmt.Fprintf(&b, "  [%d] %s (container: %s)\n", k, st.Plugin, st.Container)
+		} else if len(st.Command) > 0 {
+			fmt.Fprintf(&b, "  [%d] %s (command: %s)\n", k, st.Plugin, st.Command)
+		} else {
+			fmt.Fprintf(&b, "  [%d] %s\n", k, st.Plugin)
+		}
+		if len(st.When) > 0 {
+			fmt.Fprintf(&b, "      when %s\n", st.When)
+		}
+		for _, in := range st.Inputs {
+			fmt.Fprintf(&b, "      in  %s as %s <- %s\n", in.Name, in.SchemaName, in.Source)
+		}
+		for _, out := range st.Outputs {
+			fmt.Fprintf(&b, "      out %s as %s\n", out.Name, out.SchemaName)
+		}
+	}
+	if len(p.Issues) == 0 {
+		fmt.Fprintf(&b, "No issue found.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Issues:\n")
+	for _, i := range p.Issues {
+		fmt.Fprintf(&b, "  %s [%d] %s: %s\n", i.Level, i.Step, i.Plugin, i.Message)
+	}
+	return b.String()
+}
+
+func schemaType(schemaName string) reflect.Type {
+	return reflect.TypeOf(eputils.SchemaStructNew(schemaName))
+}
+
+func (s *server) isInitData(name string) bool {
+	for _, data := range s.workflow.Spec.Data {
+		if data.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) hasContainer(name string) bool {
+	for _, ctn := range s.workflow.Spec.Containers {
+		if ctn.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) checkSchema(p *PlanResult, k int, st *step, d io) bool {
+	if eputils.SchemaStructExists(d.schemaName) {
+		return true
+	}
+	if isBuiltInPlugin(st.plugin) {
+		p.addIssue(k, st.plugin, PLAN_ERROR, "schema %s of %s is not provided by the plugin", d.schemaName, d.name)
+	} else {
+		p.addIssue(k, st.plugin, PLAN_WARNING, "schema %s of %s cannot be checked for an external plugin", d.schemaName, d.name)
+	}
+	return false
+}
+
+// plan resolves the inputs of every step against the outputs of the
+// previous steps and the init data, without starting any plugin.
+func (s *server) plan() *PlanResult {
+	p := &PlanResult{Workflow: s.name}
+	produced := map[string]*producer{}
+
+	for k := range s.steps {
+		st := &s.steps[k]
+		ps := PlanStep{Plugin: st.plugin, Container: st.container, Command: st.command, When: st.when}
+
+		if len(st.container) > 0 {
+			if !s.hasContainer(st.container) {
+				p.addIssue(k, st.plugin, PLAN_ERROR, "container %s is not defined in the workflow", st.container)
+			}
+			if len(st.command) > 0 {
+				p.addIssue(k, st.plugin, PLAN_ERROR, "both container and command are set")
+			}
+		} else if len(st.command) > 0 {
+			if _, err := exec.LookPath(st.command); err != nil {
+				p.addIssue(k, st.plugin, PLAN_ERROR, "command %s is not found", st.command)
+			}
+		} else if !isBuiltInPlugin(st.plugin) {
+			p.addIssue(k, st.plugin, PLAN_ERROR, "unknown plugin")
+		}
+
+		for _, in := range st.inputs {
+			pd := PlanData{Name: in.name, SchemaName: in.schemaName}
+			known := s.checkSchema(p, k, st, in)
+			if prod, has := produced[in.name]; has {
+				prod.used = true
+				pd.Source = fmt.Sprintf("step %d: %s", prod.step, s.steps[prod.step].plugin)
+				if known && eputils.SchemaStructExists(prod.schemaName) &&
+					schemaType(prod.schemaName) != schemaType(in.schemaName) {
+					p.addIssue(k, st.plugin, PLAN_ERROR, "type mismatch: %s is produced as %s (%v) but consumed as %s (%v)",
+						in.name, prod.schemaName, schemaType(prod.schemaName), in.schemaName, schemaType(in.schemaName))
+				}
+			} else if data, has := s.plugin_dataattrs[in.name]; has {
+				pd.Source = "data"
+				if known {
+					v := eputils.SchemaStructNew(in.schemaName)
+					if err := eputils.LoadSchemaStructFromYaml(v, data.value); err != nil {
+						p.addIssue(k, st.plugin, PLAN_ERROR, "data %s cannot be loaded as %s: %v", in.name, in.schemaName, err)
+					}
+				}
+			} else {
+				pd.Source = "missing"
+				p.addIssue(k, st.plugin, PLAN_ERROR, "input %s is not found in previous step's output or init data", in.name)
+			}
+			ps.Inputs = append(ps.Inputs, pd)
+		}
+
+		for _, out := range st.outputs {
+			s.checkSchema(p, k, st, out)
+			if prod, has := produced[out.name]; has && !prod.used && !s.isInitData(out.name) {
+				p.addIssue(prod.step, s.steps[prod.step].plugin, PLAN_WARNING,
+					"output %s is overwritten by step %d before it is used", out.name, k)
+			}
+			produced[out.name] = &producer{step: k, schemaName: out.schemaName}
+			ps.Outputs = append(ps.Outputs, PlanData{Name: out.name, SchemaName: out.schemaName})
+		}
+		p.Steps = append(p.Steps, ps)
+	}
+
+	for k, st := range s.steps {
+		for _, out := range st.outputs {
+			prod := produced[out.name]
+			if prod.step == k && !prod.used && !s.isInitData(out.name) {
+				p.addIssue(k, st.plugin, PLAN_WARNING, "output %s is not used by any later step", out.name)
+			}
+		}
+	}
+	return p
+}
+
+// Plan checks the data flow of a workflow without executing any plugin.
+func Plan(name string, configFile string) (*PlanResult, error) {
+	s, err := newServer(name, configFile)
+	if err != nil {
+		return nil, err
+	}
+	s.loadPluginConfig()
+	if err := s.loadPluginData(); err != nil {
+		return nil, err
+	}
+	p := s.plan()
+	if p.HasError() {
+		log.Errorf("Workflow %s has errors", name)
+		return p, eputils.GetError("errWorkflowPlan")
+	}
+	return p, nil
+}
@@ -0,0 +1,117 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	epplugins "github.com/intel/edge-conductor/pkg/epplugins"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func Test_plan(t *testing.T) {
+	eputils.AddSchemaStruct("plan-a.files", func() eputils.SchemaStruct { return &epapiplugins.Files{} })
+	eputils.AddSchemaStruct("plan-a.images", func() eputils.SchemaStruct { return &epapiplugins.Images{} })
+	eputils.AddSchemaStruct("plan-b.files", func() eputils.SchemaStruct { return &epapiplugins.Files{} })
+	eputils.AddSchemaStruct("plan-b.images", func() eputils.SchemaStruct { return &epapiplugins.Images{} })
+	epplugins.PluginList = append(epplugins.PluginList, "plan-a", "plan-b")
+
+	cases := []struct {
+		name         string
+		steps        []step
+		expectIssues []string
+	}{
+		{
+			name: "plan ok",
+			steps: []step{
+				{plugin: "plan-a", inputs: []io{{"init-files", "plan-a.files"}}, outputs: []io{{"files", "plan-a.files"}}},
+				{plugin: "plan-b", inputs: []io{{"files", "plan-b.files"}}},
+			},
+			expectIssues: []string{},
+		},
+		{
+			name: "missing input",
+			steps: []step{
+				{plugin: "plan-b", inputs: []io{{"files", "plan-b.files"}}},
+			},
+			expectIssues: []string{"ERROR [0] plan-b: input files is not found"},
+		},
+		{
+			name: "type mismatch",
+			steps: []step{
+				{plugin: "plan-a", outputs: []io{{"files", "plan-a.files"}}},
+				{plugin: "plan-b", inputs: []io{{"files", "plan-b.images"}}},
+			},
+			expectIssues: []string{"ERROR [1] plan-b: type mismatch"},
+		},
+		{
+			name: "unknown plugin and schema",
+			steps: []step{
+				{plugin: "plan-unknown", inputs: []io{{"init-files", "plan-unknown.files"}}},
+			},
+			expectIssues: []string{
+				"ERROR [0] plan-unknown: unknown plugin",
+				"WARNING [0] plan-unknown: schema plan-unknown.files",
+			},
+		},
+		{
+			name: "unknown container",
+			steps: []step{
+				{plugin: "plan-a", container: "plan-container"},
+			},
+			expectIssues: []string{"ERROR [0] plan-a: container plan-container is not defined"},
+		},
+		{
+			name: "command plugin",
+			steps: []step{
+				{plugin: "plan-command", command: "/bin/sh"},
+				{plugin: "plan-missing", command: "./no-such-plugin"},
+			},
+			expectIssues: []string{"ERROR [1] plan-missing: command ./no-such-plugin is not found"},
+		},
+		{
+			name: "unused output",
+			steps: []step{
+				{plugin: "plan-a", outputs: []io{{"images", "plan-a.images"}}},
+				{plugin: "plan-a", outputs: []io{{"images", "plan-a.images"}}},
+			},
+			expectIssues: []string{
+				"WARNING [0] plan-a: output images is overwritten by step 1",
+				"WARNING [1] plan-a: output images is not used",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{
+				name: "plan-test",
+				workflow: &wfapi.Workflow{
+					Spec: &wfapi.WorkflowSpec{
+						Data: []*wfapi.WorkflowSpecDataItems0{{Name: "init-files"}},
+					},
+				},
+				steps: tc.steps,
+				plugin_dataattrs: map[string]dataAttr{
+					"init-files": {name: "init-files", value: "files: []\n"},
+				},
+			}
+			p := s.plan()
+			require.Equal(t, len(tc.expectIssues), len(p.Issues), p.Report())
+			report := p.Report()
+			for _, issue := range tc.expectIssues {
+				require.True(t, strings.Contains(report, issue), "%s not found in:\n%s", issue, report)
+			}
+			require.Equal(t, len(tc.expectIssues) > 0 && strings.HasPrefix(tc.expectIssues[0], PLAN_ERROR), p.HasError())
+		})
+	}
+}
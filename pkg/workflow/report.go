@@ -0,0 +1,149 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"encoding/json"
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+	"io/ioutil"
+	"os"
+	fpath "path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RUNTIME_REPORT_DIR = "runtime/report"
+
+	STATUS_PENDING   = "pending"
+	STATUS_RUNNING   = "running"
+	STATUS_COMPLETED = "completed"
+	STATUS_FAILED    = "failed"
+	STATUS_RESUMED   = "resumed"
+	STATUS_CANCELLED = "cancelled"
+	STATUS_SKIPPED   = "skipped"
+)
+
+type StepReport struct {
+	Index           int        `json:"index"`
+	Plugin          string     `json:"plugin"`
+	Status          string     `json:"status"`
+	Attempts        int        `json:"attempts"`
+	StartTime       *time.Time `json:"startTime,omitempty"`
+	EndTime         *time.Time `json:"endTime,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds"`
+	ErrorCode       string     `json:"errorCode,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// RunReport is the machine-readable result of a workflow run.
+type RunReport struct {
+	Workflow        string       `json:"workflow"`
+	Status          string       `json:"status"`
+	ExitStatus      int          `json:"exitStatus"`
+	StartTime       time.Time    `json:"startTime"`
+	EndTime         time.Time    `json:"endTime"`
+	DurationSeconds float64      `json:"durationSeconds"`
+	ErrorCode       string       `json:"errorCode,omitempty"`
+	Error           string       `json:"error,omitempty"`
+	Steps           []StepReport `json:"steps"`
+}
+
+func ReportFilePath(name string) string {
+	return fpath.Join(RUNTIME_REPORT_DIR, name+".json")
+}
+
+func newRunReport(name string, steps []step) *RunReport {
+	r := &RunReport{
+		Workflow:  name,
+		Status:    STATUS_RUNNING,
+		StartTime: time.Now(),
+		Steps:     []StepReport{},
+	}
+	for k, st := range steps {
+		r.Steps = append(r.Steps, StepReport{
+			Index:  k,
+			Plugin: st.plugin,
+			Status: STATUS_PENDING,
+		})
+	}
+	return r
+}
+
+// update applies a step event to the report of the step.
+func (r *RunReport) update(ev *wfapi.Event) {
+	if ev.Type == wfapi.Event_LogLine || int(ev.Step) < 0 || int(ev.Step) >= len(r.Steps) {
+		return
+	}
+	sr := &r.Steps[ev.Step]
+	t := time.Unix(0, ev.Timestamp)
+	switch ev.Type {
+	case wfapi.Event_StepStarted:
+		sr.Status = STATUS_RUNNING
+		sr.Attempts = int(ev.Attempt)
+		if sr.StartTime == nil {
+			sr.StartTime = &t
+		}
+		return
+	case wfapi.Event_StepCompleted:
+		sr.Status = STATUS_COMPLETED
+		sr.ErrorCode = ""
+		sr.Error = ""
+	case wfapi.Event_StepSkipped:
+		sr.Status = STATUS_SKIPPED
+		return
+	case wfapi.Event_StepFailed:
+		sr.Status = STATUS_FAILED
+		if ev.ErrorCode == eputils.GetErrorCode(eputils.GetError("errWorkflowCancelled")) {
+			sr.Status = STATUS_CANCELLED
+		}
+		sr.ErrorCode = ev.ErrorCode
+		sr.Error = ev.Message
+	}
+	sr.EndTime = &t
+	if sr.StartTime != nil {
+		sr.DurationSeconds = t.Sub(*sr.StartTime).Seconds()
+	}
+}
+
+func (r *RunReport) finish(err error) {
+	r.EndTime = time.Now()
+	r.DurationSeconds = r.EndTime.Sub(r.StartTime).Seconds()
+	if err != nil {
+		r.Status = STATUS_FAILED
+		if err == eputils.GetError("errWorkflowCancelled") {
+			r.Status = STATUS_CANCELLED
+		}
+		r.ExitStatus = 1
+		r.ErrorCode = eputils.GetErrorCode(err)
+		r.Error = err.Error()
+	} else {
+		r.Status = STATUS_COMPLETED
+		r.ExitStatus = 0
+	}
+}
+
+func (r *RunReport) save() error {
+	if err := os.MkdirAll(RUNTIME_REPORT_DIR, os.FileMode(0700)); err != nil {
+		return err
+	}
+	buf, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return eputils.GetError("errMarshal")
+	}
+	return ioutil.WriteFile(ReportFilePath(r.Workflow), buf, os.FileMode(0600))
+}
+
+// finishReport completes the report with the result of the run and saves it.
+func finishReport(r *RunReport, err error) {
+	r.finish(err)
+	if serr := r.save(); serr != nil {
+		log.Warningf("Failed to save report of workflow %s, %v", r.Workflow, serr)
+	}
+}
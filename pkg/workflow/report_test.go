@@ -0,0 +1,76 @@
+/*
+* Copyright (c) 2022 Intel Corporation.
+*
+* SPDX-License-Identifier: Apache-2.0
+*
+ */
+package workflow
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	eputils "github.com/intel/edge-conductor/pkg/eputils"
+)
+
+func Test_report(t *testing.T) {
+	defer os.RemoveAll(ReportFilePath("report-test"))
+
+	s := &server{
+		name:     "report-test",
+		steps:    []step{{plugin: "report-a"}, {plugin: "report-b"}, {plugin: "report-c"}},
+		watchers: map[chan *wfapi.Event]bool{},
+	}
+	s.report = newRunReport(s.name, s.steps)
+	watcher := make(chan *wfapi.Event, EVENT_BUFFER_SIZE)
+	s.watchers[watcher] = true
+
+	s.emitStep(wfapi.Event_StepStarted, 0, 1, nil)
+	s.emitStep(wfapi.Event_StepCompleted, 0, 1, nil)
+	s.emitStep(wfapi.Event_StepStarted, 1, 1, nil)
+	s.emitStep(wfapi.Event_StepFailed, 1, 1, eputils.GetError("errPluginComplete"))
+	s.emitStep(wfapi.Event_StepStarted, 1, 2, nil)
+	s.emitStep(wfapi.Event_StepFailed, 1, 2, eputils.GetError("errStepExhausted"))
+	s.emit(&wfapi.Event{Type: wfapi.Event_LogLine, Step: -1, Plugin: &wfapi.Plugin{Name: "report-b"}, Message: "log"})
+	s.saveReport(eputils.GetError("errStepExhausted"))
+	s.closeEvents()
+
+	events := []wfapi.Event_Type{}
+	for ev := range watcher {
+		require.Equal(t, "report-test", ev.Workflow)
+		events = append(events, ev.Type)
+	}
+	require.Equal(t, []wfapi.Event_Type{
+		wfapi.Event_StepStarted, wfapi.Event_StepCompleted,
+		wfapi.Event_StepStarted, wfapi.Event_StepFailed,
+		wfapi.Event_StepStarted, wfapi.Event_StepFailed,
+		wfapi.Event_LogLine,
+	}, events)
+
+	buf, err := ioutil.ReadFile(ReportFilePath("report-test"))
+	require.NoError(t, err)
+	r := RunReport{}
+	require.NoError(t, json.Unmarshal(buf, &r))
+	require.Equal(t, STATUS_FAILED, r.Status)
+	require.Equal(t, 1, r.ExitStatus)
+	require.Equal(t, "E001.055", r.ErrorCode)
+	require.Equal(t, 3, len(r.Steps))
+
+	require.Equal(t, STATUS_COMPLETED, r.Steps[0].Status)
+	require.Equal(t, 1, r.Steps[0].Attempts)
+	require.NotNil(t, r.Steps[0].StartTime)
+	require.NotNil(t, r.Steps[0].EndTime)
+
+	require.Equal(t, "report-b", r.Steps[1].Plugin)
+	require.Equal(t, STATUS_FAILED, r.Steps[1].Status)
+	require.Equal(t, 2, r.Steps[1].Attempts)
+	require.Equal(t, "E001.055", r.Steps[1].ErrorCode)
+
+	require.Equal(t, STATUS_PENDING, r.Steps[2].Status)
+	require.Nil(t, r.Steps[2].StartTime)
+}
@@ -7,17 +7,26 @@
 package workflow
 
 import (
+	"context"
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	certmgr "github.com/intel/edge-conductor/pkg/certmgr"
 	epplugins "github.com/intel/edge-conductor/pkg/epplugins"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
 	docker "github.com/intel/edge-conductor/pkg/eputils/docker"
 	plugin "github.com/intel/edge-conductor/pkg/plugin"
+	pluginsdk "github.com/intel/edge-conductor/pkg/pluginsdk"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	fpath "path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -38,27 +47,57 @@ type io struct {
 }
 
 type step struct {
-	plugin    string
-	container string
-	pending   bool
-	started   chan bool
-	finished  chan bool
-	inputs    []io
-	outputs   []io
+	plugin     string
+	container  string
+	command    string
+	args       []string
+	pending    bool
+	handoff    bool
+	finished   chan error
+	resumed    bool
+	inputs     []io
+	outputs    []io
+	depends    []string
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	when       string
+	deps       []int
+	hooks      hooks
+	pluginData []byte
+}
+
+type stepResult struct {
+	index int
+	err   error
 }
 
 type server struct {
 	wfapi.UnimplementedWorkflowServer
 	name             string
+	address          string
 	workflow         *wfapi.Workflow
 	steps            []step
-	current          *step
+	parallel         bool
+	inflight         map[string]*step
+	dispatch         map[string]chan *step
+	mutex            sync.Mutex
 	plugin_data      eputils.SchemaMapData
 	plugin_dataattrs map[string]dataAttr
 	containers       wfapi.Containers
 	finished         chan bool
+	done             chan bool
 	data             *wfapi.WorkflowData
 	errch            chan error
+	journal          *journal
+	hooks            hooks
+	hookSteps        []*step
+	eventMutex       sync.Mutex
+	watchers         map[chan *wfapi.Event]bool
+	report           *RunReport
+	ctx              context.Context
+	cancel           context.CancelFunc
+	grpcServer       *grpc.Server
 }
 
 func isBuiltInPlugin(name string) bool {
@@ -97,10 +136,35 @@ func (s *server) setInitData() error {
 	return nil
 }
 
-func (s *server) getPendingStep(name string) *step {
+// pluginChannel returns the channel which hands the started steps to the
+// plugin. A plugin waits for any of its pending steps, since hooks may run
+// the plugin out of the order of the steps.
+func (s *server) pluginChannel(name string) chan *step {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.dispatch == nil {
+		s.dispatch = map[string]chan *step{}
+	}
+	if _, has := s.dispatch[name]; !has {
+		s.dispatch[name] = make(chan *step)
+	}
+	return s.dispatch[name]
+}
+
+// allSteps returns the steps of the workflow, followed by the steps which
+// run the plugins of hooks.
+func (s *server) allSteps() []*step {
+	steps := []*step{}
 	for k := range s.steps {
-		if s.steps[k].plugin == name && s.steps[k].pending {
-			return &s.steps[k]
+		steps = append(steps, &s.steps[k])
+	}
+	return append(steps, s.hookSteps...)
+}
+
+func (s *server) getPendingStep(name string) *step {
+	for _, st := range s.allSteps() {
+		if st.plugin == name && st.pending {
+			return st
 		}
 	}
 	return nil
@@ -150,9 +214,11 @@ func (s *server) loadPluginData() error {
 
 func (s *server) loadPluginConfig() {
 	for _, p := range s.workflow.Spec.Plugins {
-		for k := range s.steps {
-			if s.steps[k].plugin == p.Name {
-				s.steps[k].container = p.Container
+		for _, st := range s.allSteps() {
+			if st.plugin == p.Name {
+				st.container = p.Container
+				st.command = p.Command
+				st.args = p.Args
 			}
 		}
 	}
@@ -161,7 +227,7 @@ func (s *server) loadPluginConfig() {
 func (s *server) loadContainers() {
 	for _, ctn := range s.workflow.Spec.Containers {
 		needToRun := false
-		for _, st := range s.steps {
+		for _, st := range s.allSteps() {
 			if ctn.Name == st.container {
 				ctn.Args = append(ctn.Args, st.plugin)
 				needToRun = true
@@ -173,11 +239,19 @@ func (s *server) loadContainers() {
 	}
 }
 
-func run_container(ctn *wfapi.ContainersItems0) error {
+func convertContainer(ctn *wfapi.ContainersItems0) (*pluginapi.ContainersItems0, error) {
 	c := &pluginapi.ContainersItems0{}
 	if err := eputils.ConvertSchemaStruct(&ctn, c); err != nil {
 		log.Errorf("Convert containers data error: %v\n", err)
-		return eputils.GetError("errConvContainers")
+		return nil, eputils.GetError("errConvContainers")
+	}
+	return c, nil
+}
+
+func run_container(ctn *wfapi.ContainersItems0) error {
+	c, err := convertContainer(ctn)
+	if err != nil {
+		return err
 	}
 	log.Infof("remove container %s\n", c.Name)
 	if err := docker.DockerRemove(c); err != nil {
@@ -190,11 +264,55 @@ func run_container(ctn *wfapi.ContainersItems0) error {
 	return nil
 }
 
+// run_command starts an executable plugin. The plugin finds the address of
+// the workflow server and its client certificate in the environment.
+func (s *server) run_command(name string, command string, args []string) error {
+	certBundle, _, err := certmgr.GetCertBundleByName("workflow", "client")
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(s.context(), command, args...)
+	cmd.Env = append(os.Environ(),
+		pluginsdk.ENV_PLUGIN_NAME+"="+name,
+		pluginsdk.ENV_WORKFLOW_ADDRESS+"="+s.address,
+		pluginsdk.ENV_WORKFLOW_CA+"="+certBundle.Ca.Cert,
+		pluginsdk.ENV_WORKFLOW_CERT+"="+certBundle.Client.Cert,
+		pluginsdk.ENV_WORKFLOW_KEY+"="+certBundle.Client.Key,
+	)
+	cmd.Stdout = LogOutput
+	cmd.Stderr = os.Stderr
+	log.Infof("start plugin command %s for %s\n", command, name)
+	if err := cmd.Start(); err != nil {
+		log.Errorf("Failed to start plugin command %s: %v", command, err)
+		return eputils.GetError("errPluginCommand")
+	}
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			log.Errorf("Plugin command %s exited: %v", command, err)
+			err = eputils.GetError("errPluginCommand")
+		}
+		s.errch <- err
+	}()
+	return nil
+}
+
 func (s *server) startPlugins() error {
-	for _, st := range s.steps {
-		if len(st.container) == 0 && isBuiltInPlugin(st.plugin) {
+	commands := map[string]bool{}
+	for _, st := range s.allSteps() {
+		if len(st.container) > 0 {
+			continue
+		}
+		if len(st.command) > 0 {
+			if !commands[st.plugin] {
+				commands[st.plugin] = true
+				if err := s.run_command(st.plugin, st.command, st.args); err != nil {
+					return err
+				}
+			}
+		} else if isBuiltInPlugin(st.plugin) {
 			log.Debugf("start plugin: %v\n", st.plugin)
-			if err := plugin.StartPlugin(st.plugin, s.errch); err != nil {
+			if err := plugin.StartPlugin(s.context(), st.plugin, s.errch); err != nil {
 				return err
 			}
 		}
@@ -235,105 +353,507 @@ func (s *server) loadSteps() error {
 				schemaName: st.Name + "." + out.Schema,
 			})
 		}
-		s.steps = append(s.steps, step{
+		newStep := step{
 			plugin:   st.Name,
 			inputs:   inputs,
 			outputs:  outputs,
+			depends:  st.Depends,
+			when:     st.When,
 			pending:  true,
-			started:  make(chan bool),
-			finished: make(chan bool),
-		})
+			finished: make(chan error),
+		}
+		if err := newStep.loadLimits(st); err != nil {
+			return err
+		}
+		var err error
+		if newStep.hooks, err = s.loadHooks(st.Hooks); err != nil {
+			return err
+		}
+		s.steps = append(s.steps, newStep)
 	}
-	if len(s.steps) > 0 {
-		s.current = &s.steps[0]
+	var err error
+	if s.hooks, err = s.loadHooks(wf.Hooks); err != nil {
+		return err
+	}
+	s.parallel = wf.Parallel
+	if err := s.loadDependencies(); err != nil {
+		return err
 	}
 	log.Debugf("steps: %v\n", s.steps)
 	return nil
 }
 
-func (s *server) run() error {
-	for k, st := range s.steps {
-		pdata := eputils.SchemaMapData{}
-		log.Debugf("Prepare plugin data\n")
+func (st *step) loadLimits(wfStep *wfapi.WorkflowSpecWorkflowsItems0StepsItems0) error {
+	var err error
+	if len(wfStep.Timeout) > 0 {
+		if st.timeout, err = time.ParseDuration(wfStep.Timeout); err != nil || st.timeout < 0 {
+			log.Errorf("Step %s has an invalid timeout %s", wfStep.Name, wfStep.Timeout)
+			return eputils.GetError("errStepLimits")
+		}
+	}
+	if len(wfStep.Backoff) > 0 {
+		if st.backoff, err = time.ParseDuration(wfStep.Backoff); err != nil || st.backoff < 0 {
+			log.Errorf("Step %s has an invalid backoff %s", wfStep.Name, wfStep.Backoff)
+			return eputils.GetError("errStepLimits")
+		}
+	}
+	if wfStep.Retries != nil {
+		st.retries = int(*wfStep.Retries)
+	}
+	return nil
+}
+
+func hasData(ios []io, name string) bool {
+	for _, d := range ios {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadDependencies sets the steps which must complete before each step.
+// Steps of a sequential workflow depend on their previous step. Steps of a
+// parallel workflow depend on the steps declared in "depends", on the steps
+// producing or consuming the same data and on the previous step of the same
+// plugin, since one plugin only serves one step at a time.
+func (s *server) loadDependencies() error {
+	for k := range s.steps {
+		st := &s.steps[k]
+		st.deps = []int{}
+		if !s.parallel {
+			if k > 0 {
+				st.deps = append(st.deps, k-1)
+			}
+			continue
+		}
+		deps := map[int]bool{}
+		for _, name := range st.depends {
+			found := false
+			for j := k - 1; j >= 0; j-- {
+				if s.steps[j].plugin == name {
+					deps[j] = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				log.Errorf("Step %s depends on %s, which is not a previous step", st.plugin, name)
+				return eputils.GetError("errStepDepends")
+			}
+		}
+		for j := k - 1; j >= 0; j-- {
+			if s.steps[j].plugin == st.plugin {
+				deps[j] = true
+				break
+			}
+		}
 		for _, in := range st.inputs {
-			if v, has := s.plugin_data[in.name]; has {
+			for j := k - 1; j >= 0; j-- {
+				if hasData(s.steps[j].outputs, in.name) {
+					deps[j] = true
+					break
+				}
+			}
+		}
+		for _, out := range st.outputs {
+			for j := k - 1; j >= 0; j-- {
+				if hasData(s.steps[j].inputs, out.name) {
+					deps[j] = true
+				}
+				if hasData(s.steps[j].outputs, out.name) {
+					deps[j] = true
+					break
+				}
+			}
+		}
+		for j := 0; j < k; j++ {
+			if deps[j] {
+				st.deps = append(st.deps, j)
+			}
+		}
+		log.Debugf("step %d: %s depends on %v", k, st.plugin, st.deps)
+	}
+	return nil
+}
+
+func (s *server) prepareInputs(st *step) (eputils.SchemaMapData, error) {
+	pdata := eputils.SchemaMapData{}
+	log.Debugf("Prepare plugin data\n")
+	for _, in := range st.inputs {
+		if v, has := s.plugin_data[in.name]; has {
+			if s.IsConfidentialData(in.name) {
+				log.Debugf("Input %s, schema: %s, value: *confidential data*\n", in.name, in.schemaName)
+			} else {
+				log.Debugf("Input %s, schema: %s, value: %v\n", in.name, in.schemaName, v)
+			}
+			pdata[in.schemaName] = v
+		} else {
+			pdata[in.schemaName] = eputils.SchemaStructNew(in.schemaName)
+			if data, has := s.plugin_dataattrs[in.name]; has {
+				if err := eputils.LoadSchemaStructFromYaml(pdata[in.schemaName], data.value); err != nil {
+					log.Warningf("Load plugin data [%s] from init data error, %v\n", in.name, err)
+					return nil, eputils.GetError("errPluginData")
+				}
 				if s.IsConfidentialData(in.name) {
-					log.Debugf("Input %s, schema: %s, value: *confidential data*\n", in.name, in.schemaName)
+					log.Debugf("init plugin_data: [%s]: *confidential data*", in.name)
 				} else {
-					log.Debugf("Input %s, schema: %s, value: %v\n", in.name, in.schemaName, v)
+					log.Debugf("init plugin_data: [%s]: %s", in.name, data.value)
 				}
-				pdata[in.schemaName] = v
 			} else {
-				pdata[in.schemaName] = eputils.SchemaStructNew(in.schemaName)
-				if data, has := s.plugin_dataattrs[in.name]; has {
-					if err := eputils.LoadSchemaStructFromYaml(pdata[in.schemaName], data.value); err != nil {
-						log.Warningf("Load plugin data [%s] from init data error, %v\n", in.name, err)
-						return eputils.GetError("errPluginData")
-					}
-					if s.IsConfidentialData(in.name) {
-						log.Debugf("init plugin_data: [%s]: *confidential data*", in.name)
-					} else {
-						log.Debugf("init plugin_data: [%s]: %s", in.name, data.value)
-					}
-				} else {
-					log.Errorf("Cannot find schema %s in previous step's output or init data \n", in.name)
-					return eputils.GetError("errPreviousSchema")
+				log.Errorf("Cannot find schema %s in previous step's output or init data \n", in.name)
+				return nil, eputils.GetError("errPreviousSchema")
+			}
+		}
+	}
+	return pdata, nil
+}
+
+func (s *server) updateOutputs(st *step, pdata eputils.SchemaMapData) error {
+	for _, out := range st.outputs {
+		if v, has := pdata[out.schemaName]; has {
+			if s.IsConfidentialData(out.name) {
+				log.Debugf("Output %s as %s, value: *confidential data*\n", out.name, out.schemaName)
+			} else {
+				log.Debugf("Output %s as %s, value: %v\n", out.name, out.schemaName, v)
+			}
+			s.plugin_data[out.name] = v
+		} else {
+			log.Errorf("Cannot find schema %s in output\n", out.schemaName)
+			return eputils.GetError("errSchemaOutData")
+		}
+		if data, has := s.plugin_dataattrs[out.name]; has {
+			if !data.confidential {
+				log.Infof("Update data to file: %s\n", data.filepath)
+				yml, err := eputils.SchemaStructToYaml(pdata[out.schemaName])
+				if err != nil {
+					return err
+				}
+				if err := ioutil.WriteFile(data.filepath, []byte(yml), data.filemode); err != nil {
+					return err
 				}
 			}
 		}
-		json, err := pdata.MarshalBinary()
-		if err != nil {
-			log.Errorf("pdata marshal error: %v", err)
-			return eputils.GetError("errMarshalPdata")
+	}
+	return nil
+}
+
+// prepareStep resolves the inputs of step k and saves them as the plugin
+// data of the step.
+func (s *server) prepareStep(k int) (eputils.SchemaMapData, string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	st := &s.steps[k]
+	pdata, err := s.prepareInputs(st)
+	if err != nil {
+		return nil, "", err
+	}
+	inputDigest, err := s.inputDigest(pdata)
+	if err != nil {
+		return nil, "", err
+	}
+	json, err := pdata.MarshalBinary()
+	if err != nil {
+		log.Errorf("pdata marshal error: %v", err)
+		return nil, "", eputils.GetError("errMarshalPdata")
+	}
+	st.pluginData = json
+	return pdata, inputDigest, nil
+}
+
+// completeStep updates the workflow data with the outputs of step k.
+func (s *server) completeStep(k int, pdata eputils.SchemaMapData, inputDigest string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	st := &s.steps[k]
+	if err := pdata.UnmarshalBinary(st.pluginData); err != nil {
+		log.Errorf("Plugin return pdata error, err: %v, json dump: %v", err, string(st.pluginData))
+		return eputils.GetError("errPluginReturn")
+	}
+	if err := s.updateOutputs(st, pdata); err != nil {
+		return err
+	}
+	js, err := s.journalRecord(k, st, inputDigest, pdata)
+	if err != nil {
+		return err
+	}
+	if err := s.journal.record(js); err != nil {
+		log.Warningf("Failed to record step %d: %s in journal, %v", k, st.plugin, err)
+	}
+	log.Debugf("PluginComplete: plugin_data: %v", s.plugin_data)
+	return nil
+}
+
+// context returns the context of the run, which is cancelled when the
+// workflow is cancelled.
+func (s *server) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+func (s *server) isCancelled() bool {
+	return s.context().Err() != nil
+}
+
+// dispatchStep hands the step to its plugin and waits for the plugin to
+// complete it, for at most the timeout of the step or until the workflow is
+// cancelled.
+func (s *server) dispatchStep(st *step) error {
+	var timer <-chan time.Time
+	if st.timeout > 0 {
+		t := time.NewTimer(st.timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+	ch := s.pluginChannel(st.plugin)
+	s.mutex.Lock()
+	st.handoff = true
+	s.mutex.Unlock()
+	var err error
+	select {
+	case ch <- st:
+	case <-timer:
+		err = eputils.GetError("errStepExhausted")
+	case <-s.context().Done():
+		err = eputils.GetError("errWorkflowCancelled")
+	}
+	if err == nil {
+		select {
+		case err := <-st.finished:
+			return err
+		case <-timer:
+			err = eputils.GetError("errStepExhausted")
+		case <-s.context().Done():
+			err = eputils.GetError("errWorkflowCancelled")
 		}
-		s.data.PluginData = json
+	}
+	s.mutex.Lock()
+	completing := false
+	if s.inflight[st.plugin] == st {
+		// The late completion of this attempt is rejected and the
+		// plugin picks the step up again when it reconnects.
+		delete(s.inflight, st.plugin)
+		st.pending = true
+	} else if !st.handoff {
+		// The plugin took the step and is completing it right now.
+		completing = true
+	}
+	// If the plugin received the step but has not taken it yet, it
+	// refuses the step and waits again.
+	st.handoff = false
+	s.mutex.Unlock()
+	if completing {
+		return <-st.finished
+	}
+	return err
+}
 
+// takeStep hands a step received from the plugin channel to the plugin, and
+// returns its workflow data. It returns nil if the dispatch of the step was
+// given up after the step was received, e.g. for its timeout.
+func (s *server) takeStep(name string, st *step) *wfapi.WorkflowData {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !st.handoff {
+		return nil
+	}
+	st.handoff = false
+	st.pending = false
+	s.inflight[name] = st
+	return &wfapi.WorkflowData{Data: s.data.Data, PluginData: st.pluginData}
+}
+
+// runStep runs step k with its hooks. The on-failure hooks of the step run
+// before the error is returned.
+func (s *server) runStep(k int) error {
+	err := s.execStep(k)
+	if err != nil {
+		s.runFailureHooks(s.steps[k].hooks.onFailure, s.hookContext(k), err)
+	}
+	return err
+}
+
+// evalWhen evaluates the when expression of a step with the ep-params init
+// data, e.g. {{ eq .Kitconfig.Cluster.Provider "capi" }}. Like in executor
+// specs, the braces may be escaped as \{\{ \}\}, so that the expression is
+// not rendered with the workflow config. A step without expression runs.
+func (s *server) evalWhen(when string) (bool, error) {
+	if len(when) == 0 {
+		return true, nil
+	}
+	c := strings.ReplaceAll(when, `\{\{`, `{{`)
+	c = strings.ReplaceAll(c, `\}\}`, `}}`)
+	epparams := &pluginapi.EpParams{}
+	if err := epparams.UnmarshalBinary(s.data.Data); err != nil {
+		return false, eputils.GetError("errUnmarshalData")
+	}
+	str, err := eputils.StringTemplateConvertWithParams(c, epparams)
+	if err != nil {
+		log.Errorf("Failed to evaluate when expression %s: %v", when, err)
+		return false, eputils.GetError("errStepWhen")
+	}
+	run, err := strconv.ParseBool(strings.TrimSpace(str))
+	if err != nil {
+		log.Errorf("When expression %s is %s, not a boolean", when, str)
+		return false, eputils.GetError("errStepWhen")
+	}
+	return run, nil
+}
+
+func (s *server) execStep(k int) error {
+	run, err := s.evalWhen(s.steps[k].when)
+	if err != nil {
+		s.emitStep(wfapi.Event_StepFailed, k, 0, err)
+		return err
+	}
+	if !run {
+		log.Infof("skip plugin: %v, when: %s is false", s.steps[k].plugin, s.steps[k].when)
+		s.emitStep(wfapi.Event_StepSkipped, k, 0, nil)
+		return nil
+	}
+	pdata, inputDigest, err := s.prepareStep(k)
+	if err != nil {
+		s.emitStep(wfapi.Event_StepFailed, k, 0, err)
+		return err
+	}
+	st := &s.steps[k]
+	if err := s.runHooks(HOOK_PRE, st.hooks.pre, s.hookContext(k)); err != nil {
+		s.emitStep(wfapi.Event_StepFailed, k, 0, err)
+		return err
+	}
+	backoff := st.backoff
+	attempt := 1
+	for ; ; attempt++ {
 		log.Infof("kickoff plugin: %v", st.plugin)
-		s.current = &s.steps[k]
-		s.current.started <- true
-		<-s.current.finished
+		s.emitStep(wfapi.Event_StepStarted, k, attempt, nil)
+		err = s.dispatchStep(st)
+		if err == nil {
+			break
+		}
+		if attempt > st.retries || s.isCancelled() {
+			log.Errorf("Step %d: %s failed after %d attempts: %v", k, st.plugin, attempt, err)
+			if st.retries > 0 && !s.isCancelled() {
+				err = eputils.GetError("errStepExhausted")
+			}
+			s.emitStep(wfapi.Event_StepFailed, k, attempt, err)
+			return err
+		}
+		s.emitStep(wfapi.Event_StepFailed, k, attempt, err)
+		log.Warningf("Step %d: %s failed: %v, retry in %v", k, st.plugin, err, backoff)
+		if err := s.sleep(backoff); err != nil {
+			return err
+		}
+		backoff *= 2
+	}
+	if err := s.completeStep(k, pdata, inputDigest); err != nil {
+		s.emitStep(wfapi.Event_StepFailed, k, attempt, err)
+		return err
+	}
+	if err := s.runHooks(HOOK_POST, st.hooks.post, s.hookContext(k)); err != nil {
+		s.emitStep(wfapi.Event_StepFailed, k, attempt, err)
+		return err
+	}
+	s.emitStep(wfapi.Event_StepCompleted, k, attempt, nil)
+	return nil
+}
 
-		if err := pdata.UnmarshalBinary(s.data.PluginData); err != nil {
-			log.Errorf("Plugin return pdata error, err: %v, json dump: %v", err, string(s.data.PluginData))
-			return eputils.GetError("errPluginReturn")
+// sleep waits for the backoff before the next attempt of a step, unless the
+// workflow is cancelled.
+func (s *server) sleep(d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-s.context().Done():
+		return eputils.GetError("errWorkflowCancelled")
+	}
+}
+
+func (s *server) isStepReady(k int, completed []bool) bool {
+	for _, d := range s.steps[k].deps {
+		if !completed[d] {
+			return false
 		}
-		for _, out := range st.outputs {
-			if v, has := pdata[out.schemaName]; has {
-				if s.IsConfidentialData(out.name) {
-					log.Debugf("Output %s as %s, value: *confidential data*\n", out.name, out.schemaName)
-				} else {
-					log.Debugf("Output %s as %s, value: %v\n", out.name, out.schemaName, v)
-				}
-				s.plugin_data[out.name] = v
-			} else {
-				log.Errorf("Cannot find schema %s in output\n", out.schemaName)
-				return eputils.GetError("errSchemaOutData")
+	}
+	return true
+}
+
+// run runs the steps with the hooks of the workflow. The on-failure hooks
+// run before the error is returned. Then the plugins still waiting for a
+// step, e.g. for a hook which did not run, are released.
+func (s *server) run() error {
+	if s.done != nil {
+		defer close(s.done)
+	}
+	hc := s.hookContext(-1)
+	err := s.runHooks(HOOK_PRE, s.hooks.pre, hc)
+	if err == nil {
+		err = s.runSteps()
+	}
+	if err == nil {
+		err = s.runHooks(HOOK_POST, s.hooks.post, hc)
+	}
+	if err != nil {
+		s.runFailureHooks(s.hooks.onFailure, hc, err)
+		return err
+	}
+	log.Infof("workflow finished")
+	return nil
+}
+
+// runSteps kicks off every step whose dependencies are completed, so
+// independent steps of a parallel workflow run at the same time.
+func (s *server) runSteps() error {
+	results := make(chan stepResult, len(s.steps))
+	launched := make([]bool, len(s.steps))
+	completed := make([]bool, len(s.steps))
+	for k, st := range s.steps {
+		if st.resumed {
+			log.Infof("skip plugin: %v, completed in previous run", st.plugin)
+			s.reportResumed(k)
+			launched[k] = true
+			completed[k] = true
+		}
+	}
+	running := 0
+	for {
+		for k := range s.steps {
+			if !launched[k] && s.isStepReady(k, completed) {
+				launched[k] = true
+				running++
+				go func(k int) {
+					results <- stepResult{index: k, err: s.runStep(k)}
+				}(k)
 			}
-			if data, has := s.plugin_dataattrs[out.name]; has {
-				if !data.confidential {
-					log.Infof("Update data to file: %s\n", data.filepath)
-					yml, err := eputils.SchemaStructToYaml(pdata[out.schemaName])
-					if err != nil {
-						return err
-					}
-					if err := ioutil.WriteFile(data.filepath, []byte(yml), data.filemode); err != nil {
-						return err
-					}
+		}
+		if running == 0 {
+			break
+		}
+		r := <-results
+		running--
+		if r.err != nil {
+			if s.isCancelled() {
+				// The other steps stop as well, wait for them.
+				for ; running > 0; running-- {
+					<-results
 				}
 			}
+			return r.err
 		}
-		log.Debugf("PluginComplete: plugin_data: %v", s.plugin_data)
+		completed[r.index] = true
 	}
-	log.Infof("workflow finished")
 	return nil
 }
 
-func Start(name string, address string, configFile string) error {
+func newServer(name string, configFile string) (*server, error) {
 	log.Infof("load workflow config file %v", configFile)
 	wf := wfapi.Workflow{}
 	err := eputils.LoadSchemaStructFromYamlFile(&wf, configFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	s := &server{
@@ -342,14 +862,39 @@ func Start(name string, address string, configFile string) error {
 		steps:            []step{},
 		plugin_data:      eputils.SchemaMapData{},
 		plugin_dataattrs: map[string]dataAttr{},
+		inflight:         map[string]*step{},
 		finished:         make(chan bool),
+		done:             make(chan bool),
 		data:             &wfapi.WorkflowData{},
 		errch:            make(chan error),
+		watchers:         map[chan *wfapi.Event]bool{},
 	}
 
 	if err := s.loadSteps(); err != nil {
+		return nil, err
+	}
+	s.report = newRunReport(name, s.steps)
+	return s, nil
+}
+
+// Start runs the workflow until it finishes or ctx is cancelled, and saves
+// the report of the run to ReportFilePath(name).
+func Start(ctx context.Context, name string, address string, configFile string) error {
+	s, err := newServer(name, configFile)
+	if err != nil {
+		finishReport(newRunReport(name, nil), err)
 		return err
 	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	defer s.cancel()
+	err = s.start(address)
+	s.saveReport(err)
+	s.closeEvents()
+	return err
+}
+
+func (s *server) start(address string) error {
+	var err error
 	if len(s.steps) <= 0 {
 		log.Infof("No step in this workflow to run.")
 		return nil
@@ -362,6 +907,13 @@ func Start(name string, address string, configFile string) error {
 	if err := s.setInitData(); err != nil {
 		return err
 	}
+	if s.journal, err = loadJournal(s.name); err != nil {
+		return err
+	}
+	if err := s.resumeSteps(); err != nil {
+		return err
+	}
+	s.address = address
 	if err := s.serve(address); err != nil {
 		return err
 	}
@@ -382,7 +934,35 @@ func Start(name string, address string, configFile string) error {
 			return err
 		case err = <-s.errch:
 			break
+		case <-s.context().Done():
+			err = s.context().Err()
 		}
 	}
+	if s.isCancelled() {
+		log.Warningf("Workflow %s is cancelled", s.name)
+		// Wait for the current steps to stop, so that the report
+		// records them.
+		<-s.done
+		s.shutdown()
+		return eputils.GetError("errWorkflowCancelled")
+	}
 	return err
 }
+
+// shutdown removes the plugin containers and stops the gRPC server of a
+// cancelled workflow.
+func (s *server) shutdown() {
+	for _, ctn := range s.containers {
+		c, err := convertContainer(ctn)
+		if err != nil {
+			continue
+		}
+		log.Infof("remove container %s\n", c.Name)
+		if err := docker.DockerRemove(c); err != nil {
+			log.Warningf("Failed to remove container %s: %v", c.Name, err)
+		}
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+}
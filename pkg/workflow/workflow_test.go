@@ -7,6 +7,7 @@
 package workflow
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -14,15 +15,19 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"text/template"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
+	cmapi "github.com/intel/edge-conductor/pkg/api/certmgr"
 	epapiplugins "github.com/intel/edge-conductor/pkg/api/plugins"
 	pluginapi "github.com/intel/edge-conductor/pkg/api/plugins"
 	wfapi "github.com/intel/edge-conductor/pkg/api/workflow"
+	certmgr "github.com/intel/edge-conductor/pkg/certmgr"
 	epplugins "github.com/intel/edge-conductor/pkg/epplugins"
 	_ "github.com/intel/edge-conductor/pkg/epplugins/file-exporter"
 	eputils "github.com/intel/edge-conductor/pkg/eputils"
@@ -154,17 +159,559 @@ func Test_Start(t *testing.T) {
 
 	epplugins.PluginList = append(epplugins.PluginList, "test")
 
-	err := Start("testErr", "localhost:100000", "./workflow/workflow.yml")
+	err := Start(context.Background(), "testErr", "localhost:100000", "./workflow/workflow.yml")
 	t.Log("workflow ret:", err)
 
-	err = Start("test", "localhost:100000", "./workflow/workflow.yml")
+	err = Start(context.Background(), "test", "localhost:100000", "./workflow/workflow.yml")
 	t.Log("workflow ret:", err)
 
-	err = Start("test", "localhost:50089", "./workflow/workflow.yml")
+	err = Start(context.Background(), "test", "localhost:50089", "./workflow/workflow.yml")
 	t.Log("workflow ret:", err)
 	t.Log("Done")
 }
 
+func Test_loadDependencies(t *testing.T) {
+	newSteps := func() []step {
+		return []step{
+			{plugin: "parser", outputs: []io{{name: "images"}, {name: "files"}}},
+			{plugin: "image-downloader", inputs: []io{{name: "images"}}},
+			{plugin: "file-downloader", inputs: []io{{name: "files"}}, outputs: []io{{name: "files"}}},
+			{plugin: "binary-download", depends: []string{"parser"}},
+			{plugin: "image-downloader", inputs: []io{{name: "images"}}},
+		}
+	}
+	cases := []struct {
+		name        string
+		parallel    bool
+		depends     []string
+		expectDeps  [][]int
+		expectError error
+	}{
+		{
+			name:       "sequential",
+			parallel:   false,
+			expectDeps: [][]int{{}, {0}, {1}, {2}, {3}},
+		},
+		{
+			name:       "parallel",
+			parallel:   true,
+			expectDeps: [][]int{{}, {0}, {0}, {0}, {0, 1}},
+		},
+		{
+			name:        "unknown depends",
+			parallel:    true,
+			depends:     []string{"unknown"},
+			expectError: eputils.GetError("errStepDepends"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{steps: newSteps(), parallel: tc.parallel}
+			if tc.depends != nil {
+				s.steps[3].depends = tc.depends
+			}
+			err := s.loadDependencies()
+			if tc.expectError != nil {
+				require.Equal(t, tc.expectError, err)
+				return
+			}
+			require.NoError(t, err)
+			for k, st := range s.steps {
+				require.Equal(t, tc.expectDeps[k], st.deps, "step %d", k)
+			}
+		})
+	}
+}
+
+func Test_runParallel(t *testing.T) {
+	j, err := loadJournal("test-parallel")
+	require.NoError(t, err)
+	defer os.RemoveAll(journalFilePath("test-parallel"))
+
+	s := &server{
+		name: "test-parallel",
+		steps: []step{
+			{plugin: "parallel-a", pending: true, deps: []int{}},
+			{plugin: "parallel-b", pending: true, deps: []int{}},
+			{plugin: "parallel-c", pending: true, deps: []int{0, 1}},
+		},
+		parallel:         true,
+		inflight:         map[string]*step{},
+		plugin_data:      eputils.SchemaMapData{},
+		plugin_dataattrs: map[string]dataAttr{},
+		data:             &wfapi.WorkflowData{},
+		journal:          j,
+	}
+	for k := range s.steps {
+		s.steps[k].finished = make(chan error)
+	}
+
+	// parallel-a and parallel-b must be connected at the same time before
+	// any of them completes.
+	var connected sync.WaitGroup
+	connected.Add(2)
+	var wg sync.WaitGroup
+	for _, name := range []string{"parallel-a", "parallel-b", "parallel-c"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			req := &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: name}}
+			if _, err := s.PluginConnect(context.Background(), req); err != nil {
+				t.Error(err)
+				return
+			}
+			if name != "parallel-c" {
+				connected.Done()
+				connected.Wait()
+			}
+			creq := &wfapi.PluginCompleteRequest{
+				Plugin:       &wfapi.Plugin{Name: name},
+				Result:       &wfapi.Result{Return: wfapi.Result_Success},
+				WorkflowData: &wfapi.WorkflowData{PluginData: []byte("{}")},
+			}
+			if _, err := s.PluginComplete(context.Background(), creq); err != nil {
+				t.Error(err)
+			}
+		}(name)
+	}
+
+	done := make(chan error)
+	go func() { done <- s.run() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("parallel steps are not run concurrently")
+	}
+	wg.Wait()
+	require.Equal(t, 3, len(s.journal.Steps))
+}
+
+// simulatePlugin connects to the server once for each result, and completes
+// the step with it. A "hang" result completes the step only after twice the
+// timeout of the step, which the server must reject.
+func simulatePlugin(t *testing.T, s *server, name string, timeout time.Duration, results []string) {
+	for _, r := range results {
+		req := &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: name}}
+		res, err := s.PluginConnect(context.Background(), req)
+		if err != nil || res.Result.Return != wfapi.ConnectResult_Connected {
+			t.Errorf("Unexpected connect result: %v, %v", res, err)
+			return
+		}
+		creq := &wfapi.PluginCompleteRequest{
+			Plugin:       &wfapi.Plugin{Name: name},
+			Result:       &wfapi.Result{Return: wfapi.Result_Success},
+			WorkflowData: &wfapi.WorkflowData{PluginData: []byte("{}")},
+		}
+		switch r {
+		case "fail":
+			creq.Result.Return = wfapi.Result_Error
+			creq.WorkflowData.PluginData = nil
+		case "hang":
+			time.Sleep(2 * timeout)
+		}
+		cres, err := s.PluginComplete(context.Background(), creq)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if r == "hang" && cres.Return != wfapi.Result_Error {
+			t.Errorf("Late completion is not rejected")
+		}
+	}
+}
+
+func Test_runStepLimits(t *testing.T) {
+	j, err := loadJournal("test-limits")
+	require.NoError(t, err)
+	defer os.RemoveAll(journalFilePath("test-limits"))
+
+	cases := []struct {
+		name         string
+		timeout      time.Duration
+		retries      int
+		results      []string
+		expectError  error
+		expectStatus string
+	}{
+		{
+			name:         "success",
+			results:      []string{"ok"},
+			expectError:  nil,
+			expectStatus: STATUS_COMPLETED,
+		},
+		{
+			name:         "fail without retry",
+			results:      []string{"fail"},
+			expectError:  eputils.GetError("errPluginComplete"),
+			expectStatus: STATUS_FAILED,
+		},
+		{
+			name:         "retry after failure",
+			retries:      2,
+			results:      []string{"fail", "fail", "ok"},
+			expectError:  nil,
+			expectStatus: STATUS_COMPLETED,
+		},
+		{
+			name:         "retries exhausted",
+			retries:      1,
+			results:      []string{"fail", "fail"},
+			expectError:  eputils.GetError("errStepExhausted"),
+			expectStatus: STATUS_FAILED,
+		},
+		{
+			name:         "retry after timeout",
+			timeout:      100 * time.Millisecond,
+			retries:      1,
+			results:      []string{"hang", "ok"},
+			expectError:  nil,
+			expectStatus: STATUS_COMPLETED,
+		},
+		{
+			name:         "plugin never connects",
+			timeout:      100 * time.Millisecond,
+			retries:      1,
+			results:      []string{},
+			expectError:  eputils.GetError("errStepExhausted"),
+			expectStatus: STATUS_FAILED,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{
+				name: "test-limits",
+				steps: []step{{
+					plugin:   "limits-a",
+					pending:  true,
+					finished: make(chan error),
+					timeout:  tc.timeout,
+					retries:  tc.retries,
+					backoff:  10 * time.Millisecond,
+				}},
+				inflight:         map[string]*step{},
+				plugin_data:      eputils.SchemaMapData{},
+				plugin_dataattrs: map[string]dataAttr{},
+				data:             &wfapi.WorkflowData{},
+				journal:          j,
+			}
+			s.report = newRunReport(s.name, s.steps)
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				simulatePlugin(t, s, "limits-a", tc.timeout, tc.results)
+			}()
+			err := s.runStep(0)
+			wg.Wait()
+			require.Equal(t, tc.expectError, err)
+			require.Equal(t, tc.expectStatus, s.report.Steps[0].Status)
+			require.Equal(t, eputils.GetErrorCode(tc.expectError), s.report.Steps[0].ErrorCode)
+		})
+	}
+}
+
+func Test_dispatchHandoff(t *testing.T) {
+	cases := []struct {
+		name        string
+		timeout     time.Duration
+		cancel      bool
+		expectError error
+	}{
+		{
+			name:        "timeout before the plugin takes the step",
+			timeout:     50 * time.Millisecond,
+			expectError: eputils.GetError("errStepExhausted"),
+		},
+		{
+			name:        "cancel before the plugin takes the step",
+			cancel:      true,
+			expectError: eputils.GetError("errWorkflowCancelled"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{
+				steps: []step{{
+					plugin:   "handoff-a",
+					pending:  true,
+					finished: make(chan error),
+					timeout:  tc.timeout,
+				}},
+				inflight: map[string]*step{},
+				data:     &wfapi.WorkflowData{},
+			}
+			s.ctx, s.cancel = context.WithCancel(context.Background())
+			defer s.cancel()
+			st := &s.steps[0]
+
+			errc := make(chan error, 1)
+			go func() {
+				errc <- s.dispatchStep(st)
+			}()
+			// The step is received, and the timeout or the cancellation
+			// fires before the plugin takes it.
+			received := <-s.pluginChannel("handoff-a")
+			if tc.cancel {
+				s.cancel()
+			}
+			select {
+			case err := <-errc:
+				require.Equal(t, tc.expectError, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("dispatchStep does not return")
+			}
+
+			// The plugin refuses the given up step, which stays pending.
+			require.Nil(t, s.takeStep("handoff-a", received))
+			require.True(t, st.pending)
+			require.Empty(t, s.inflight)
+		})
+	}
+}
+
+func Test_cancel(t *testing.T) {
+	j, err := loadJournal("test-cancel")
+	require.NoError(t, err)
+	defer os.RemoveAll(journalFilePath("test-cancel"))
+
+	cases := []struct {
+		name    string
+		connect bool
+	}{
+		{
+			name:    "cancel before the plugin connects",
+			connect: false,
+		},
+		{
+			name:    "cancel while the plugin runs",
+			connect: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{
+				name: "test-cancel",
+				steps: []step{{
+					plugin:   "cancel-a",
+					pending:  true,
+					finished: make(chan error),
+					retries:  1,
+				}},
+				inflight:         map[string]*step{},
+				plugin_data:      eputils.SchemaMapData{},
+				plugin_dataattrs: map[string]dataAttr{},
+				data:             &wfapi.WorkflowData{},
+				journal:          j,
+			}
+			s.ctx, s.cancel = context.WithCancel(context.Background())
+			s.report = newRunReport(s.name, s.steps)
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if tc.connect {
+					req := &wfapi.PluginConnectRequest{Plugin: &wfapi.Plugin{Name: "cancel-a"}}
+					res, err := s.PluginConnect(context.Background(), req)
+					if err != nil || res.Result.Return != wfapi.ConnectResult_Connected {
+						t.Errorf("Unexpected connect result: %v, %v", res, err)
+					}
+				}
+				res, err := s.Cancel(context.Background(), &wfapi.CancelRequest{Reason: "test"})
+				if err != nil || res.Return != wfapi.Result_Success {
+					t.Errorf("Unexpected cancel result: %v, %v", res, err)
+				}
+			}()
+			err := s.runStep(0)
+			wg.Wait()
+			require.Equal(t, eputils.GetError("errWorkflowCancelled"), err)
+			require.Equal(t, STATUS_CANCELLED, s.report.Steps[0].Status)
+			require.Equal(t, 1, s.report.Steps[0].Attempts)
+
+			// The late completion of the cancelled step is rejected.
+			creq := &wfapi.PluginCompleteRequest{
+				Plugin: &wfapi.Plugin{Name: "cancel-a"},
+				Result: &wfapi.Result{Return: wfapi.Result_Success},
+			}
+			cres, err := s.PluginComplete(context.Background(), creq)
+			require.NoError(t, err)
+			require.Equal(t, wfapi.Result_Error, cres.Return)
+		})
+	}
+
+	s := &server{}
+	res, err := s.Cancel(context.Background(), &wfapi.CancelRequest{})
+	require.NoError(t, err)
+	require.Equal(t, wfapi.Result_Error, res.Return)
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.cancel()
+	require.Equal(t, eputils.GetError("errWorkflowCancelled"), s.sleep(time.Hour))
+}
+
+func Test_evalWhen(t *testing.T) {
+	cases := []struct {
+		name        string
+		when        string
+		expectRun   bool
+		expectError error
+	}{
+		{
+			name:      "no expression",
+			when:      "",
+			expectRun: true,
+		},
+		{
+			name:      "escaped expression is true",
+			when:      `\{\{ eq .Kitconfig.Cluster.Provider "capi" \}\}`,
+			expectRun: true,
+		},
+		{
+			name:      "expression is false",
+			when:      `{{ eq .Kitconfig.Cluster.Provider "rke" }}`,
+			expectRun: false,
+		},
+		{
+			name:      "rendered expression",
+			when:      "false",
+			expectRun: false,
+		},
+		{
+			name:        "not a boolean",
+			when:        "{{ .Kitconfig.Cluster.Provider }}",
+			expectError: eputils.GetError("errStepWhen"),
+		},
+		{
+			name:        "invalid expression",
+			when:        "{{ eq .Kitconfig.Cluster.Provider ",
+			expectError: eputils.GetError("errStepWhen"),
+		},
+	}
+
+	s := &server{data: &wfapi.WorkflowData{Data: []byte(`{"kitconfig": {"Cluster": {"provider": "capi"}}}`)}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			run, err := s.evalWhen(tc.when)
+			require.Equal(t, tc.expectError, err)
+			require.Equal(t, tc.expectRun, run)
+		})
+	}
+}
+
+func Test_runStepWhen(t *testing.T) {
+	s := &server{
+		name: "test-when",
+		steps: []step{{
+			plugin:   "when-a",
+			pending:  true,
+			finished: make(chan error),
+			when:     "{{ eq .Kitconfig.Cluster.Provider \"rke\" }}",
+		}},
+		data: &wfapi.WorkflowData{Data: []byte(`{"kitconfig": {"Cluster": {"provider": "capi"}}}`)},
+	}
+	s.report = newRunReport(s.name, s.steps)
+	require.NoError(t, s.runSteps())
+	require.Equal(t, STATUS_SKIPPED, s.report.Steps[0].Status)
+}
+
+func Test_loadLimits(t *testing.T) {
+	retries := int64(3)
+	cases := []struct {
+		name        string
+		step        wfapi.WorkflowSpecWorkflowsItems0StepsItems0
+		expect      step
+		expectError error
+	}{
+		{
+			name:   "no limits",
+			step:   wfapi.WorkflowSpecWorkflowsItems0StepsItems0{Name: "a"},
+			expect: step{},
+		},
+		{
+			name:   "all limits",
+			step:   wfapi.WorkflowSpecWorkflowsItems0StepsItems0{Name: "a", Timeout: "1m", Retries: &retries, Backoff: "5s"},
+			expect: step{timeout: time.Minute, retries: 3, backoff: 5 * time.Second},
+		},
+		{
+			name:        "invalid timeout",
+			step:        wfapi.WorkflowSpecWorkflowsItems0StepsItems0{Name: "a", Timeout: "forever"},
+			expectError: eputils.GetError("errStepLimits"),
+		},
+		{
+			name:        "negative backoff",
+			step:        wfapi.WorkflowSpecWorkflowsItems0StepsItems0{Name: "a", Backoff: "-1s"},
+			expectError: eputils.GetError("errStepLimits"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := step{}
+			err := st.loadLimits(&tc.step)
+			require.Equal(t, tc.expectError, err)
+			if err == nil {
+				require.Equal(t, tc.expect, st)
+			}
+		})
+	}
+}
+
+func Test_runCommand(t *testing.T) {
+	patch, err := mpatch.PatchMethod(certmgr.GetCertBundleByName, func(cname string, ctype string) (*cmapi.Certificate, certmgr.CertType, error) {
+		return &cmapi.Certificate{
+			Ca:     &cmapi.CertificateCa{Cert: "ca.pem"},
+			Client: &cmapi.CertificateClient{Cert: "client.pem", Key: "client-key.pem"},
+		}, certmgr.CLIENTCERT, nil
+	})
+	require.NoError(t, err)
+	defer unpatch(t, patch)
+
+	cases := []struct {
+		name             string
+		command          string
+		args             []string
+		expectStartError error
+		expectExitError  error
+	}{
+		{
+			name:    "plugin environment",
+			command: "/bin/sh",
+			args: []string{"-c", `test "$EC_PLUGIN_NAME" = command-a && test "$EC_WORKFLOW_ADDRESS" = 127.0.0.1:50088 &&
+				test "$EC_WORKFLOW_CA" = ca.pem && test "$EC_WORKFLOW_CERT" = client.pem && test "$EC_WORKFLOW_KEY" = client-key.pem`},
+			expectExitError: nil,
+		},
+		{
+			name:            "plugin fails",
+			command:         "/bin/sh",
+			args:            []string{"-c", "exit 1"},
+			expectExitError: eputils.GetError("errPluginCommand"),
+		},
+		{
+			name:             "command not found",
+			command:          "./no-such-plugin",
+			expectStartError: eputils.GetError("errPluginCommand"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &server{
+				address: "127.0.0.1:50088",
+				errch:   make(chan error),
+			}
+			err := s.run_command("command-a", tc.command, tc.args)
+			require.Equal(t, tc.expectStartError, err)
+			if err == nil {
+				require.Equal(t, tc.expectExitError, <-s.errch)
+			}
+		})
+	}
+}
+
 func unpatch(t *testing.T, m *mpatch.Patch) {
 	err := m.Unpatch()
 	if err != nil {